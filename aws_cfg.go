@@ -1,8 +1,6 @@
 package xaws
 
 import (
-	"context"
-
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -10,18 +8,18 @@ import (
 
 const (
 	_defaultTimeoutSecs = 60
+
+	_defaultRoleSessionName = "xaws"
 )
 
 func NewAwsConfig(ak, sk, region string) (aws.Config, error) {
-	ctx := context.TODO()
 	prov := credentials.StaticCredentialsProvider{
 		Value: aws.Credentials{
 			AccessKeyID:     ak,
 			SecretAccessKey: sk,
 		},
 	}
-	cfg, err := config.LoadDefaultConfig(
-		ctx,
+	cfg, err := loadDefaultConfig(
 		config.WithRegion(region),
 		config.WithCredentialsProvider(prov),
 	)
@@ -29,6 +27,27 @@ func NewAwsConfig(ak, sk, region string) (aws.Config, error) {
 	return cfg, err
 }
 
+// NewAwsConfigFromProfile loads config of the named profile in ~/.aws/config and ~/.aws/credentials,
+// it works with profiles logged in by `aws sso login`, empty region uses the region of profile.
+func NewAwsConfigFromProfile(profile, region string) (aws.Config, error) {
+	optFns := []func(*config.LoadOptions) error{
+		config.WithSharedConfigProfile(profile),
+	}
+
+	if region != "" {
+		optFns = append(optFns, config.WithRegion(region))
+	}
+
+	return loadDefaultConfig(optFns...)
+}
+
+func MustNewAwsConfigFromProfile(profile, region string) aws.Config {
+	cfg, err := NewAwsConfigFromProfile(profile, region)
+	panicIfErr(err)
+
+	return cfg
+}
+
 // NewConfigWithSecret will use ~/.aws/credentials to load secret from secrets manager,
 // then use the loaded secret to work on aws resources
 func NewConfigWithSecret(secretName string) (aws.Config, error) {
@@ -54,20 +73,28 @@ func NewConfigFromSecretWithAkSk(ak, sk, secretName string) (aws.Config, error)
 }
 
 func newConfigWithSecret(raw string) (aws.Config, error) {
-	var (
-		cfg aws.Config
-		err error
-	)
-
 	auth, err := SecretToAuth(raw)
 	if err != nil {
-		return cfg, err
+		return aws.Config{}, err
 	}
 
-	cfg, err = NewAwsConfig(auth.AwsAccessKeyID, auth.AwsSecretAccessKey, "")
+	cfg, err := NewAwsConfig(auth.AwsAccessKeyID, auth.AwsSecretAccessKey, auth.Region)
 	if err != nil {
-		panic(err)
+		return aws.Config{}, err
 	}
 
-	return cfg, nil
+	if auth.RoleArn == "" {
+		return cfg, nil
+	}
+
+	return NewAwsConfigWithAssumeRole(auth.RoleArn, _defaultRoleSessionName, auth.Region, WithBaseConfig(cfg))
+}
+
+// WithEndpoint returns a copy of cfg whose clients call endpoint instead of the aws endpoints,
+// e.g. dynamodb-local, LocalStack or minio.
+func WithEndpoint(cfg aws.Config, endpoint string) aws.Config {
+	cfg = cfg.Copy()
+	cfg.BaseEndpoint = aws.String(endpoint)
+
+	return cfg
 }
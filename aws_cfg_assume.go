@@ -0,0 +1,153 @@
+package xaws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+type AssumeRoleOpts struct {
+	externalID    string
+	mfaSerial     string
+	tokenProvider func() (string, error)
+	duration      time.Duration
+	baseConfig    *aws.Config
+
+	sessionPolicy any
+	policyArns    []string
+}
+
+type AssumeRoleOptFunc func(o *AssumeRoleOpts)
+
+func bindAssumeRoleOpts(opt *AssumeRoleOpts, opts ...AssumeRoleOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithExternalID sets the external id required by the trust policy of role.
+func WithExternalID(id string) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.externalID = id
+	}
+}
+
+// WithMFA sets the serial number of the MFA device, tokenProvider returns the current code,
+// nil tokenProvider reads the code from stdin.
+func WithMFA(serial string, tokenProvider func() (string, error)) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.mfaSerial = serial
+		o.tokenProvider = tokenProvider
+	}
+}
+
+// WithRoleDuration sets how long the assumed credentials are valid, default is 15 minutes.
+func WithRoleDuration(d time.Duration) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.duration = d
+	}
+}
+
+// WithBaseConfig sets the config used to call sts, the default config is used if not set.
+func WithBaseConfig(cfg aws.Config) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.baseConfig = &cfg
+	}
+}
+
+// WithSessionPolicy sets an inline session policy, the assumed credentials get the intersection
+// of role policies and it, policy is marshaled to JSON, string and []byte are sent as is.
+func WithSessionPolicy(policy any) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.sessionPolicy = policy
+	}
+}
+
+// WithSessionPolicyArns sets managed policies used as session policies, see WithSessionPolicy.
+func WithSessionPolicyArns(arns ...string) AssumeRoleOptFunc {
+	return func(o *AssumeRoleOpts) {
+		o.policyArns = arns
+	}
+}
+
+// NewAwsConfigWithAssumeRole creates config whose credentials are obtained by assuming roleArn,
+// the credentials are cached and refreshed before they expire.
+//
+// Usage:
+//
+//	cfg, err := NewAwsConfigWithAssumeRole(
+//		"arn:aws:iam::123456789012:role/deployer", "xaws", "us-west-2",
+//		WithExternalID("partner-id"),
+//	)
+//	w := NewSecretsWrapper(cfg)
+func NewAwsConfigWithAssumeRole(roleArn, sessionName, region string, opts ...AssumeRoleOptFunc) (aws.Config, error) {
+	opt := &AssumeRoleOpts{}
+	bindAssumeRoleOpts(opt, opts...)
+
+	var policy string
+
+	if opt.sessionPolicy != nil {
+		p, err := marshalPolicy(opt.sessionPolicy)
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("cannot marshal session policy: %w", err)
+		}
+
+		policy = p
+	}
+
+	var base aws.Config
+
+	if opt.baseConfig != nil {
+		base = opt.baseConfig.Copy()
+	} else {
+		cfg, err := loadDefaultConfig(config.WithRegion(region))
+		if err != nil {
+			return aws.Config{}, err
+		}
+
+		base = cfg
+	}
+
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), roleArn, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+
+		if opt.externalID != "" {
+			o.ExternalID = aws.String(opt.externalID)
+		}
+
+		if opt.mfaSerial != "" {
+			o.SerialNumber = aws.String(opt.mfaSerial)
+			o.TokenProvider = opt.tokenProvider
+
+			if o.TokenProvider == nil {
+				o.TokenProvider = stscreds.StdinTokenProvider
+			}
+		}
+
+		if opt.duration > 0 {
+			o.Duration = opt.duration
+		}
+
+		if policy != "" {
+			o.Policy = aws.String(policy)
+		}
+
+		for _, arn := range opt.policyArns {
+			o.PolicyARNs = append(o.PolicyARNs, types.PolicyDescriptorType{Arn: aws.String(arn)})
+		}
+	})
+
+	cfg := base.Copy()
+	cfg.Credentials = aws.NewCredentialsCache(provider)
+
+	if region != "" {
+		cfg.Region = region
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,78 @@
+package xaws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClientOpts configures a wrapper and the sdk clients created by its constructor.
+type ClientOpts struct {
+	retryPolicy *RetryPolicy
+	logger      Logger
+	telemetry   *telemetry
+}
+
+type ClientOptFunc func(o *ClientOpts)
+
+func bindClientOpts(opt *ClientOpts, opts ...ClientOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// newClientOpts returns the ClientOpts of opts.
+func newClientOpts(opts ...ClientOptFunc) ClientOpts {
+	opt := ClientOpts{}
+	bindClientOpts(&opt, opts...)
+
+	return opt
+}
+
+// WithRetryPolicy makes the clients of a wrapper retry with p, instead of the retryer of its config.
+func WithRetryPolicy(p RetryPolicy) ClientOptFunc {
+	return func(o *ClientOpts) {
+		o.retryPolicy = &p
+	}
+}
+
+// WithWrapperLogger sets the logger of the wrapper, like its SetLogger, nil uses the default logger.
+func WithWrapperLogger(l Logger) ClientOptFunc {
+	return func(o *ClientOpts) {
+		o.logger = l
+	}
+}
+
+// WithTelemetry makes the clients of a wrapper trace every aws call with tp,
+// and record call counts and latency with mp, nil tp or mp disables the respective signal.
+//
+// Spans are named "<service>.<operation>", and carry the bucket/queue/table/function name of the call.
+//
+// Usage:
+//
+//	tel := WithTelemetry(otel.GetTracerProvider(), otel.GetMeterProvider())
+//	sqs := NewSqsClient(queue, cfg, 10, 60, tel)
+//	s3 := NewS3Wrapper(bucket, cfg, WithClientOpts(tel))
+func WithTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) ClientOptFunc {
+	return func(o *ClientOpts) {
+		o.telemetry = newTelemetry(tp, mp)
+	}
+}
+
+// applyClientOpts returns cfg with opts applied.
+func applyClientOpts(cfg aws.Config, opts ...ClientOptFunc) aws.Config {
+	return newClientOpts(opts...).apply(cfg)
+}
+
+// apply returns a copy of cfg with the retry policy and telemetry of o, or cfg as is if neither is set.
+func (o ClientOpts) apply(cfg aws.Config) aws.Config {
+	if o.retryPolicy != nil {
+		cfg = o.retryPolicy.Apply(cfg)
+	}
+
+	if o.telemetry != nil {
+		cfg = o.telemetry.apply(cfg)
+	}
+
+	return cfg
+}
@@ -0,0 +1,132 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+const (
+	// _putMetricDataMaxCount is the max datums of a PutMetricData call.
+	_putMetricDataMaxCount = 1000
+)
+
+type CloudWatchWrapper struct {
+	client *cloudwatch.Client
+
+	logger Logger
+}
+
+func NewCloudWatchWrapper(cfg aws.Config, opts ...ClientOptFunc) *CloudWatchWrapper {
+	opt := newClientOpts(opts...)
+
+	return &CloudWatchWrapper{
+		client: cloudwatch.NewFromConfig(opt.apply(cfg)),
+		logger: opt.logger,
+	}
+}
+
+func NewCloudWatchWrapperWithDefaultConfig(opts ...ClientOptFunc) (*CloudWatchWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCloudWatchWrapper(cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *CloudWatchWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *CloudWatchWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// Metric is a datapoint of a custom metric.
+type Metric struct {
+	Name  string
+	Value float64
+	// Unit defaults to None.
+	Unit       types.StandardUnit
+	Dimensions map[string]string
+	// Timestamp defaults to the time the metric is put.
+	Timestamp time.Time
+}
+
+// PutMetric puts a single datapoint of metric name to namespace.
+//
+// Usage:
+//
+//	err := w.PutMetric("scraper", "pages", 12, map[string]string{"site": "example.com"})
+func (w *CloudWatchWrapper) PutMetric(namespace, name string, value float64, dimensions map[string]string) error {
+	return w.PutMetrics(namespace, Metric{Name: name, Value: value, Dimensions: dimensions})
+}
+
+// PutMetrics puts metrics to namespace, in batches of 1000.
+func (w *CloudWatchWrapper) PutMetrics(namespace string, metrics ...Metric) error {
+	data := make([]types.MetricDatum, 0, len(metrics))
+	for _, m := range metrics {
+		data = append(data, m.toDatum())
+	}
+
+	return w.putMetricData(namespace, data)
+}
+
+func (w *CloudWatchWrapper) putMetricData(namespace string, data []types.MetricDatum) error {
+	for start := 0; start < len(data); start += _putMetricDataMaxCount {
+		end := min(start+_putMetricDataMaxCount, len(data))
+
+		_, err := w.client.PutMetricData(context.TODO(), &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(namespace),
+			MetricData: data[start:end],
+		})
+		if err != nil {
+			return fmt.Errorf("cannot put metric data to %s: %w", namespace, err)
+		}
+	}
+
+	return nil
+}
+
+func (m Metric) toDatum() types.MetricDatum {
+	datum := types.MetricDatum{
+		MetricName: aws.String(m.Name),
+		Value:      aws.Float64(m.Value),
+		Unit:       m.Unit,
+		Dimensions: toDimensions(m.Dimensions),
+	}
+
+	if !m.Timestamp.IsZero() {
+		datum.Timestamp = aws.Time(m.Timestamp)
+	}
+
+	return datum
+}
+
+// toDimensions converts m to dimensions sorted by name.
+func toDimensions(m map[string]string) []types.Dimension {
+	if len(m) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	dims := make([]types.Dimension, 0, len(names))
+	for _, name := range names {
+		dims = append(dims, types.Dimension{Name: aws.String(name), Value: aws.String(m[name])})
+	}
+
+	return dims
+}
@@ -0,0 +1,215 @@
+package xaws
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+const (
+	_defaultEmitInterval = time.Minute
+	// _emitterMaxPending is the buffered datapoints that trigger a flush before the interval.
+	_emitterMaxPending = 1000
+)
+
+// MetricEmitter buffers datapoints and puts them asynchronously, datapoints of the same metric,
+// dimensions and unit are aggregated into a statistic set, it is safe for concurrent use.
+//
+// Pending datapoints are flushed every interval, or once 1000 datapoints are buffered,
+// errors of a flush are logged and the datapoints are dropped.
+//
+// Usage:
+//
+//	emitter := w.NewEmitter("scraper", time.Minute)
+//	defer emitter.Close()
+//
+//	emitter.Add("pages", 1, map[string]string{"site": site})
+type MetricEmitter struct {
+	namespace string
+	put       func(namespace string, data []types.MetricDatum) error
+
+	mu      sync.Mutex
+	series  map[string]*metricSeries
+	pending int
+
+	flushCh   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	logger Logger
+}
+
+type metricSeries struct {
+	name       string
+	unit       types.StandardUnit
+	dimensions []types.Dimension
+
+	count, sum, min, max float64
+}
+
+// NewEmitter creates an emitter of namespace flushing every interval, interval <= 0 uses 1 minute.
+func (w *CloudWatchWrapper) NewEmitter(namespace string, interval time.Duration) *MetricEmitter {
+	e := newMetricEmitter(namespace, interval, w.putMetricData)
+	e.logger = w.logger
+
+	return e
+}
+
+func newMetricEmitter(namespace string, interval time.Duration, put func(string, []types.MetricDatum) error) *MetricEmitter {
+	if interval <= 0 {
+		interval = _defaultEmitInterval
+	}
+
+	e := &MetricEmitter{
+		namespace: namespace,
+		put:       put,
+		series:    make(map[string]*metricSeries),
+		flushCh:   make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	e.wg.Add(1)
+
+	go e.loop(interval)
+
+	return e
+}
+
+// SetLogger sets the logger of emitter, nil falls back to the default logger.
+func (e *MetricEmitter) SetLogger(l Logger) {
+	e.logger = l
+}
+
+func (e *MetricEmitter) log() Logger {
+	return orDefaultLogger(e.logger)
+}
+
+// Add buffers a datapoint of metric name without unit.
+func (e *MetricEmitter) Add(name string, value float64, dimensions map[string]string) {
+	e.AddMetric(Metric{Name: name, Value: value, Dimensions: dimensions})
+}
+
+// AddMetric buffers m, its Timestamp is ignored, the flush time is used instead.
+func (e *MetricEmitter) AddMetric(m Metric) {
+	dims := toDimensions(m.Dimensions)
+	key := seriesKey(m.Name, m.Unit, dims)
+
+	e.mu.Lock()
+
+	s, ok := e.series[key]
+	if !ok {
+		s = &metricSeries{name: m.Name, unit: m.Unit, dimensions: dims, min: m.Value, max: m.Value}
+		e.series[key] = s
+	}
+
+	s.count++
+	s.sum += m.Value
+	s.min = min(s.min, m.Value)
+	s.max = max(s.max, m.Value)
+
+	e.pending++
+	full := e.pending >= _emitterMaxPending
+
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush puts pending datapoints now.
+func (e *MetricEmitter) Flush() error {
+	e.mu.Lock()
+	series := e.series
+	e.series = make(map[string]*metricSeries)
+	e.pending = 0
+	e.mu.Unlock()
+
+	if len(series) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(series))
+	for k := range series {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	now := time.Now()
+	data := make([]types.MetricDatum, 0, len(series))
+
+	for _, k := range keys {
+		s := series[k]
+		data = append(data, types.MetricDatum{
+			MetricName: aws.String(s.name),
+			Unit:       s.unit,
+			Dimensions: s.dimensions,
+			Timestamp:  aws.Time(now),
+			StatisticValues: &types.StatisticSet{
+				SampleCount: aws.Float64(s.count),
+				Sum:         aws.Float64(s.sum),
+				Minimum:     aws.Float64(s.min),
+				Maximum:     aws.Float64(s.max),
+			},
+		})
+	}
+
+	return e.put(e.namespace, data)
+}
+
+// Close stops the emitter and flushes pending datapoints, Add after Close is never flushed.
+func (e *MetricEmitter) Close() error {
+	e.closeOnce.Do(func() {
+		close(e.done)
+	})
+
+	e.wg.Wait()
+
+	return e.Flush()
+}
+
+func (e *MetricEmitter) loop(interval time.Duration) {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		case <-e.flushCh:
+		}
+
+		if err := e.Flush(); err != nil {
+			e.log().Error("cannot flush metrics", "namespace", e.namespace, "error", err)
+		}
+	}
+}
+
+func seriesKey(name string, unit types.StandardUnit, dims []types.Dimension) string {
+	var b strings.Builder
+
+	b.WriteString(name)
+	b.WriteString("\x00")
+	b.WriteString(string(unit))
+
+	for _, d := range dims {
+		b.WriteString("\x00")
+		b.WriteString(aws.ToString(d.Name))
+		b.WriteString("=")
+		b.WriteString(aws.ToString(d.Value))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package xaws
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type CloudWatchSuite struct {
+	suite.Suite
+}
+
+func TestCloudWatch(t *testing.T) {
+	suite.Run(t, new(CloudWatchSuite))
+}
+
+func (s *CloudWatchSuite) Test_01_emitter() {
+	var (
+		mu  sync.Mutex
+		got []types.MetricDatum
+	)
+
+	e := newMetricEmitter("test", time.Hour, func(_ string, data []types.MetricDatum) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		got = append(got, data...)
+
+		return nil
+	})
+
+	e.Add("pages", 1, map[string]string{"site": "a"})
+	e.Add("pages", 3, map[string]string{"site": "a"})
+	e.Add("pages", 5, map[string]string{"site": "b"})
+
+	s.Nil(e.Close())
+	s.Len(got, 2)
+
+	stats := got[0].StatisticValues
+	s.Equal(2.0, aws.ToFloat64(stats.SampleCount))
+	s.Equal(4.0, aws.ToFloat64(stats.Sum))
+	s.Equal(1.0, aws.ToFloat64(stats.Minimum))
+	s.Equal(3.0, aws.ToFloat64(stats.Maximum))
+}
+
+func (s *CloudWatchSuite) Test_02_emitterFlushOnFull() {
+	flushed := make(chan int, 10)
+
+	e := newMetricEmitter("test", time.Hour, func(_ string, data []types.MetricDatum) error {
+		flushed <- len(data)
+		return nil
+	})
+	defer e.Close()
+
+	for i := 0; i < _emitterMaxPending; i++ {
+		e.Add("pages", 1, nil)
+	}
+
+	select {
+	case n := <-flushed:
+		s.Equal(1, n)
+	case <-time.After(time.Second):
+		s.Fail("not flushed")
+	}
+}
@@ -0,0 +1,98 @@
+package xaws
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DryRunRecord is an action skipped by a wrapper in dry-run mode,
+// it can be captured by a DryRunHook to build plan/preview output.
+type DryRunRecord struct {
+	Service string
+	Action  string
+	// Target is the resource the action applies to, e.g. the function name.
+	Target string
+	// Input is the request which would be sent, usually the sdk input struct.
+	Input any
+	At    time.Time
+}
+
+// DryRunHook receives every action skipped in dry-run mode.
+type DryRunHook func(rec DryRunRecord)
+
+// dryRunSummary formats input for the dry-run log, pointer fields are dereferenced, zero fields are skipped,
+// and byte slices (e.g. a zip file) are reported by length, e.g.
+// "{FunctionName:my-func Code:{ZipFile:<1024 bytes>} Runtime:provided.al2}".
+func dryRunSummary(input any) string {
+	if raw, ok := input.(string); ok {
+		return raw
+	}
+
+	var b strings.Builder
+	writeDryRunValue(&b, reflect.ValueOf(input))
+
+	return b.String()
+}
+
+func writeDryRunValue(b *strings.Builder, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			b.WriteString("<nil>")
+			return
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			b.WriteString(s.String())
+			return
+		}
+
+		b.WriteString("{")
+
+		n := 0
+
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+
+			if n > 0 {
+				b.WriteString(" ")
+			}
+
+			n++
+
+			b.WriteString(v.Type().Field(i).Name + ":")
+			writeDryRunValue(b, v.Field(i))
+		}
+
+		b.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			fmt.Fprintf(b, "<%d bytes>", v.Len())
+			return
+		}
+
+		b.WriteString("[")
+
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+
+			writeDryRunValue(b, v.Index(i))
+		}
+
+		b.WriteString("]")
+	case reflect.Invalid:
+		b.WriteString("<nil>")
+	default:
+		fmt.Fprintf(b, "%v", v.Interface())
+	}
+}
@@ -2,10 +2,10 @@ package xaws
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -21,6 +21,8 @@ const (
 type DynamodbWrapper struct {
 	Config aws.Config
 	Client *dynamodb.Client
+	// sdk is the client calls are made with, Client or the DynamodbSdkClient given to NewDynamodbWrapperWithClient.
+	sdk    DynamodbSdkClient
 	DdbCtx context.Context
 
 	readCapacity  int
@@ -28,23 +30,60 @@ type DynamodbWrapper struct {
 
 	TableName string
 
+	// key schema of the table, see keySchema.
+	partitionKey string
+	sortKey      string
+
 	Timeout int
+
+	logger Logger
 }
 
-func NewDynamodbWrapper(table string, config aws.Config, readCapacity, writeCapacity int) *DynamodbWrapper {
+func NewDynamodbWrapper(table string, config aws.Config, readCapacity, writeCapacity int, opts ...ClientOptFunc) *DynamodbWrapper {
+	w := NewDynamodbWrapperWithClient(table, dynamodb.NewFromConfig(applyClientOpts(config, opts...)), readCapacity, writeCapacity, opts...)
+	w.Config = config
+
+	return w
+}
+
+// NewDynamodbWrapperWithClient creates wrapper with client, e.g. a mocked DynamodbSdkClient,
+// Client is only set if client is a *dynamodb.Client, only the logger of opts is used.
+func NewDynamodbWrapperWithClient(table string, client DynamodbSdkClient, readCapacity, writeCapacity int, opts ...ClientOptFunc) *DynamodbWrapper {
+	concrete, _ := client.(*dynamodb.Client)
+
 	return &DynamodbWrapper{
-		Config:    config,
-		Client:    dynamodb.NewFromConfig(config),
+		Client:    concrete,
+		sdk:       client,
 		DdbCtx:    context.TODO(),
 		TableName: table,
 
 		readCapacity:  readCapacity,
 		writeCapacity: writeCapacity,
+
+		logger: newClientOpts(opts...).logger,
 	}
 }
 
-func NewDynamodbWrapperWithDefault(table string) (*DynamodbWrapper, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *DynamodbWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *DynamodbWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// api returns the client calls are made with, Client if the wrapper is not created by a constructor.
+func (w *DynamodbWrapper) api() DynamodbSdkClient {
+	if w.sdk != nil {
+		return w.sdk
+	}
+
+	return w.Client
+}
+
+func NewDynamodbWrapperWithDefault(table string, opts ...ClientOptFunc) (*DynamodbWrapper, error) {
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -53,14 +92,14 @@ func NewDynamodbWrapperWithDefault(table string) (*DynamodbWrapper, error) {
 		dftCap = 5 // value when create a table with default settings.
 	)
 
-	return NewDynamodbWrapper(table, cfg, dftCap, dftCap), nil
+	return NewDynamodbWrapper(table, cfg, dftCap, dftCap, opts...), nil
 }
 
 // TableExists determines whether a DynamoDB table exists.
 func (w *DynamodbWrapper) TableExists() (bool, error) {
 	exists := true
 
-	_, err := w.Client.DescribeTable(w.DdbCtx, &dynamodb.DescribeTableInput{TableName: aws.String(w.TableName)})
+	_, err := w.api().DescribeTable(w.DdbCtx, &dynamodb.DescribeTableInput{TableName: aws.String(w.TableName)})
 	if err != nil {
 		exists = false
 	}
@@ -72,7 +111,7 @@ func (w *DynamodbWrapper) TableExists() (bool, error) {
 func (w *DynamodbWrapper) ListTables() ([]string, error) {
 	var tableNames []string
 
-	tables, err := w.Client.ListTables(w.DdbCtx, &dynamodb.ListTablesInput{})
+	tables, err := w.api().ListTables(w.DdbCtx, &dynamodb.ListTablesInput{})
 	if err == nil {
 		tableNames = tables.TableNames
 	}
@@ -80,7 +119,22 @@ func (w *DynamodbWrapper) ListTables() ([]string, error) {
 	return tableNames, err
 }
 
-func (w *DynamodbWrapper) BuildTableInput(primaryKey string, sortKey string, skType types.ScalarAttributeType) *dynamodb.CreateTableInput {
+// BuildTableInput builds the input of CreateTable with primaryKey(string) and an optional sortKey.
+//
+// Available Options:
+//   - AddGSI(IndexKey): adds a global secondary index.
+//   - AddLSI(IndexKey): adds a local secondary index.
+//   - WithBillingMode(mode): provisioned (default) or on-demand.
+//
+// Usage:
+//
+//	input := w.BuildTableInput("id", "created_at", TypeN,
+//		AddGSI(IndexKey{Name: "by-user", PartitionKey: "user_id", PartitionKeyType: TypeS}),
+//	)
+func (w *DynamodbWrapper) BuildTableInput(primaryKey string, sortKey string, skType types.ScalarAttributeType, opts ...TableOptFunc) *dynamodb.CreateTableInput {
+	opt := &TableOpts{}
+	bindTableOpts(opt, opts...)
+
 	ads := []types.AttributeDefinition{{
 		AttributeName: aws.String(primaryKey),
 		AttributeType: types.ScalarAttributeTypeS,
@@ -102,23 +156,97 @@ func (w *DynamodbWrapper) BuildTableInput(primaryKey string, sortKey string, skT
 		})
 	}
 
+	throughput := &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(int64(w.readCapacity)),
+		WriteCapacityUnits: aws.Int64(int64(w.writeCapacity)),
+	}
+
+	addDef := func(name string, typ types.ScalarAttributeType) {
+		for _, ad := range ads {
+			if aws.ToString(ad.AttributeName) == name {
+				return
+			}
+		}
+
+		ads = append(ads, types.AttributeDefinition{AttributeName: aws.String(name), AttributeType: typ})
+	}
+
+	indexSchema := func(index IndexKey) []types.KeySchemaElement {
+		addDef(index.PartitionKey, index.PartitionKeyType)
+		schema := []types.KeySchemaElement{{AttributeName: aws.String(index.PartitionKey), KeyType: types.KeyTypeHash}}
+
+		if index.SortKey != "" {
+			addDef(index.SortKey, index.SortKeyType)
+			schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(index.SortKey), KeyType: types.KeyTypeRange})
+		}
+
+		return schema
+	}
+
+	var (
+		gsis []types.GlobalSecondaryIndex
+		lsis []types.LocalSecondaryIndex
+	)
+
+	for _, index := range opt.gsis {
+		gsis = append(gsis, types.GlobalSecondaryIndex{
+			IndexName:             aws.String(index.Name),
+			KeySchema:             indexSchema(index),
+			Projection:            &types.Projection{ProjectionType: types.ProjectionTypeAll},
+			ProvisionedThroughput: throughput,
+		})
+	}
+
+	for _, index := range opt.lsis {
+		index.PartitionKey, index.PartitionKeyType = primaryKey, types.ScalarAttributeTypeS
+		lsis = append(lsis, types.LocalSecondaryIndex{
+			IndexName:  aws.String(index.Name),
+			KeySchema:  indexSchema(index),
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+
 	input := &dynamodb.CreateTableInput{
-		AttributeDefinitions: ads,
-		KeySchema:            kss,
-		TableName:            aws.String(w.TableName),
-		ProvisionedThroughput: &types.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(int64(w.readCapacity)),
-			WriteCapacityUnits: aws.Int64(int64(w.writeCapacity)),
-		},
+		AttributeDefinitions:   ads,
+		KeySchema:              kss,
+		TableName:              aws.String(w.TableName),
+		ProvisionedThroughput:  throughput,
+		GlobalSecondaryIndexes: gsis,
+		LocalSecondaryIndexes:  lsis,
 	}
+	applyBillingMode(input, opt.billingMode)
 
 	return input
 }
 
-func (w *DynamodbWrapper) CreateTable(tableInput *dynamodb.CreateTableInput) (*types.TableDescription, error) {
+// applyBillingMode sets billing mode of input, throughput is removed for on-demand tables.
+func applyBillingMode(input *dynamodb.CreateTableInput, mode types.BillingMode) {
+	if mode == "" {
+		return
+	}
+
+	input.BillingMode = mode
+
+	if mode != types.BillingModePayPerRequest {
+		return
+	}
+
+	input.ProvisionedThroughput = nil
+	for i := range input.GlobalSecondaryIndexes {
+		input.GlobalSecondaryIndexes[i].ProvisionedThroughput = nil
+	}
+}
+
+// CreateTable creates table with tableInput and waits until it is active,
+// WithBillingMode overrides the billing mode of tableInput.
+func (w *DynamodbWrapper) CreateTable(tableInput *dynamodb.CreateTableInput, opts ...TableOptFunc) (*types.TableDescription, error) {
+	opt := &TableOpts{}
+	bindTableOpts(opt, opts...)
+
 	tableInput.TableName = aws.String(w.TableName)
+	applyBillingMode(tableInput, opt.billingMode)
 
-	table, err := w.Client.CreateTable(
+	table, err := w.api().CreateTable(
 		w.DdbCtx,
 		tableInput,
 	)
@@ -127,7 +255,7 @@ func (w *DynamodbWrapper) CreateTable(tableInput *dynamodb.CreateTableInput) (*t
 	}
 
 	longTo := 5
-	waiter := dynamodb.NewTableExistsWaiter(w.Client)
+	waiter := dynamodb.NewTableExistsWaiter(w.api())
 
 	if err := waiter.Wait(
 		w.DdbCtx,
@@ -139,23 +267,37 @@ func (w *DynamodbWrapper) CreateTable(tableInput *dynamodb.CreateTableInput) (*t
 		return nil, err
 	}
 
+	w.partitionKey, w.sortKey = keySchemaNames(tableInput.KeySchema)
+
 	return table.TableDescription, err
 }
 
+// PutItem marshals data with attributevalue.MarshalMap and puts it into the table.
 func (w *DynamodbWrapper) PutItem(data interface{}) error {
 	item, err := attributevalue.MarshalMap(data)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("cannot marshal item: %w", err)
 	}
 
-	_, err = w.Client.PutItem(w.DdbCtx, &dynamodb.PutItemInput{
+	_, err = w.api().PutItem(w.DdbCtx, &dynamodb.PutItemInput{
 		TableName: aws.String(w.TableName), Item: item,
 	})
 
 	return err
 }
 
-func (w *DynamodbWrapper) AddItemBatch(data []types.WriteRequest) (int, error) {
+func (w *DynamodbWrapper) MustPutItem(data interface{}) {
+	err := w.PutItem(data)
+	panicIfErr(err)
+}
+
+// AddItemBatch writes data with BatchWriteItem in batches of 10, WithWriteBudget is supported.
+func (w *DynamodbWrapper) AddItemBatch(data []types.WriteRequest, opts ...DdbOptFunc) (int, error) {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	throttle := newWriteThrottle(opt.writeBudget)
+
 	var err error
 
 	written := 0
@@ -173,16 +315,21 @@ func (w *DynamodbWrapper) AddItemBatch(data []types.WriteRequest) (int, error) {
 
 		wrArr = data[start:end]
 
-		_, err = w.Client.BatchWriteItem(
+		var resp *dynamodb.BatchWriteItemOutput
+
+		resp, err = w.api().BatchWriteItem(
 			w.DdbCtx,
 			&dynamodb.BatchWriteItemInput{
-				RequestItems: map[string][]types.WriteRequest{w.TableName: wrArr},
+				RequestItems:           map[string][]types.WriteRequest{w.TableName: wrArr},
+				ReturnConsumedCapacity: throttle.returnConsumedCapacity(),
 			},
 		)
 		if err != nil {
 			return 0, err
 		}
 
+		throttle.wait(resp.ConsumedCapacity)
+
 		written += len(wrArr)
 		start = end
 		end += batchSize
@@ -191,6 +338,95 @@ func (w *DynamodbWrapper) AddItemBatch(data []types.WriteRequest) (int, error) {
 	return written, err
 }
 
+// UpdateItem updates attributes of the item with key, the item is created if not exists.
+//
+// Parameters:
+//   - key: primary key of the item, see BuildAttrValueMap.
+//   - updates: attributes to SET, attribute name as key.
+//   - opts: WithAddValues / WithRemoveAttrs for ADD / REMOVE actions,
+//     WithCondition for a condition expression, WithReturnValues for the returned attributes.
+//
+// Returns:
+//   - the attributes selected by WithReturnValues, nil by default.
+//
+// Usage:
+//
+//	attrs, err := w.UpdateItem(key, map[string]interface{}{"status": "done"},
+//		WithAddValues(map[string]interface{}{"retries": 1}),
+//		WithReturnValues(types.ReturnValueAllNew),
+//	)
+func (w *DynamodbWrapper) UpdateItem(key map[string]types.AttributeValue, updates map[string]interface{}, opts ...DdbOptFunc) (map[string]types.AttributeValue, error) {
+	opt := &DdbOpts{returnValues: types.ReturnValueNone}
+	bindDdbOpts(opt, opts...)
+
+	expr, err := buildUpdateExpr(updates, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := w.api().UpdateItem(w.DdbCtx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(w.TableName),
+		Key:                       key,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ReturnValues:              opt.returnValues,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Attributes, nil
+}
+
+// IncrementCounter atomically adds delta (can be negative) to the number attribute of the item with key,
+// a missing attribute or item starts from 0, the new value is returned.
+//
+// Usage:
+//
+//	views, err := w.IncrementCounter(key, "views", 1)
+func (w *DynamodbWrapper) IncrementCounter(key map[string]types.AttributeValue, attribute string, delta int64) (int64, error) {
+	attrs, err := w.UpdateItem(key, nil,
+		WithAddValues(map[string]interface{}{attribute: delta}),
+		WithReturnValues(types.ReturnValueUpdatedNew),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	if err := attributevalue.Unmarshal(attrs[attribute], &n); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// buildUpdateExpr builds SET actions from updates, ADD / REMOVE actions and condition from opt.
+func buildUpdateExpr(updates map[string]interface{}, opt *DdbOpts) (expression.Expression, error) {
+	var update expression.UpdateBuilder
+
+	for name, value := range updates {
+		update = update.Set(expression.Name(name), expression.Value(value))
+	}
+
+	for name, value := range opt.addValues {
+		update = update.Add(expression.Name(name), expression.Value(value))
+	}
+
+	for _, name := range opt.removeAttr {
+		update = update.Remove(expression.Name(name))
+	}
+
+	builder := expression.NewBuilder().WithUpdate(update)
+	if opt.condition != nil {
+		builder = builder.WithCondition(*opt.condition)
+	}
+
+	return builder.Build()
+}
+
 func (w *DynamodbWrapper) BuildAttrValueMap(keys []string, values []interface{}) (map[string]types.AttributeValue, error) {
 	mapped := make(map[string]types.AttributeValue)
 
@@ -206,11 +442,20 @@ func (w *DynamodbWrapper) BuildAttrValueMap(keys []string, values []interface{})
 	return mapped, nil
 }
 
-func (w *DynamodbWrapper) GetItem(key map[string]types.AttributeValue, out interface{}) error {
-	resp, err := w.Client.GetItem(w.DdbCtx, &dynamodb.GetItemInput{
-		Key:       key,
-		TableName: aws.String(w.TableName),
-	})
+// GetItem gets the item with key into out, out is unchanged if the item doesn't exist.
+// WithConsistentRead and WithProjection are supported.
+func (w *DynamodbWrapper) GetItem(key map[string]types.AttributeValue, out interface{}, opts ...DdbOptFunc) error {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	input := &dynamodb.GetItemInput{
+		Key:            key,
+		TableName:      aws.String(w.TableName),
+		ConsistentRead: aws.Bool(opt.consistentRead),
+	}
+	input.ProjectionExpression, input.ExpressionAttributeNames = opt.projectionExpr(nil)
+
+	resp, err := w.api().GetItem(w.DdbCtx, input)
 	if err != nil {
 		return err
 	}
@@ -228,16 +473,34 @@ func (w *DynamodbWrapper) BuildQueryBeginsWith(name string, key string) (express
 	return expression.NewBuilder().WithKeyCondition(keyEx).Build()
 }
 
-func (w *DynamodbWrapper) Query(expr expression.Expression, out interface{}) error {
-	resp, err := w.Client.Query(
-		w.DdbCtx,
-		&dynamodb.QueryInput{
-			TableName:                 aws.String(w.TableName),
-			ExpressionAttributeNames:  expr.Names(),
-			ExpressionAttributeValues: expr.Values(),
-			KeyConditionExpression:    expr.KeyCondition(),
-		},
-	)
+// Query queries the table, or the index set by WithIndexName, with the key condition of expr.
+// WithConsistentRead, WithLimit and WithProjection are supported.
+func (w *DynamodbWrapper) Query(expr expression.Expression, out interface{}, opts ...DdbOptFunc) error {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(w.TableName),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ConsistentRead:            aws.Bool(opt.consistentRead),
+	}
+	input.ProjectionExpression, input.ExpressionAttributeNames = opt.projectionExpr(expr.Names())
+
+	if input.ProjectionExpression == nil {
+		input.ProjectionExpression = expr.Projection()
+	}
+
+	if opt.indexName != "" {
+		input.IndexName = aws.String(opt.indexName)
+	}
+
+	if opt.limit > 0 {
+		input.Limit = aws.Int32(opt.limit)
+	}
+
+	resp, err := w.api().Query(w.DdbCtx, input)
 	if err != nil {
 		return err
 	}
@@ -248,17 +511,33 @@ func (w *DynamodbWrapper) Query(expr expression.Expression, out interface{}) err
 func (w *DynamodbWrapper) BuildScanExpr() {
 }
 
-func (w *DynamodbWrapper) Scan(expr expression.Expression, out interface{}) error {
-	resp, err := w.Client.Scan(
-		w.DdbCtx,
-		&dynamodb.ScanInput{
-			TableName:                 aws.String(w.TableName),
-			ExpressionAttributeNames:  expr.Names(),
-			ExpressionAttributeValues: expr.Values(),
-			FilterExpression:          expr.Filter(),
-			ProjectionExpression:      expr.Projection(),
-		},
-	)
+// Scan scans the table, or the index set by WithIndexName, with the filter and projection of expr.
+// WithConsistentRead, WithLimit and WithProjection are supported.
+func (w *DynamodbWrapper) Scan(expr expression.Expression, out interface{}, opts ...DdbOptFunc) error {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(w.TableName),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ConsistentRead:            aws.Bool(opt.consistentRead),
+	}
+	input.ProjectionExpression, input.ExpressionAttributeNames = opt.projectionExpr(expr.Names())
+
+	if input.ProjectionExpression == nil {
+		input.ProjectionExpression = expr.Projection()
+	}
+
+	if opt.indexName != "" {
+		input.IndexName = aws.String(opt.indexName)
+	}
+
+	if opt.limit > 0 {
+		input.Limit = aws.Int32(opt.limit)
+	}
+
+	resp, err := w.api().Scan(w.DdbCtx, input)
 	if err != nil {
 		return err
 	}
@@ -267,7 +546,7 @@ func (w *DynamodbWrapper) Scan(expr expression.Expression, out interface{}) erro
 }
 
 func (w *DynamodbWrapper) DeleteRow(key map[string]types.AttributeValue) error {
-	_, err := w.Client.DeleteItem(
+	_, err := w.api().DeleteItem(
 		w.DdbCtx,
 		&dynamodb.DeleteItemInput{
 			TableName: aws.String(w.TableName),
@@ -279,7 +558,7 @@ func (w *DynamodbWrapper) DeleteRow(key map[string]types.AttributeValue) error {
 }
 
 func (w *DynamodbWrapper) DeleteTable() error {
-	_, err := w.Client.DeleteTable(
+	_, err := w.api().DeleteTable(
 		w.DdbCtx,
 		&dynamodb.DeleteTableInput{
 			TableName: aws.String(w.TableName),
@@ -0,0 +1,183 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// DynamoDB allows a maximum of 100 keys per BatchGetItem.
+	_maxBatchGetSize = 100
+	// DynamoDB allows a maximum of 25 items per BatchWriteItem.
+	_maxBatchWriteSize = 25
+
+	_batchRetryTimes   = 8
+	_batchRetryBackoff = 50 * time.Millisecond
+	_batchRetryMaxWait = 5 * time.Second
+)
+
+var ErrUnprocessedItems = errors.New("unprocessed items remain after retries")
+
+// GetItemBatch gets items by keys with BatchGetItem, keys are split into batches of 100,
+// and unprocessed keys are retried with exponential backoff.
+//
+// out must be a pointer to a slice, the order of items is not guaranteed
+// and keys not found are skipped.
+//
+// Usage:
+//
+//	var movies []Movie
+//	err := w.GetItemBatch(keys, &movies, WithProjection("title"))
+//
+// WithConsistentRead and WithProjection are supported.
+func (w *DynamodbWrapper) GetItemBatch(keys []map[string]types.AttributeValue, out interface{}, opts ...DdbOptFunc) error {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	projection, names := opt.projectionExpr(nil)
+
+	var items []map[string]types.AttributeValue
+
+	for start := 0; start < len(keys); start += _maxBatchGetSize {
+		end := min(start+_maxBatchGetSize, len(keys))
+
+		pending := map[string]types.KeysAndAttributes{
+			w.TableName: {
+				Keys:                     keys[start:end],
+				ConsistentRead:           aws.Bool(opt.consistentRead),
+				ProjectionExpression:     projection,
+				ExpressionAttributeNames: names,
+			},
+		}
+
+		for attempt := 0; len(pending) != 0; attempt++ {
+			if attempt >= _batchRetryTimes {
+				return fmt.Errorf("%w: %d keys of table %s", ErrUnprocessedItems, len(pending[w.TableName].Keys), w.TableName)
+			}
+
+			if attempt > 0 {
+				w.log().Debug("retrying unprocessed keys", "table", w.TableName, "count", len(pending[w.TableName].Keys), "attempt", attempt)
+				time.Sleep(batchBackoff(attempt))
+			}
+
+			resp, err := w.api().BatchGetItem(w.DdbCtx, &dynamodb.BatchGetItemInput{
+				RequestItems: pending,
+			})
+			if err != nil {
+				return err
+			}
+
+			items = append(items, resp.Responses[w.TableName]...)
+			pending = resp.UnprocessedKeys
+		}
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
+
+// batchBackoff returns the exponential wait before the nth retry of unprocessed items.
+func batchBackoff(attempt int) time.Duration {
+	return min(_batchRetryBackoff<<(attempt-1), _batchRetryMaxWait)
+}
+
+// PutItemsBatch marshals items with attributevalue.MarshalMap and writes them with BatchWriteItem
+// in batches of 25, unprocessed items are retried with exponential backoff.
+//
+// Returns the number of items written.
+//
+// Usage:
+//
+//	n, err := w.PutItemsBatch([]interface{}{movie1, movie2}, WithWriteBudget(50))
+func (w *DynamodbWrapper) PutItemsBatch(items []interface{}, opts ...DdbOptFunc) (int, error) {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	throttle := newWriteThrottle(opt.writeBudget)
+
+	requests := make([]types.WriteRequest, 0, len(items))
+
+	for i, item := range items {
+		av, err := attributevalue.MarshalMap(item)
+		if err != nil {
+			return 0, fmt.Errorf("cannot marshal item %d: %w", i, err)
+		}
+
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+	}
+
+	written := 0
+
+	for start := 0; start < len(requests); start += _maxBatchWriteSize {
+		end := min(start+_maxBatchWriteSize, len(requests))
+
+		pending := map[string][]types.WriteRequest{w.TableName: requests[start:end]}
+
+		for attempt := 0; len(pending) != 0; attempt++ {
+			if attempt >= _batchRetryTimes {
+				return written, fmt.Errorf("%w: %d items of table %s", ErrUnprocessedItems, len(pending[w.TableName]), w.TableName)
+			}
+
+			if attempt > 0 {
+				w.log().Debug("retrying unprocessed items", "table", w.TableName, "count", len(pending[w.TableName]), "attempt", attempt)
+				time.Sleep(batchBackoff(attempt))
+			}
+
+			resp, err := w.api().BatchWriteItem(w.DdbCtx, &dynamodb.BatchWriteItemInput{
+				RequestItems:           pending,
+				ReturnConsumedCapacity: throttle.returnConsumedCapacity(),
+			})
+			if err != nil {
+				return written, err
+			}
+
+			throttle.wait(resp.ConsumedCapacity)
+
+			written += len(pending[w.TableName]) - len(resp.UnprocessedItems[w.TableName])
+			pending = resp.UnprocessedItems
+		}
+	}
+
+	return written, nil
+}
+
+// writeThrottle paces batch writes to a budget of write capacity units per second, since its creation.
+type writeThrottle struct {
+	budget   float64
+	start    time.Time
+	consumed float64
+}
+
+// newWriteThrottle returns a throttle of budget wcu/s, a budget <= 0 disables throttling.
+func newWriteThrottle(budget float64) *writeThrottle {
+	return &writeThrottle{budget: budget, start: time.Now()}
+}
+
+func (t *writeThrottle) returnConsumedCapacity() types.ReturnConsumedCapacity {
+	if t.budget <= 0 {
+		return types.ReturnConsumedCapacityNone
+	}
+
+	return types.ReturnConsumedCapacityTotal
+}
+
+// wait adds the capacity consumed by a write, and sleeps until the consumption is within the budget.
+func (t *writeThrottle) wait(consumed []types.ConsumedCapacity) {
+	if t.budget <= 0 {
+		return
+	}
+
+	for _, c := range consumed {
+		t.consumed += aws.ToFloat64(c.CapacityUnits)
+	}
+
+	due := time.Duration(t.consumed / t.budget * float64(time.Second))
+	if elapsed := time.Since(t.start); elapsed < due {
+		time.Sleep(due - elapsed)
+	}
+}
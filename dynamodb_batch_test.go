@@ -0,0 +1,76 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// wcuSdk consumes 1 WCU per written item.
+type wcuSdk struct {
+	DynamodbSdkClient
+
+	calls    int
+	returned types.ReturnConsumedCapacity
+}
+
+func (c *wcuSdk) BatchWriteItem(_ context.Context, in *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	c.calls++
+	c.returned = in.ReturnConsumedCapacity
+
+	out := &dynamodb.BatchWriteItemOutput{}
+
+	for table, requests := range in.RequestItems {
+		out.ConsumedCapacity = append(out.ConsumedCapacity, types.ConsumedCapacity{
+			TableName:     aws.String(table),
+			CapacityUnits: aws.Float64(float64(len(requests))),
+		})
+	}
+
+	return out, nil
+}
+
+type DdbBatchSuite struct {
+	suite.Suite
+}
+
+func TestDdbBatch(t *testing.T) {
+	suite.Run(t, new(DdbBatchSuite))
+}
+
+func (s *DdbBatchSuite) Test_01_writeBudget() {
+	sdk := &wcuSdk{}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	items := make([]interface{}, 100)
+	for i := range items {
+		items[i] = Movie{Title: "m", Year: i}
+	}
+
+	start := time.Now()
+	n, err := w.PutItemsBatch(items)
+	s.Nil(err)
+	s.Equal(100, n)
+	s.Equal(types.ReturnConsumedCapacityNone, sdk.returned)
+	s.Less(time.Since(start), 50*time.Millisecond)
+
+	// 100 WCU at 1000 WCU/s.
+	start = time.Now()
+	n, err = w.PutItemsBatch(items, WithWriteBudget(1000))
+	s.Nil(err)
+	s.Equal(100, n)
+	s.Equal(types.ReturnConsumedCapacityTotal, sdk.returned)
+	s.GreaterOrEqual(time.Since(start), 95*time.Millisecond)
+
+	start = time.Now()
+	requests := make([]types.WriteRequest, 20)
+	n, err = w.AddItemBatch(requests, WithWriteBudget(1000))
+	s.Nil(err)
+	s.Equal(20, n)
+	s.GreaterOrEqual(time.Since(start), 15*time.Millisecond)
+}
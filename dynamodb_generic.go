@@ -0,0 +1,48 @@
+package xaws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// GetItemAs gets the item with key as a T, the zero T if the item doesn't exist.
+// db is a *DynamodbWrapper, or a FakeDynamo in tests.
+//
+// Usage:
+//
+//	movie, err := GetItemAs[Movie](w, key)
+func GetItemAs[T any](db DynamodbAPI, key map[string]types.AttributeValue, opts ...DdbOptFunc) (T, error) {
+	var out T
+	err := db.GetItem(key, &out, opts...)
+
+	return out, err
+}
+
+// QueryAs queries like db.Query and returns the items as []T.
+//
+// Usage:
+//
+//	expr, _ := w.BuildQueryExpr("year", 2010)
+//	movies, err := QueryAs[Movie](w, expr)
+func QueryAs[T any](db DynamodbAPI, expr expression.Expression, opts ...DdbOptFunc) ([]T, error) {
+	var out []T
+	err := db.Query(expr, &out, opts...)
+
+	return out, err
+}
+
+// ScanAs scans like db.Scan and returns the items as []T.
+func ScanAs[T any](db DynamodbAPI, expr expression.Expression, opts ...DdbOptFunc) ([]T, error) {
+	var out []T
+	err := db.Scan(expr, &out, opts...)
+
+	return out, err
+}
+
+// ExecuteAs runs q like q.Execute and returns the items as []T.
+func ExecuteAs[T any](q *QueryBuilder) ([]T, error) {
+	var out []T
+	err := q.Execute(&out)
+
+	return out, err
+}
@@ -0,0 +1,139 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// itemSdk records the inputs of item calls.
+type itemSdk struct {
+	DynamodbSdkClient
+
+	puts   []*dynamodb.PutItemInput
+	query  *dynamodb.QueryInput
+	scan   *dynamodb.ScanInput
+	movies []map[string]types.AttributeValue
+}
+
+func (c *itemSdk) PutItem(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	c.puts = append(c.puts, in)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *itemSdk) Query(_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	c.query = in
+	return &dynamodb.QueryOutput{Items: c.movies}, nil
+}
+
+func (c *itemSdk) Scan(_ context.Context, in *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	c.scan = in
+	return &dynamodb.ScanOutput{Items: c.movies}, nil
+}
+
+type DdbItemSuite struct {
+	suite.Suite
+}
+
+func TestDdbItem(t *testing.T) {
+	suite.Run(t, new(DdbItemSuite))
+}
+
+func (s *DdbItemSuite) Test_01_putItem() {
+	sdk := &itemSdk{}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	s.Nil(w.PutItem(Movie{Title: "m", Year: 2024}))
+	s.Require().Len(sdk.puts, 1)
+	s.Equal("movies", *sdk.puts[0].TableName)
+	s.Equal(&types.AttributeValueMemberS{Value: "m"}, sdk.puts[0].Item["title"])
+
+	// a value attributevalue cannot marshal is returned, without calling dynamodb.
+	bad := map[string]interface{}{"ch": make(chan int)}
+
+	err := w.PutItem(bad)
+	s.ErrorContains(err, "cannot marshal item")
+	s.Len(sdk.puts, 1)
+
+	s.Panics(func() { w.MustPutItem(bad) })
+	s.NotPanics(func() { w.MustPutItem(Movie{Title: "n"}) })
+	s.Len(sdk.puts, 2)
+}
+
+func (s *DdbItemSuite) Test_02_buildTableInputIndexes() {
+	w := NewDynamodbWrapperWithClient("movies", &itemSdk{}, 5, 5)
+
+	input := w.BuildTableInput("title", "year", types.ScalarAttributeTypeN,
+		AddGSI(IndexKey{Name: "by-genre", PartitionKey: "genre", PartitionKeyType: types.ScalarAttributeTypeS,
+			SortKey: "year", SortKeyType: types.ScalarAttributeTypeN}),
+		AddLSI(IndexKey{Name: "by-rating", SortKey: "rating", SortKeyType: types.ScalarAttributeTypeN}),
+	)
+
+	defs := map[string]types.ScalarAttributeType{}
+	for _, ad := range input.AttributeDefinitions {
+		defs[aws.ToString(ad.AttributeName)] = ad.AttributeType
+	}
+
+	// year is shared by the table and the gsi, and defined once.
+	s.Len(input.AttributeDefinitions, 4)
+	s.Equal(map[string]types.ScalarAttributeType{
+		"title":  types.ScalarAttributeTypeS,
+		"year":   types.ScalarAttributeTypeN,
+		"genre":  types.ScalarAttributeTypeS,
+		"rating": types.ScalarAttributeTypeN,
+	}, defs)
+
+	s.Require().Len(input.GlobalSecondaryIndexes, 1)
+	gsi := input.GlobalSecondaryIndexes[0]
+	s.Equal("by-genre", aws.ToString(gsi.IndexName))
+	s.Equal([]types.KeySchemaElement{
+		{AttributeName: aws.String("genre"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("year"), KeyType: types.KeyTypeRange},
+	}, gsi.KeySchema)
+	s.Equal(types.ProjectionTypeAll, gsi.Projection.ProjectionType)
+	s.Equal(int64(5), aws.ToInt64(gsi.ProvisionedThroughput.ReadCapacityUnits))
+
+	// the lsi always uses the partition key of the table.
+	s.Require().Len(input.LocalSecondaryIndexes, 1)
+	s.Equal([]types.KeySchemaElement{
+		{AttributeName: aws.String("title"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("rating"), KeyType: types.KeyTypeRange},
+	}, input.LocalSecondaryIndexes[0].KeySchema)
+
+	input = w.BuildTableInput("title", "", "")
+	s.Len(input.AttributeDefinitions, 1)
+	s.Empty(input.GlobalSecondaryIndexes)
+	s.Empty(input.LocalSecondaryIndexes)
+}
+
+func (s *DdbItemSuite) Test_03_queryAndScanIndex() {
+	sdk := &itemSdk{movies: []map[string]types.AttributeValue{{
+		"title": &types.AttributeValueMemberS{Value: "m"},
+		"year":  &types.AttributeValueMemberN{Value: "2024"},
+	}}}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("genre").Equal(expression.Value("drama"))).Build()
+	s.Require().Nil(err)
+
+	var movies []Movie
+
+	s.Nil(w.Query(expr, &movies, WithIndexName("by-genre")))
+	s.Equal("by-genre", aws.ToString(sdk.query.IndexName))
+	s.Equal([]Movie{{Title: "m", Year: 2024}}, movies)
+
+	s.Nil(w.Query(expr, &movies))
+	s.Nil(sdk.query.IndexName)
+
+	s.Nil(w.Scan(expression.Expression{}, &movies, WithIndexName("by-genre")))
+	s.Equal("by-genre", aws.ToString(sdk.scan.IndexName))
+
+	s.Nil(w.Scan(expression.Expression{}, &movies))
+	s.Nil(sdk.scan.IndexName)
+}
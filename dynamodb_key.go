@@ -0,0 +1,146 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+var ErrMissingKey = errors.New("missing key attribute")
+
+// SetKeySchema sets the partition and sort key names of the table, sortKey is empty if the table has none.
+// Otherwise they're set by CreateTable, or read by DescribeTable on the first call needing them.
+func (w *DynamodbWrapper) SetKeySchema(partitionKey, sortKey string) {
+	w.partitionKey, w.sortKey = partitionKey, sortKey
+}
+
+// keySchema returns the partition and sort key names of the table.
+func (w *DynamodbWrapper) keySchema() (string, string, error) {
+	if w.partitionKey != "" {
+		return w.partitionKey, w.sortKey, nil
+	}
+
+	info, err := w.DescribeTable()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot get key schema of %s: %w", w.TableName, err)
+	}
+
+	w.SetKeySchema(info.PartitionKey, info.SortKey)
+
+	return w.partitionKey, w.sortKey, nil
+}
+
+func keySchemaNames(schema []types.KeySchemaElement) (string, string) {
+	var pk, sk string
+
+	for _, e := range schema {
+		switch e.KeyType {
+		case types.KeyTypeHash:
+			pk = aws.ToString(e.AttributeName)
+		case types.KeyTypeRange:
+			sk = aws.ToString(e.AttributeName)
+		}
+	}
+
+	return pk, sk
+}
+
+// BuildKey returns the primary key of the item with partition key pk and sort key sk,
+// sk is ignored if the table has no sort key.
+func (w *DynamodbWrapper) BuildKey(pk, sk any) (map[string]types.AttributeValue, error) {
+	pkName, skName, err := w.keySchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if skName == "" {
+		return w.BuildAttrValueMap([]string{pkName}, []interface{}{pk})
+	}
+
+	return w.BuildAttrValueMap([]string{pkName, skName}, []interface{}{pk, sk})
+}
+
+// KeyOf returns the primary key of item, a struct (or map) marshaled with its `dynamodbav` tags,
+// ErrMissingKey if a key attribute is missing.
+func (w *DynamodbWrapper) KeyOf(item any) (map[string]types.AttributeValue, error) {
+	pkName, skName, err := w.keySchema()
+	if err != nil {
+		return nil, err
+	}
+
+	av, err := attributevalue.MarshalMap(item)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal item: %w", err)
+	}
+
+	key := make(map[string]types.AttributeValue, 2)
+
+	for _, name := range []string{pkName, skName} {
+		if name == "" {
+			continue
+		}
+
+		v, ok := av[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingKey, name)
+		}
+
+		key[name] = v
+	}
+
+	return key, nil
+}
+
+// GetItemByKeys gets the item with partition key pk and sort key sk into out, like GetItem with opts.
+//
+// Usage:
+//
+//	var m Movie
+//	err := w.GetItemByKeys(2010, "Test movie", &m)
+func (w *DynamodbWrapper) GetItemByKeys(pk, sk any, out interface{}, opts ...DdbOptFunc) error {
+	key, err := w.BuildKey(pk, sk)
+	if err != nil {
+		return err
+	}
+
+	return w.GetItem(key, out, opts...)
+}
+
+// DeleteItemByKeys deletes the item with partition key pk and sort key sk.
+func (w *DynamodbWrapper) DeleteItemByKeys(pk, sk any) error {
+	key, err := w.BuildKey(pk, sk)
+	if err != nil {
+		return err
+	}
+
+	return w.DeleteRow(key)
+}
+
+// Reload gets the stored version of item into item, a pointer to a struct whose key fields are set.
+//
+// Usage:
+//
+//	_ = w.PutItem(m)
+//	got := Movie{Year: m.Year, Title: m.Title}
+//	err := w.Reload(&got)
+func (w *DynamodbWrapper) Reload(item interface{}) error {
+	key, err := w.KeyOf(item)
+	if err != nil {
+		return err
+	}
+
+	return w.GetItem(key, item)
+}
+
+// DeleteItem deletes the item with the key fields of item.
+func (w *DynamodbWrapper) DeleteItem(item interface{}) error {
+	key, err := w.KeyOf(item)
+	if err != nil {
+		return err
+	}
+
+	return w.DeleteRow(key)
+}
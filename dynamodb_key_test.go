@@ -0,0 +1,164 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// memDdbSdk is a table of items keyed by pk and sk, only item reads and writes are implemented,
+// condition expressions of PutItem are evaluated.
+type memDdbSdk struct {
+	DynamodbSdkClient
+
+	pk, sk    string
+	items     map[string]map[string]types.AttributeValue
+	describes int
+}
+
+func newMemDdbSdk(pk, sk string) *memDdbSdk {
+	return &memDdbSdk{pk: pk, sk: sk, items: map[string]map[string]types.AttributeValue{}}
+}
+
+func (c *memDdbSdk) keyOf(item map[string]types.AttributeValue) string {
+	return fmt.Sprintf("%v|%v", item[c.pk], item[c.sk])
+}
+
+func (c *memDdbSdk) DescribeTable(_ context.Context, in *dynamodb.DescribeTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	c.describes++
+
+	schema := []types.KeySchemaElement{{AttributeName: aws.String(c.pk), KeyType: types.KeyTypeHash}}
+	if c.sk != "" {
+		schema = append(schema, types.KeySchemaElement{AttributeName: aws.String(c.sk), KeyType: types.KeyTypeRange})
+	}
+
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableName: in.TableName, KeySchema: schema}}, nil
+}
+
+func (c *memDdbSdk) PutItem(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	old := c.items[c.keyOf(in.Item)]
+
+	if in.ConditionExpression != nil {
+		pred, err := parseFakeExpr(*in.ConditionExpression, in.ExpressionAttributeNames, in.ExpressionAttributeValues)
+		if err != nil {
+			return nil, err
+		}
+
+		if !pred(old) {
+			return nil, &types.ConditionalCheckFailedException{Message: aws.String("condition failed"), Item: old}
+		}
+	}
+
+	c.items[c.keyOf(in.Item)] = in.Item
+
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *memDdbSdk) GetItem(_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: c.items[c.keyOf(in.Key)]}, nil
+}
+
+func (c *memDdbSdk) DeleteItem(_ context.Context, in *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(c.items, c.keyOf(in.Key))
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// readCaptureSdk records the inputs of reads and returns no item.
+type readCaptureSdk struct {
+	DynamodbSdkClient
+
+	get   *dynamodb.GetItemInput
+	query *dynamodb.QueryInput
+	scan  *dynamodb.ScanInput
+}
+
+func (c *readCaptureSdk) GetItem(_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	c.get = in
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (c *readCaptureSdk) Query(_ context.Context, in *dynamodb.QueryInput, _ ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	c.query = in
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func (c *readCaptureSdk) Scan(_ context.Context, in *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	c.scan = in
+	return &dynamodb.ScanOutput{}, nil
+}
+
+type DdbKeySuite struct {
+	suite.Suite
+}
+
+func TestDdbKey(t *testing.T) {
+	suite.Run(t, new(DdbKeySuite))
+}
+
+func (s *DdbKeySuite) Test_01_byKeys() {
+	sdk := newMemDdbSdk("year", "title")
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	movie := Movie{Title: "Test movie", Year: 2010, Info: map[string]interface{}{"rating": 7.5}}
+	s.Nil(w.PutItem(movie))
+
+	var got Movie
+	s.Nil(w.GetItemByKeys(2010, "Test movie", &got))
+	s.Equal(movie, got)
+
+	// the key schema is described once.
+	s.Nil(w.DeleteItemByKeys(2010, "Test movie"))
+	s.Empty(sdk.items)
+	s.Equal(1, sdk.describes)
+}
+
+func (s *DdbKeySuite) Test_02_fromStruct() {
+	sdk := newMemDdbSdk("year", "title")
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+	w.SetKeySchema("year", "title")
+
+	movie := Movie{Title: "Test movie", Year: 2011, Info: map[string]interface{}{"rating": 8.0}}
+	s.Nil(w.PutItem(movie))
+
+	got := Movie{Title: movie.Title, Year: movie.Year}
+	s.Nil(w.Reload(&got))
+	s.Equal(movie, got)
+
+	s.Nil(w.DeleteItem(movie))
+	s.Empty(sdk.items)
+	s.Equal(0, sdk.describes)
+
+	_, err := w.KeyOf(map[string]interface{}{"year": 2011})
+	s.ErrorIs(err, ErrMissingKey)
+}
+
+func (s *DdbKeySuite) Test_03_readOptions() {
+	sdk := &readCaptureSdk{}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	var m Movie
+	s.Nil(w.GetItem(nil, &m, WithConsistentRead(), WithProjection("title", "info.rating")))
+	s.True(*sdk.get.ConsistentRead)
+	s.Equal("#p0, #p1.#p2", *sdk.get.ProjectionExpression)
+	s.Equal(map[string]string{"#p0": "title", "#p1": "info", "#p2": "rating"}, sdk.get.ExpressionAttributeNames)
+
+	expr, err := w.BuildQueryExpr("year", 2010)
+	s.Nil(err)
+
+	var movies []Movie
+	s.Nil(w.Query(expr, &movies, WithLimit(5), WithProjection("title")))
+	s.Equal(int32(5), *sdk.query.Limit)
+	s.False(*sdk.query.ConsistentRead)
+	s.Equal("#p0", *sdk.query.ProjectionExpression)
+	s.Equal(map[string]string{"#0": "year", "#p0": "title"}, sdk.query.ExpressionAttributeNames)
+
+	s.Nil(w.Scan(expression.Expression{}, &movies))
+	s.Nil(sdk.scan.Limit)
+	s.Nil(sdk.scan.ProjectionExpression)
+}
@@ -0,0 +1,92 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// LocalDynamodbEndpointEnv overrides the endpoint of local dynamodb used by tests.
+	LocalDynamodbEndpointEnv     = "XAWS_DYNAMODB_ENDPOINT"
+	DefaultLocalDynamodbEndpoint = "http://localhost:8000"
+
+	_localTablePrefix  = "xaws-test-"
+	_localTableRandLen = 8
+	_localPingTimeout  = 2 * time.Second
+)
+
+// LocalDynamodbConfig returns a config of dynamodb-local or LocalStack at endpoint, with dummy credentials,
+// empty endpoint is $XAWS_DYNAMODB_ENDPOINT, or http://localhost:8000.
+func LocalDynamodbConfig(endpoint string) aws.Config {
+	if endpoint == "" {
+		endpoint = os.Getenv(LocalDynamodbEndpointEnv)
+	}
+
+	if endpoint == "" {
+		endpoint = DefaultLocalDynamodbEndpoint
+	}
+
+	return WithEndpoint(aws.Config{
+		Region:      _usEast1,
+		Credentials: credentials.NewStaticCredentialsProvider("local", "local", ""),
+	}, endpoint)
+}
+
+// NewLocalDynamodbWrapper returns a wrapper of a throwaway table "xaws-test-<random>" of local dynamodb
+// (see LocalDynamodbConfig), the table isn't created, and it's deleted when t ends if it exists.
+// t is skipped if local dynamodb is not reachable.
+//
+// Usage:
+//
+//	// docker run -p 8000:8000 amazon/dynamodb-local
+//	w := NewLocalDynamodbWrapper(t, "")
+//	_, err := w.CreateTable(w.BuildTableInput("id", "", ""))
+func NewLocalDynamodbWrapper(t testing.TB, endpoint string) *DynamodbWrapper {
+	t.Helper()
+
+	cfg := LocalDynamodbConfig(endpoint)
+	w := NewDynamodbWrapper(_localTablePrefix+randSeq(_localTableRandLen), cfg, 1, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), _localPingTimeout)
+	defer cancel()
+
+	if _, err := w.api().ListTables(ctx, &dynamodb.ListTablesInput{Limit: aws.Int32(1)}); err != nil {
+		t.Skipf("local dynamodb %s is not reachable: %v", aws.ToString(cfg.BaseEndpoint), err)
+	}
+
+	t.Cleanup(func() {
+		var notFound *types.ResourceNotFoundException
+		if err := w.DeleteTable(); err != nil && !errors.As(err, &notFound) {
+			t.Logf("cannot delete table %s: %v", w.TableName, err)
+		}
+	})
+
+	return w
+}
+
+// NewLocalDynamodbTable is NewLocalDynamodbWrapper with an on-demand table created by EnsureTable(model, opts...).
+//
+// Usage:
+//
+//	w := NewLocalDynamodbTable(t, "", Order{})
+//	err := w.PutItem(Order{UserID: "u1", CreatedAt: 1})
+func NewLocalDynamodbTable(t testing.TB, endpoint string, model any, opts ...TableOptFunc) *DynamodbWrapper {
+	t.Helper()
+
+	w := NewLocalDynamodbWrapper(t, endpoint)
+
+	opts = append([]TableOptFunc{WithBillingMode(types.BillingModePayPerRequest)}, opts...)
+	if _, err := w.EnsureTable(model, opts...); err != nil {
+		t.Fatalf("cannot create table %s: %v", w.TableName, err)
+	}
+
+	return w
+}
@@ -0,0 +1,189 @@
+package xaws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type DdbOpts struct {
+	condition    *expression.ConditionBuilder
+	returnValues types.ReturnValue
+
+	addValues  map[string]interface{}
+	removeAttr []string
+
+	tableName string
+	indexName string
+
+	// used by reads only.
+	consistentRead bool
+	limit          int32
+	projection     []string
+
+	// used by batch writes only.
+	writeBudget float64
+}
+
+type DdbOptFunc func(o *DdbOpts)
+
+func bindDdbOpts(opt *DdbOpts, opts ...DdbOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithCondition only applies the write when cond is met, e.g. expression.AttributeExists(expression.Name("id")).
+func WithCondition(cond expression.ConditionBuilder) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.condition = &cond
+	}
+}
+
+// WithReturnValues sets which item attributes are returned by a write, e.g. types.ReturnValueAllNew.
+func WithReturnValues(rv types.ReturnValue) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.returnValues = rv
+	}
+}
+
+// WithAddValues adds (ADD) numbers to number attributes or elements to set attributes in UpdateItem.
+func WithAddValues(m map[string]interface{}) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.addValues = m
+	}
+}
+
+// WithRemoveAttrs removes (REMOVE) attributes from the item in UpdateItem.
+func WithRemoveAttrs(names ...string) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.removeAttr = names
+	}
+}
+
+// WithTableName targets another table than the wrapper's TableName, used by transactions.
+func WithTableName(name string) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.tableName = name
+	}
+}
+
+// WithIndexName queries or scans a secondary index instead of the table.
+func WithIndexName(name string) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.indexName = name
+	}
+}
+
+// WithConsistentRead makes GetItem, GetItemBatch, Query and Scan strongly consistent,
+// it's not supported on global secondary indexes.
+func WithConsistentRead() DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.consistentRead = true
+	}
+}
+
+// WithLimit sets the maximum number of items Query and Scan evaluate,
+// items are evaluated before the filter, so fewer items may be returned.
+func WithLimit(n int) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.limit = int32(n)
+	}
+}
+
+// WithProjection only reads the fields of items, nested fields are separated by dots, e.g. "info.rating",
+// it overrides the projection of the expression of Scan.
+func WithProjection(fields ...string) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.projection = fields
+	}
+}
+
+// projectionExpr returns the projection expression of the fields set by WithProjection,
+// and names merged with the placeholders of the fields, nil and names if none is set.
+func (o *DdbOpts) projectionExpr(names map[string]string) (*string, map[string]string) {
+	if len(o.projection) == 0 {
+		return nil, names
+	}
+
+	merged := make(map[string]string, len(names)+len(o.projection))
+	for k, v := range names {
+		merged[k] = v
+	}
+
+	paths := make([]string, 0, len(o.projection))
+
+	for _, field := range o.projection {
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			// "#p" placeholders don't collide with the "#0", "#1"... of expression.Builder.
+			placeholder := fmt.Sprintf("#p%d", len(merged)-len(names))
+			merged[placeholder] = part
+			parts[i] = placeholder
+		}
+
+		paths = append(paths, strings.Join(parts, "."))
+	}
+
+	return aws.String(strings.Join(paths, ", ")), merged
+}
+
+// WithWriteBudget throttles AddItemBatch and PutItemsBatch to consume at most wcu write capacity units per second,
+// measured by the consumed capacity DynamoDB returns, so bulk loads leave capacity to production traffic.
+func WithWriteBudget(wcu float64) DdbOptFunc {
+	return func(o *DdbOpts) {
+		o.writeBudget = wcu
+	}
+}
+
+// IndexKey is the key schema of a secondary index, SortKey is optional.
+type IndexKey struct {
+	Name string
+
+	PartitionKey     string
+	PartitionKeyType types.ScalarAttributeType
+
+	SortKey     string
+	SortKeyType types.ScalarAttributeType
+}
+
+type TableOpts struct {
+	gsis []IndexKey
+	lsis []IndexKey
+
+	billingMode types.BillingMode
+}
+
+type TableOptFunc func(o *TableOpts)
+
+func bindTableOpts(opt *TableOpts, opts ...TableOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// AddGSI adds a global secondary index projecting all attributes.
+func AddGSI(index IndexKey) TableOptFunc {
+	return func(o *TableOpts) {
+		o.gsis = append(o.gsis, index)
+	}
+}
+
+// AddLSI adds a local secondary index projecting all attributes,
+// PartitionKey of index is ignored, the table's partition key is used.
+func AddLSI(index IndexKey) TableOptFunc {
+	return func(o *TableOpts) {
+		o.lsis = append(o.lsis, index)
+	}
+}
+
+// WithBillingMode sets billing mode of a new table, types.BillingModePayPerRequest for on-demand,
+// the wrapper's read/write capacity is only used for types.BillingModeProvisioned (default).
+func WithBillingMode(mode types.BillingMode) TableOptFunc {
+	return func(o *TableOpts) {
+		o.billingMode = mode
+	}
+}
@@ -0,0 +1,142 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDB allows a maximum of 25 statements per BatchExecuteStatement.
+const _maxBatchStatementSize = 25
+
+var ErrStatementFailed = errors.New("statement failed")
+
+// Statement is a PartiQL statement with its "?" parameters, marshaled with attributevalue.Marshal.
+type Statement struct {
+	SQL    string
+	Params []interface{}
+}
+
+func (s Statement) parameters() ([]types.AttributeValue, error) {
+	if len(s.Params) == 0 {
+		return nil, nil
+	}
+
+	params := make([]types.AttributeValue, 0, len(s.Params))
+
+	for i, p := range s.Params {
+		av, err := attributevalue.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal parameter %d: %w", i, err)
+		}
+
+		params = append(params, av)
+	}
+
+	return params, nil
+}
+
+// ExecuteStatement runs a PartiQL statement with params, following all pages of a SELECT,
+// and unmarshals the items into out, a pointer to a slice, nil to ignore them.
+//
+// Usage:
+//
+//	var movies []Movie
+//	err := w.ExecuteStatement(`SELECT * FROM "movies" WHERE "year" = ? AND begins_with("title", ?)`,
+//		[]interface{}{2010, "Test"}, &movies)
+func (w *DynamodbWrapper) ExecuteStatement(statement string, params []interface{}, out interface{}) error {
+	values, err := Statement{SQL: statement, Params: params}.parameters()
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.ExecuteStatementInput{
+		Statement:  aws.String(statement),
+		Parameters: values,
+	}
+
+	var items []map[string]types.AttributeValue
+
+	for {
+		resp, err := w.api().ExecuteStatement(w.DdbCtx, input)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, resp.Items...)
+
+		if resp.NextToken == nil {
+			break
+		}
+
+		input.NextToken = resp.NextToken
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
+
+// BatchExecuteStatement runs statements in batches of 25, all reads or all writes,
+// and unmarshals the items returned by reads into out, a pointer to a slice, nil to ignore them.
+//
+// Statements fail individually, their errors wrap ErrStatementFailed with the statement index and are joined,
+// items of the succeeded statements are still unmarshaled.
+//
+// Usage:
+//
+//	err := w.BatchExecuteStatement([]Statement{
+//		{SQL: `UPDATE "movies" SET "rating" = ? WHERE "year" = ? AND "title" = ?`, Params: []interface{}{8, 2010, "Test movie"}},
+//		{SQL: `DELETE FROM "movies" WHERE "year" = ? AND "title" = ?`, Params: []interface{}{2011, "Test movie"}},
+//	}, nil)
+func (w *DynamodbWrapper) BatchExecuteStatement(statements []Statement, out interface{}) error {
+	var (
+		items []map[string]types.AttributeValue
+		errs  []error
+	)
+
+	for start := 0; start < len(statements); start += _maxBatchStatementSize {
+		end := min(start+_maxBatchStatementSize, len(statements))
+
+		requests := make([]types.BatchStatementRequest, 0, end-start)
+
+		for i, s := range statements[start:end] {
+			params, err := s.parameters()
+			if err != nil {
+				return fmt.Errorf("statement #%d: %w", start+i, err)
+			}
+
+			requests = append(requests, types.BatchStatementRequest{Statement: aws.String(s.SQL), Parameters: params})
+		}
+
+		resp, err := w.api().BatchExecuteStatement(w.DdbCtx, &dynamodb.BatchExecuteStatementInput{Statements: requests})
+		if err != nil {
+			return err
+		}
+
+		for i, r := range resp.Responses {
+			if r.Error != nil {
+				errs = append(errs, fmt.Errorf("%w: #%d: %s: %s", ErrStatementFailed, start+i, r.Error.Code, aws.ToString(r.Error.Message)))
+				continue
+			}
+
+			if len(r.Item) != 0 {
+				items = append(items, r.Item)
+			}
+		}
+	}
+
+	if out != nil {
+		if err := attributevalue.UnmarshalListOfMaps(items, out); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
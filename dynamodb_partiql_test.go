@@ -0,0 +1,96 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// partiqlSdk returns two pages of one movie for ExecuteStatement,
+// and fails the DELETE statements of BatchExecuteStatement.
+type partiqlSdk struct {
+	DynamodbSdkClient
+
+	executed []*dynamodb.ExecuteStatementInput
+	batches  int
+}
+
+func (c *partiqlSdk) ExecuteStatement(_ context.Context, in *dynamodb.ExecuteStatementInput, _ ...func(*dynamodb.Options)) (*dynamodb.ExecuteStatementOutput, error) {
+	c.executed = append(c.executed, in)
+
+	out := &dynamodb.ExecuteStatementOutput{Items: []map[string]types.AttributeValue{{
+		"year":  &types.AttributeValueMemberN{Value: "2010"},
+		"title": &types.AttributeValueMemberS{Value: "page " + aws.ToString(in.NextToken)},
+	}}}
+
+	if in.NextToken == nil {
+		out.NextToken = aws.String("2")
+	}
+
+	return out, nil
+}
+
+func (c *partiqlSdk) BatchExecuteStatement(_ context.Context, in *dynamodb.BatchExecuteStatementInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchExecuteStatementOutput, error) {
+	c.batches++
+
+	out := &dynamodb.BatchExecuteStatementOutput{}
+
+	for _, st := range in.Statements {
+		if aws.ToString(st.Statement)[:6] == "DELETE" {
+			out.Responses = append(out.Responses, types.BatchStatementResponse{Error: &types.BatchStatementError{
+				Code: types.BatchStatementErrorCodeEnumConditionalCheckFailed, Message: aws.String("boom"),
+			}})
+
+			continue
+		}
+
+		out.Responses = append(out.Responses, types.BatchStatementResponse{Item: map[string]types.AttributeValue{
+			"year": st.Parameters[0],
+		}})
+	}
+
+	return out, nil
+}
+
+type PartiQLSuite struct {
+	suite.Suite
+}
+
+func TestPartiQL(t *testing.T) {
+	suite.Run(t, new(PartiQLSuite))
+}
+
+func (s *PartiQLSuite) Test_01_execute() {
+	sdk := &partiqlSdk{}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	var movies []Movie
+	s.Nil(w.ExecuteStatement(`SELECT * FROM "movies" WHERE "year" = ?`, []interface{}{2010}, &movies))
+	s.Equal([]string{"page ", "page 2"}, []string{movies[0].Title, movies[1].Title})
+	s.Len(sdk.executed, 2)
+	s.Equal(&types.AttributeValueMemberN{Value: "2010"}, sdk.executed[0].Parameters[0])
+}
+
+func (s *PartiQLSuite) Test_02_batch() {
+	sdk := &partiqlSdk{}
+	w := NewDynamodbWrapperWithClient("movies", sdk, 0, 0)
+
+	var statements []Statement
+	for i := range 30 {
+		statements = append(statements, Statement{SQL: `SELECT * FROM "movies" WHERE "year" = ?`, Params: []interface{}{2000 + i}})
+	}
+
+	statements[26] = Statement{SQL: `DELETE FROM "movies" WHERE "year" = ?`, Params: []interface{}{1}}
+
+	var movies []Movie
+	err := w.BatchExecuteStatement(statements, &movies)
+	s.ErrorIs(err, ErrStatementFailed)
+	s.Contains(err.Error(), "#26")
+	s.Len(movies, 29)
+	s.Equal(2029, movies[28].Year)
+	s.Equal(2, sdk.batches)
+}
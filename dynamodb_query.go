@@ -0,0 +1,196 @@
+package xaws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// QueryBuilder builds a Query on the partition key with optional sort key condition,
+// filter, projection, limit, order, index and consistent read.
+//
+// Usage:
+//
+//	var movies []Movie
+//	err := w.NewQuery("year", 2010).
+//		SortKeyBeginsWith("title", "Test").
+//		Filter(expression.Name("rating").GreaterThan(expression.Value(3))).
+//		Project("year", "title").
+//		Descending().
+//		Limit(10).
+//		Execute(&movies)
+type QueryBuilder struct {
+	w *DynamodbWrapper
+
+	keyCond    expression.KeyConditionBuilder
+	filter     *expression.ConditionBuilder
+	projection []string
+
+	indexName  string
+	limit      int32
+	descending bool
+	consistent bool
+}
+
+// NewQuery starts a query on items with partition key pkName equal to pkValue.
+func (w *DynamodbWrapper) NewQuery(pkName string, pkValue interface{}) *QueryBuilder {
+	return &QueryBuilder{
+		w:       w,
+		keyCond: expression.Key(pkName).Equal(expression.Value(pkValue)),
+	}
+}
+
+func (q *QueryBuilder) sortKey(cond expression.KeyConditionBuilder) *QueryBuilder {
+	q.keyCond = q.keyCond.And(cond)
+	return q
+}
+
+func (q *QueryBuilder) SortKeyEqual(name string, value interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).Equal(expression.Value(value)))
+}
+
+func (q *QueryBuilder) SortKeyBeginsWith(name string, prefix string) *QueryBuilder {
+	return q.sortKey(expression.Key(name).BeginsWith(prefix))
+}
+
+// SortKeyBetween matches sort key in [lower, upper].
+func (q *QueryBuilder) SortKeyBetween(name string, lower, upper interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).Between(expression.Value(lower), expression.Value(upper)))
+}
+
+func (q *QueryBuilder) SortKeyLessThan(name string, value interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).LessThan(expression.Value(value)))
+}
+
+func (q *QueryBuilder) SortKeyLessThanEqual(name string, value interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).LessThanEqual(expression.Value(value)))
+}
+
+func (q *QueryBuilder) SortKeyGreaterThan(name string, value interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).GreaterThan(expression.Value(value)))
+}
+
+func (q *QueryBuilder) SortKeyGreaterThanEqual(name string, value interface{}) *QueryBuilder {
+	return q.sortKey(expression.Key(name).GreaterThanEqual(expression.Value(value)))
+}
+
+// Filter filters items after they are read, filtered items still consume read capacity.
+// Calling Filter more than once combines conditions with AND.
+func (q *QueryBuilder) Filter(cond expression.ConditionBuilder) *QueryBuilder {
+	if q.filter != nil {
+		cond = q.filter.And(cond)
+	}
+
+	q.filter = &cond
+
+	return q
+}
+
+// Project only returns the named attributes.
+func (q *QueryBuilder) Project(names ...string) *QueryBuilder {
+	q.projection = append(q.projection, names...)
+	return q
+}
+
+// Limit limits the number of items returned by Execute.
+func (q *QueryBuilder) Limit(n int) *QueryBuilder {
+	q.limit = int32(n)
+	return q
+}
+
+// Index queries a secondary index instead of the table.
+func (q *QueryBuilder) Index(name string) *QueryBuilder {
+	q.indexName = name
+	return q
+}
+
+// Descending returns items in descending sort key order.
+func (q *QueryBuilder) Descending() *QueryBuilder {
+	q.descending = true
+	return q
+}
+
+// ConsistentRead uses strongly consistent reads, not supported on GSIs.
+func (q *QueryBuilder) ConsistentRead() *QueryBuilder {
+	q.consistent = true
+	return q
+}
+
+// Build returns the ready-to-run QueryInput.
+func (q *QueryBuilder) Build() (*dynamodb.QueryInput, error) {
+	builder := expression.NewBuilder().WithKeyCondition(q.keyCond)
+
+	if q.filter != nil {
+		builder = builder.WithFilter(*q.filter)
+	}
+
+	if len(q.projection) != 0 {
+		proj := expression.NamesList(expression.Name(q.projection[0]))
+		for _, name := range q.projection[1:] {
+			proj = proj.AddNames(expression.Name(name))
+		}
+
+		builder = builder.WithProjection(proj)
+	}
+
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(q.w.TableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(!q.descending),
+		ConsistentRead:            aws.Bool(q.consistent),
+	}
+
+	if q.indexName != "" {
+		input.IndexName = aws.String(q.indexName)
+	}
+
+	if q.limit > 0 {
+		input.Limit = aws.Int32(q.limit)
+	}
+
+	return input, nil
+}
+
+// Execute runs the query following all pages (or until Limit items are found)
+// and unmarshals items into out, which must be a pointer to a slice.
+func (q *QueryBuilder) Execute(out interface{}) error {
+	input, err := q.Build()
+	if err != nil {
+		return err
+	}
+
+	var items []map[string]types.AttributeValue
+
+	for {
+		resp, err := q.w.api().Query(q.w.DdbCtx, input)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, resp.Items...)
+
+		if q.limit > 0 && len(items) >= int(q.limit) {
+			items = items[:q.limit]
+			break
+		}
+
+		if len(resp.LastEvaluatedKey) == 0 {
+			break
+		}
+
+		input.ExclusiveStartKey = resp.LastEvaluatedKey
+	}
+
+	return attributevalue.UnmarshalListOfMaps(items, out)
+}
@@ -0,0 +1,266 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	_tagDdbPK  = "ddbpk"
+	_tagDdbSK  = "ddbsk"
+	_tagDdbGSI = "ddbgsi"
+
+	_ensureTableTimeout = 5 * time.Minute
+)
+
+var ErrInvalidModel = errors.New("invalid table model")
+
+// modelSchema is the key schema of a table declared by the tags of a model struct.
+type modelSchema struct {
+	index IndexKey
+	gsis  []IndexKey
+}
+
+// EnsureTable creates the table described by the tags of model if it doesn't exist, and waits until it is active,
+// then the key schema of the wrapper is set. It returns whether the table was created.
+//
+// Attributes are named by their `dynamodbav` tag, or field name, and typed by their field type:
+// strings and time.Time are S, numbers are N and []byte is B. Keys are declared by:
+//   - `ddbpk:""`: the partition key (required).
+//   - `ddbsk:""`: the sort key.
+//   - `ddbgsi:"name,pk"` / `ddbgsi:"name,sk"`: a key of the global secondary index name,
+//     "a,pk;b,sk" for a field in several indexes.
+//
+// opts are applied to the created table, e.g. WithBillingMode or AddLSI.
+//
+// Usage:
+//
+//	type Order struct {
+//		UserID    string `dynamodbav:"user_id" ddbpk:""`
+//		CreatedAt int64  `dynamodbav:"created_at" ddbsk:""`
+//		Status    string `dynamodbav:"status" ddbgsi:"by-status,pk"`
+//	}
+//
+//	created, err := w.EnsureTable(Order{}, WithBillingMode(types.BillingModePayPerRequest))
+func (w *DynamodbWrapper) EnsureTable(model any, opts ...TableOptFunc) (bool, error) {
+	schema, err := parseModelSchema(model)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := w.DescribeTable()
+	if err == nil {
+		return false, w.waitTableActive(info)
+	}
+
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		return false, err
+	}
+
+	key := schema.index
+
+	tableOpts := make([]TableOptFunc, 0, len(schema.gsis)+len(opts))
+	for _, gsi := range schema.gsis {
+		tableOpts = append(tableOpts, AddGSI(gsi))
+	}
+
+	tableOpts = append(tableOpts, opts...)
+
+	input := w.BuildTableInput(key.PartitionKey, key.SortKey, key.SortKeyType, tableOpts...)
+	// BuildTableInput always defines the partition key as a string.
+	input.AttributeDefinitions[0].AttributeType = key.PartitionKeyType
+
+	_, err = w.CreateTable(input, opts...)
+
+	var inUse *types.ResourceInUseException
+	if errors.As(err, &inUse) {
+		// created meanwhile, e.g. by another instance of the service.
+		return false, w.waitTableActive(nil)
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("cannot create table %s: %w", w.TableName, err)
+	}
+
+	return true, nil
+}
+
+// waitTableActive waits until the table is active, and sets the key schema of the wrapper,
+// info is the current description, nil to describe the table.
+func (w *DynamodbWrapper) waitTableActive(info *TableInfo) error {
+	if info == nil || info.Status != types.TableStatusActive {
+		waiter := dynamodb.NewTableExistsWaiter(w.api())
+		if err := waiter.Wait(w.DdbCtx, &dynamodb.DescribeTableInput{TableName: aws.String(w.TableName)}, _ensureTableTimeout); err != nil {
+			return err
+		}
+
+		var err error
+		if info, err = w.DescribeTable(); err != nil {
+			return err
+		}
+	}
+
+	w.SetKeySchema(info.PartitionKey, info.SortKey)
+
+	return nil
+}
+
+// parseModelSchema returns the key schema declared by the tags of model, a struct or a pointer to it.
+func parseModelSchema(model any) (*modelSchema, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a struct", ErrInvalidModel, model)
+	}
+
+	schema := &modelSchema{}
+	gsis := map[string]*IndexKey{}
+
+	var order []string
+
+	err := walkModelFields(t, func(f reflect.StructField, name string) error {
+		_, isPK := f.Tag.Lookup(_tagDdbPK)
+		_, isSK := f.Tag.Lookup(_tagDdbSK)
+		gsiTag := f.Tag.Get(_tagDdbGSI)
+
+		if !isPK && !isSK && gsiTag == "" {
+			return nil
+		}
+
+		typ, err := scalarTypeOf(f.Type)
+		if err != nil {
+			return fmt.Errorf("%w: key %s: %w", ErrInvalidModel, name, err)
+		}
+
+		if isPK {
+			schema.index.PartitionKey, schema.index.PartitionKeyType = name, typ
+		}
+
+		if isSK {
+			schema.index.SortKey, schema.index.SortKeyType = name, typ
+		}
+
+		for _, decl := range strings.Split(gsiTag, ";") {
+			if decl == "" {
+				continue
+			}
+
+			index, role, _ := strings.Cut(decl, ",")
+
+			gsi, ok := gsis[index]
+			if !ok {
+				gsi = &IndexKey{Name: index}
+				gsis[index] = gsi
+				order = append(order, index)
+			}
+
+			switch role {
+			case "pk":
+				gsi.PartitionKey, gsi.PartitionKeyType = name, typ
+			case "sk":
+				gsi.SortKey, gsi.SortKeyType = name, typ
+			default:
+				return fmt.Errorf("%w: %s of %s must be pk or sk, got %q", ErrInvalidModel, _tagDdbGSI, name, role)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.index.PartitionKey == "" {
+		return nil, fmt.Errorf("%w: no %s field in %s", ErrInvalidModel, _tagDdbPK, t)
+	}
+
+	for _, name := range order {
+		if gsis[name].PartitionKey == "" {
+			return nil, fmt.Errorf("%w: index %s has no partition key", ErrInvalidModel, name)
+		}
+
+		schema.gsis = append(schema.gsis, *gsis[name])
+	}
+
+	return schema, nil
+}
+
+// walkModelFields calls fn with the exported fields of t and their attribute names,
+// fields of embedded structs without `dynamodbav` name are walked like attributevalue does.
+func walkModelFields(t reflect.Type, fn func(f reflect.StructField, name string) error) error {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("dynamodbav")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				if err := walkModelFields(ft, fn); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		if err := fn(f, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scalarTypeOf returns the attribute type of key fields of type t.
+func scalarTypeOf(t reflect.Type) (types.ScalarAttributeType, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return TypeS, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return TypeS, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return TypeN, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return TypeB, nil
+		}
+	}
+
+	return "", fmt.Errorf("type %s cannot be a key", t)
+}
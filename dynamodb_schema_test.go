@@ -0,0 +1,92 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type schemaBase struct {
+	Tenant string `dynamodbav:"tenant" ddbgsi:"by-status,sk"`
+}
+
+type schemaOrder struct {
+	schemaBase
+
+	UserID    int64  `dynamodbav:"user_id" ddbpk:""`
+	CreatedAt string `dynamodbav:"created_at" ddbsk:""`
+	Status    string `dynamodbav:"status" ddbgsi:"by-status,pk;by-status-only,pk"`
+	Note      string `dynamodbav:"note"`
+}
+
+// createTableSdk is a table which doesn't exist until CreateTable is called.
+type createTableSdk struct {
+	DynamodbSdkClient
+
+	created *dynamodb.CreateTableInput
+}
+
+func (c *createTableSdk) DescribeTable(_ context.Context, in *dynamodb.DescribeTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	if c.created == nil {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+
+	return &dynamodb.DescribeTableOutput{Table: &types.TableDescription{
+		TableName:   in.TableName,
+		TableStatus: types.TableStatusActive,
+		KeySchema:   c.created.KeySchema,
+	}}, nil
+}
+
+func (c *createTableSdk) CreateTable(_ context.Context, in *dynamodb.CreateTableInput, _ ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	c.created = in
+	return &dynamodb.CreateTableOutput{TableDescription: &types.TableDescription{TableName: in.TableName}}, nil
+}
+
+type SchemaSuite struct {
+	suite.Suite
+}
+
+func TestSchema(t *testing.T) {
+	suite.Run(t, new(SchemaSuite))
+}
+
+func (s *SchemaSuite) Test_01_parse() {
+	schema, err := parseModelSchema(&schemaOrder{})
+	s.Nil(err)
+	s.Equal(IndexKey{PartitionKey: "user_id", PartitionKeyType: TypeN, SortKey: "created_at", SortKeyType: TypeS}, schema.index)
+	s.Equal([]IndexKey{
+		{Name: "by-status", PartitionKey: "status", PartitionKeyType: TypeS, SortKey: "tenant", SortKeyType: TypeS},
+		{Name: "by-status-only", PartitionKey: "status", PartitionKeyType: TypeS},
+	}, schema.gsis)
+
+	_, err = parseModelSchema(Movie{})
+	s.ErrorIs(err, ErrInvalidModel)
+
+	_, err = parseModelSchema(struct {
+		ID map[string]string `ddbpk:""`
+	}{})
+	s.ErrorIs(err, ErrInvalidModel)
+}
+
+func (s *SchemaSuite) Test_02_ensure() {
+	sdk := &createTableSdk{}
+	w := NewDynamodbWrapperWithClient("orders", sdk, 1, 1)
+
+	created, err := w.EnsureTable(schemaOrder{}, WithBillingMode(types.BillingModePayPerRequest))
+	s.Nil(err)
+	s.True(created)
+	s.Equal(types.BillingModePayPerRequest, sdk.created.BillingMode)
+	s.Len(sdk.created.GlobalSecondaryIndexes, 2)
+	s.Contains(sdk.created.AttributeDefinitions, types.AttributeDefinition{AttributeName: aws.String("user_id"), AttributeType: TypeN})
+	s.Len(sdk.created.AttributeDefinitions, 4)
+	s.Equal("user_id", w.partitionKey)
+
+	created, err = w.EnsureTable(schemaOrder{})
+	s.Nil(err)
+	s.False(created)
+}
@@ -0,0 +1,137 @@
+package xaws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TableInfo is a summary of DescribeTable, the raw description is kept in Raw.
+type TableInfo struct {
+	Name   string
+	Arn    string
+	Status types.TableStatus
+
+	// ItemCount and SizeBytes are updated by DynamoDB about every six hours.
+	ItemCount int64
+	SizeBytes int64
+
+	BillingMode   types.BillingMode
+	ReadCapacity  int64
+	WriteCapacity int64
+
+	StreamArn string
+
+	// SortKey is empty if the table has no sort key.
+	PartitionKey string
+	SortKey      string
+
+	Raw *types.TableDescription
+}
+
+// DescribeTable returns a summary of the table.
+func (w *DynamodbWrapper) DescribeTable() (*TableInfo, error) {
+	output, err := w.api().DescribeTable(w.DdbCtx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(w.TableName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	desc := output.Table
+	info := &TableInfo{
+		Name:      aws.ToString(desc.TableName),
+		Arn:       aws.ToString(desc.TableArn),
+		Status:    desc.TableStatus,
+		ItemCount: aws.ToInt64(desc.ItemCount),
+		SizeBytes: aws.ToInt64(desc.TableSizeBytes),
+		StreamArn: aws.ToString(desc.LatestStreamArn),
+		// tables created without billing mode are provisioned.
+		BillingMode: types.BillingModeProvisioned,
+		Raw:         desc,
+	}
+
+	info.PartitionKey, info.SortKey = keySchemaNames(desc.KeySchema)
+
+	if desc.BillingModeSummary != nil {
+		info.BillingMode = desc.BillingModeSummary.BillingMode
+	}
+
+	if pt := desc.ProvisionedThroughput; pt != nil {
+		info.ReadCapacity = aws.ToInt64(pt.ReadCapacityUnits)
+		info.WriteCapacity = aws.ToInt64(pt.WriteCapacityUnits)
+	}
+
+	return info, nil
+}
+
+// EnableTTL enables time to live on attributeName, items are deleted after
+// the epoch seconds stored in that attribute.
+func (w *DynamodbWrapper) EnableTTL(attributeName string) error {
+	return w.updateTTL(attributeName, true)
+}
+
+// DisableTTL disables time to live on attributeName.
+func (w *DynamodbWrapper) DisableTTL(attributeName string) error {
+	return w.updateTTL(attributeName, false)
+}
+
+func (w *DynamodbWrapper) updateTTL(attributeName string, enabled bool) error {
+	_, err := w.api().UpdateTimeToLive(w.DdbCtx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(w.TableName),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attributeName),
+			Enabled:       aws.Bool(enabled),
+		},
+	})
+
+	return err
+}
+
+// DescribeTTL returns the ttl attribute name and status of the table,
+// attribute name is empty when ttl is never enabled.
+func (w *DynamodbWrapper) DescribeTTL() (string, types.TimeToLiveStatus, error) {
+	output, err := w.api().DescribeTimeToLive(w.DdbCtx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(w.TableName),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	desc := output.TimeToLiveDescription
+	if desc == nil {
+		return "", types.TimeToLiveStatusDisabled, nil
+	}
+
+	return aws.ToString(desc.AttributeName), desc.TimeToLiveStatus, nil
+}
+
+// UpdateCapacity switches the table to provisioned mode with the given read/write capacity units.
+func (w *DynamodbWrapper) UpdateCapacity(readCapacity, writeCapacity int) error {
+	_, err := w.api().UpdateTable(w.DdbCtx, &dynamodb.UpdateTableInput{
+		TableName:   aws.String(w.TableName),
+		BillingMode: types.BillingModeProvisioned,
+		ProvisionedThroughput: &types.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(int64(readCapacity)),
+			WriteCapacityUnits: aws.Int64(int64(writeCapacity)),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	w.readCapacity, w.writeCapacity = readCapacity, writeCapacity
+
+	return nil
+}
+
+// SwitchToOnDemand switches the table to on-demand (PAY_PER_REQUEST) billing mode,
+// AWS allows switching billing mode once every 24 hours.
+func (w *DynamodbWrapper) SwitchToOnDemand() error {
+	_, err := w.api().UpdateTable(w.DdbCtx, &dynamodb.UpdateTableInput{
+		TableName:   aws.String(w.TableName),
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	return err
+}
@@ -3,14 +3,13 @@ package xaws
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/coghost/xpretty"
 	"github.com/k0kubun/pp/v3"
 	"github.com/stretchr/testify/suite"
-	"github.com/ungerik/go-dry"
 )
 
 type DyanmodbWrapperSuite struct {
@@ -28,17 +27,9 @@ func TestDyanmodbWrapper(t *testing.T) {
 	suite.Run(t, new(DyanmodbWrapperSuite))
 }
 
+// SetupSuite points the suite to a throwaway table of local dynamodb, see NewLocalDynamodbWrapper.
 func (s *DyanmodbWrapperSuite) SetupSuite() {
-	xpretty.Initialize()
-	cfgFile := "/tmp/aws.ddb.cfg"
-	lines, e := dry.FileGetLines(cfgFile)
-	if e != nil {
-		xpretty.DummyErrorLog(cfgFile, "is empty or not exist")
-		panic(e)
-	}
-	ak, sk, region, name := lines[0], lines[1], lines[2], lines[3]
-	cfg, _ := NewAwsConfig(ak, sk, region)
-	s.w = NewDynamodbWrapper(name, cfg, 10, 10)
+	s.w = NewLocalDynamodbWrapper(s.T(), "")
 }
 
 func (s *DyanmodbWrapperSuite) TearDownSuite() {
@@ -147,3 +138,116 @@ func (s *DyanmodbWrapperSuite) Test_07_delete() {
 		pp.Println(out)
 	}
 }
+
+func (s *DyanmodbWrapperSuite) Test_08_updateItem() {
+	tt := tests[1]
+	key, e := s.w.BuildAttrValueMap([]string{"year", "title"}, []interface{}{tt.Year, tt.Title})
+	s.Nil(e)
+
+	attrs, err := s.w.UpdateItem(key, map[string]interface{}{"info": map[string]interface{}{"rating": 5}},
+		WithCondition(expression.AttributeExists(expression.Name("title"))),
+		WithReturnValues(types.ReturnValueAllNew),
+	)
+	s.Nil(err)
+
+	var out Movie
+	s.Nil(attributevalue.UnmarshalMap(attrs, &out))
+	s.Equal(tt.Title, out.Title)
+	s.EqualValues(5, out.Info["rating"])
+}
+
+func (s *DyanmodbWrapperSuite) Test_09_getItemBatch() {
+	var keys []map[string]types.AttributeValue
+
+	for _, tt := range tests {
+		key, e := s.w.BuildAttrValueMap([]string{"year", "title"}, []interface{}{tt.Year, tt.Title})
+		s.Nil(e)
+
+		keys = append(keys, key)
+	}
+
+	var out []Movie
+	err := s.w.GetItemBatch(keys, &out)
+	s.Nil(err)
+	s.Len(out, len(tests))
+}
+
+func (s *DyanmodbWrapperSuite) Test_10_putItemsBatch() {
+	var items []interface{}
+	for _, tt := range tests {
+		items = append(items, tt)
+	}
+
+	n, err := s.w.PutItemsBatch(items)
+	s.Nil(err)
+	s.Equal(len(tests), n)
+}
+
+func (s *DyanmodbWrapperSuite) Test_11_transaction() {
+	tx := Movie{Title: "Test movie - tx", Year: 2012}
+	txKey, e := s.w.BuildAttrValueMap([]string{"year", "title"}, []interface{}{tx.Year, tx.Title})
+	s.Nil(e)
+
+	existKey, e := s.w.BuildAttrValueMap([]string{"year", "title"}, []interface{}{tests[0].Year, tests[0].Title})
+	s.Nil(e)
+
+	err := s.w.Transaction().
+		Put(tx, WithCondition(expression.AttributeNotExists(expression.Name("title")))).
+		ConditionCheck(existKey, expression.AttributeExists(expression.Name("title"))).
+		Execute()
+	s.Nil(err)
+
+	var out []Movie
+	err = s.w.TransactGet([]map[string]types.AttributeValue{txKey, existKey}, &out)
+	s.Nil(err)
+	s.Len(out, 2)
+	s.Equal(tx.Title, out[0].Title)
+
+	err = s.w.Transaction().Delete(txKey).Execute()
+	s.Nil(err)
+}
+
+func (s *DyanmodbWrapperSuite) Test_12_describeTable() {
+	info, err := s.w.DescribeTable()
+	s.Nil(err)
+	s.Equal(s.w.TableName, info.Name)
+	s.Equal(types.BillingModeProvisioned, info.BillingMode)
+	pp.Println(info.ItemCount, info.SizeBytes)
+}
+
+func (s *DyanmodbWrapperSuite) Test_13_queryBuilder() {
+	var out []Movie
+	err := s.w.NewQuery("year", 2010).
+		SortKeyBeginsWith("title", "Test movie").
+		Project("year", "title").
+		Descending().
+		Limit(1).
+		Execute(&out)
+	s.Nil(err)
+	s.Len(out, 1)
+	s.Equal("Test movie - 01", out[0].Title)
+}
+
+func (s *DyanmodbWrapperSuite) Test_14_incrementCounter() {
+	tt := tests[2]
+	key, e := s.w.BuildAttrValueMap([]string{"year", "title"}, []interface{}{tt.Year, tt.Title})
+	s.Nil(e)
+
+	n, err := s.w.IncrementCounter(key, "views", 2)
+	s.Nil(err)
+
+	m, err := s.w.IncrementCounter(key, "views", -1)
+	s.Nil(err)
+	s.Equal(n-1, m)
+}
+
+func (s *DyanmodbWrapperSuite) Test_15_localTable() {
+	w := NewLocalDynamodbTable(s.T(), "", schemaOrder{})
+
+	order := schemaOrder{UserID: 1, CreatedAt: "2024-06-01", Status: "new", Note: "first"}
+	s.Nil(w.PutItem(order))
+
+	got := schemaOrder{UserID: 1, CreatedAt: "2024-06-01"}
+	s.Nil(w.Reload(&got))
+	s.Equal(order, got)
+}
@@ -0,0 +1,195 @@
+package xaws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Transaction collects write actions executed atomically by TransactWriteItems,
+// either all actions succeed or none is applied.
+//
+// Usage:
+//
+//	err := w.Transaction().
+//		Put(order, WithCondition(expression.AttributeNotExists(expression.Name("id")))).
+//		Update(stockKey, nil, WithAddValues(map[string]interface{}{"count": -1})).
+//		ConditionCheck(userKey, expression.AttributeExists(expression.Name("id"))).
+//		Execute()
+type Transaction struct {
+	w     *DynamodbWrapper
+	items []types.TransactWriteItem
+	err   error
+}
+
+// Transaction starts a write transaction on w.TableName, other tables can be used by WithTableName.
+func (w *DynamodbWrapper) Transaction() *Transaction {
+	return &Transaction{w: w}
+}
+
+func (t *Transaction) bind(opts []DdbOptFunc) *DdbOpts {
+	opt := &DdbOpts{tableName: t.w.TableName}
+	bindDdbOpts(opt, opts...)
+
+	return opt
+}
+
+// Put adds a PutItem action, data is marshaled by attributevalue.MarshalMap.
+func (t *Transaction) Put(data interface{}, opts ...DdbOptFunc) *Transaction {
+	if t.err != nil {
+		return t
+	}
+
+	opt := t.bind(opts)
+
+	item, err := attributevalue.MarshalMap(data)
+	if err != nil {
+		t.err = fmt.Errorf("cannot marshal item %d: %w", len(t.items), err)
+		return t
+	}
+
+	put := &types.Put{TableName: aws.String(opt.tableName), Item: item}
+
+	if opt.condition != nil {
+		expr, err := expression.NewBuilder().WithCondition(*opt.condition).Build()
+		if err != nil {
+			t.err = err
+			return t
+		}
+
+		put.ConditionExpression = expr.Condition()
+		put.ExpressionAttributeNames = expr.Names()
+		put.ExpressionAttributeValues = expr.Values()
+	}
+
+	t.items = append(t.items, types.TransactWriteItem{Put: put})
+
+	return t
+}
+
+// Update adds an UpdateItem action, with the same updates and options as UpdateItem.
+func (t *Transaction) Update(key map[string]types.AttributeValue, updates map[string]interface{}, opts ...DdbOptFunc) *Transaction {
+	if t.err != nil {
+		return t
+	}
+
+	opt := t.bind(opts)
+
+	expr, err := buildUpdateExpr(updates, opt)
+	if err != nil {
+		t.err = err
+		return t
+	}
+
+	t.items = append(t.items, types.TransactWriteItem{Update: &types.Update{
+		TableName:                 aws.String(opt.tableName),
+		Key:                       key,
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}})
+
+	return t
+}
+
+// Delete adds a DeleteItem action.
+func (t *Transaction) Delete(key map[string]types.AttributeValue, opts ...DdbOptFunc) *Transaction {
+	if t.err != nil {
+		return t
+	}
+
+	opt := t.bind(opts)
+
+	del := &types.Delete{TableName: aws.String(opt.tableName), Key: key}
+
+	if opt.condition != nil {
+		expr, err := expression.NewBuilder().WithCondition(*opt.condition).Build()
+		if err != nil {
+			t.err = err
+			return t
+		}
+
+		del.ConditionExpression = expr.Condition()
+		del.ExpressionAttributeNames = expr.Names()
+		del.ExpressionAttributeValues = expr.Values()
+	}
+
+	t.items = append(t.items, types.TransactWriteItem{Delete: del})
+
+	return t
+}
+
+// ConditionCheck adds a check on another item, the transaction fails if cond is not met.
+func (t *Transaction) ConditionCheck(key map[string]types.AttributeValue, cond expression.ConditionBuilder, opts ...DdbOptFunc) *Transaction {
+	if t.err != nil {
+		return t
+	}
+
+	opt := t.bind(opts)
+
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		t.err = err
+		return t
+	}
+
+	t.items = append(t.items, types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+		TableName:                 aws.String(opt.tableName),
+		Key:                       key,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}})
+
+	return t
+}
+
+// Execute runs all actions in one TransactWriteItems call (at most 100 actions),
+// the first error of building actions is returned without calling DynamoDB.
+//
+// When a condition fails the error is a *types.TransactionCanceledException,
+// whose CancellationReasons tells which action failed.
+func (t *Transaction) Execute() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	_, err := t.w.api().TransactWriteItems(t.w.DdbCtx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: t.items,
+	})
+
+	return err
+}
+
+// TransactGet reads items by keys from w.TableName atomically with TransactGetItems (at most 100 keys).
+//
+// out must be a pointer to a slice, items are in the same order as keys,
+// a key not found is unmarshaled into a zero value.
+func (w *DynamodbWrapper) TransactGet(keys []map[string]types.AttributeValue, out interface{}) error {
+	items := make([]types.TransactGetItem, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, types.TransactGetItem{Get: &types.Get{
+			TableName: aws.String(w.TableName),
+			Key:       key,
+		}})
+	}
+
+	resp, err := w.api().TransactGetItems(w.DdbCtx, &dynamodb.TransactGetItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return err
+	}
+
+	found := make([]map[string]types.AttributeValue, 0, len(resp.Responses))
+	for _, r := range resp.Responses {
+		found = append(found, r.Item)
+	}
+
+	return attributevalue.UnmarshalListOfMaps(found, out)
+}
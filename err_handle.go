@@ -1,7 +1,54 @@
 package xaws
 
+import (
+	"errors"
+	"net/http"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
 func panicIfErr(err error) {
 	if err != nil {
 		panic(err)
 	}
 }
+
+// retryableErrorCodes are the api error codes of throttling and transient server errors.
+var retryableErrorCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"TooManyRequestsException":               true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"SlowDown":                               true,
+	"RequestTimeout":                         true,
+	"RequestTimeoutException":                true,
+	"InternalError":                          true,
+	"InternalServerError":                    true,
+	"ServiceUnavailable":                     true,
+}
+
+// IsRetryableError reports whether err is caused by throttling or a 5xx server error,
+// which are worth retrying.
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && retryableErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		code := respErr.HTTPStatusCode()
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	return false
+}
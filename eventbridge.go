@@ -3,93 +3,244 @@ package xaws
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
-	"github.com/k0kubun/pp/v3"
-	"github.com/rs/zerolog/log"
 )
 
 type EventWrapper struct {
 	client *eventbridge.Client
+
+	logger Logger
 }
 
-func NewEventWrapper(cfg aws.Config) (*EventWrapper, error) {
+func NewEventWrapper(cfg aws.Config, opts ...ClientOptFunc) (*EventWrapper, error) {
+	opt := newClientOpts(opts...)
+
 	return &EventWrapper{
-		client: eventbridge.NewFromConfig(cfg),
+		client: eventbridge.NewFromConfig(opt.apply(cfg)),
+		logger: opt.logger,
 	}, nil
 }
 
-func NewEventWrapperWithDefaultConfig() (*EventWrapper, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+func NewEventWrapperWithDefaultConfig(opts ...ClientOptFunc) (*EventWrapper, error) {
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewEventWrapper(cfg)
+	return NewEventWrapper(cfg, opts...)
 }
 
-// ListRules lists all rules available.
-func (w *EventWrapper) ListRules() []types.Rule {
-	output, err := w.client.ListRules(context.TODO(), &eventbridge.ListRulesInput{})
-	if err != nil {
-		log.Error().Err(err).Msg("cannot list rules")
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *EventWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *EventWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// ListRules lists all rules of the event bus.
+func (w *EventWrapper) ListRules(opts ...EventOptFunc) ([]types.Rule, error) {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
+	input := &eventbridge.ListRulesInput{
+		EventBusName: opt.bus(),
+	}
+
+	if opt.namePrefix != "" {
+		input.NamePrefix = aws.String(opt.namePrefix)
+	}
+
+	var rules []types.Rule
+
+	for {
+		output, err := w.client.ListRules(context.TODO(), input)
+		if err != nil {
+			return rules, fmt.Errorf("cannot list rules: %w", err)
+		}
+
+		rules = append(rules, output.Rules...)
+
+		if output.NextToken == nil {
+			return rules, nil
+		}
+
+		input.NextToken = output.NextToken
 	}
+}
+
+// DescribeRule describes rule name.
+func (w *EventWrapper) DescribeRule(name string, opts ...EventOptFunc) (*eventbridge.DescribeRuleOutput, error) {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
 
-	return output.Rules
+	return w.client.DescribeRule(context.TODO(), &eventbridge.DescribeRuleInput{
+		Name:         aws.String(name),
+		EventBusName: opt.bus(),
+	})
 }
 
-// PutRule put a rule.
-func (w *EventWrapper) PutRule(name string, schedule string) error {
-	_, err := w.client.PutRule(context.TODO(), &eventbridge.PutRuleInput{
+// PutRule put a rule triggered by schedule.
+func (w *EventWrapper) PutRule(name string, schedule string, opts ...EventOptFunc) error {
+	return w.putRule(&eventbridge.PutRuleInput{
 		Name:               aws.String(name),
 		Description:        aws.String(fmt.Sprintf("trigger %s", name)),
 		ScheduleExpression: aws.String(schedule),
 		State:              types.RuleStateEnabled,
-	})
+	}, opts...)
+}
+
+// PutPatternRule put a rule triggered by events matching pattern.
+func (w *EventWrapper) PutPatternRule(name string, pattern *EventPattern, opts ...EventOptFunc) error {
+	return w.putRule(&eventbridge.PutRuleInput{
+		Name:         aws.String(name),
+		Description:  aws.String(fmt.Sprintf("route %s", name)),
+		EventPattern: aws.String(pattern.String()),
+		State:        types.RuleStateEnabled,
+	}, opts...)
+}
+
+func (w *EventWrapper) putRule(input *eventbridge.PutRuleInput, opts ...EventOptFunc) error {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
+	input.EventBusName = opt.bus()
+
+	if opt.description != "" {
+		input.Description = aws.String(opt.description)
+	}
+
+	if opt.roleArn != "" {
+		input.RoleArn = aws.String(opt.roleArn)
+	}
+
+	_, err := w.client.PutRule(context.TODO(), input)
 
 	return err
 }
 
 // DeleteRule delete a rule with name.
-func (w *EventWrapper) DeleteRule(name string) error {
+func (w *EventWrapper) DeleteRule(name string, opts ...EventOptFunc) error {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
 	_, err := w.client.DeleteRule(context.TODO(), &eventbridge.DeleteRuleInput{
-		Name: aws.String(name),
+		Name:         aws.String(name),
+		EventBusName: opt.bus(),
 	})
 
 	return err
 }
 
-// ListTargets list targets of a rule.
-func (w *EventWrapper) ListTargets(name string) {
-	output, err := w.client.ListTargetsByRule(context.TODO(), &eventbridge.ListTargetsByRuleInput{
-		Rule: aws.String(name),
-	})
+// ListTargets lists all targets of rule name.
+func (w *EventWrapper) ListTargets(name string, opts ...EventOptFunc) ([]types.Target, error) {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
+	input := &eventbridge.ListTargetsByRuleInput{
+		Rule:         aws.String(name),
+		EventBusName: opt.bus(),
+	}
+
+	var targets []types.Target
+
+	for {
+		output, err := w.client.ListTargetsByRule(context.TODO(), input)
+		if err != nil {
+			return targets, fmt.Errorf("cannot get targets of rule %s: %w", name, err)
+		}
+
+		targets = append(targets, output.Targets...)
+
+		if output.NextToken == nil {
+			return targets, nil
+		}
+
+		input.NextToken = output.NextToken
+	}
+}
+
+// FindRulesByTarget returns the rules of the event bus having a target arn, e.g. a lambda function or a queue,
+// rules targeting a version or alias of function arn are returned too.
+// All rules are scanned, use WithEventBus for another bus than the default one.
+func (w *EventWrapper) FindRulesByTarget(arn string, opts ...EventOptFunc) ([]types.Rule, error) {
+	rules, err := w.ListRules(opts...)
 	if err != nil {
-		log.Error().Err(err).Msg("cannot get targets of rule")
+		return nil, err
+	}
+
+	var found []types.Rule
+
+	for _, rule := range rules {
+		targets, err := w.ListTargets(aws.ToString(rule.Name), opts...)
+		if err != nil {
+			return found, err
+		}
+
+		if slices.ContainsFunc(targets, func(t types.Target) bool { return targetArnMatches(aws.ToString(t.Arn), arn) }) {
+			found = append(found, rule)
+		}
 	}
 
-	pp.Println(output.Targets)
+	return found, nil
 }
 
-// PutTarget put target to a rule.
-func (w *EventWrapper) PutTarget(name string, targetArn, targetID, jsonStr string) {
+// PutTarget put target to a rule, the target is invoked with jsonStr as input.
+func (w *EventWrapper) PutTarget(name string, targetArn, targetID, jsonStr string, opts ...EventOptFunc) error {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
+	target := types.Target{
+		Arn:   aws.String(targetArn),
+		Id:    aws.String(targetID),
+		Input: aws.String(jsonStr),
+	}
+
+	if opt.roleArn != "" {
+		target.RoleArn = aws.String(opt.roleArn)
+	}
+
 	output, err := w.client.PutTargets(context.TODO(), &eventbridge.PutTargetsInput{
-		Rule: aws.String(name),
-		Targets: []types.Target{
-			{
-				Arn: aws.String(targetArn),
-				Id:  aws.String(targetID),
-				// RoleArn: aws.String(roleArn),
-				Input: aws.String(jsonStr),
-			},
-		},
+		Rule:         aws.String(name),
+		EventBusName: opt.bus(),
+		Targets:      []types.Target{target},
 	})
 	if err != nil {
-		log.Error().Err(err).Msg("cannot put targets")
+		return fmt.Errorf("cannot put targets: %w", err)
+	}
+
+	if output.FailedEntryCount > 0 {
+		failed := output.FailedEntries[0]
+		return fmt.Errorf("cannot put target %s: %s %s", targetID, aws.ToString(failed.ErrorCode), aws.ToString(failed.ErrorMessage))
+	}
+
+	return nil
+}
+
+// RemoveTargets removes targets with ids from rule name.
+func (w *EventWrapper) RemoveTargets(name string, ids []string, opts ...EventOptFunc) error {
+	opt := &EventOpts{}
+	bindEventOpts(opt, opts...)
+
+	output, err := w.client.RemoveTargets(context.TODO(), &eventbridge.RemoveTargetsInput{
+		Rule:         aws.String(name),
+		EventBusName: opt.bus(),
+		Ids:          ids,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot remove targets: %w", err)
+	}
+
+	if output.FailedEntryCount > 0 {
+		failed := output.FailedEntries[0]
+		return fmt.Errorf("cannot remove target %s: %s %s",
+			aws.ToString(failed.TargetId), aws.ToString(failed.ErrorCode), aws.ToString(failed.ErrorMessage))
 	}
 
-	pp.Println(output)
+	return nil
 }
@@ -0,0 +1,186 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+const (
+	// _putEventsBatchSize and _putEventsMaxBytes are the limits of a PutEvents call.
+	_putEventsBatchSize = 10
+	_putEventsMaxBytes  = 256 * 1024
+	// _putEventsTimeBytes is the size of Time in an entry.
+	_putEventsTimeBytes = 14
+)
+
+var ErrPutEventsFailed = errors.New("failed to put events")
+
+// Event is an application event published to an event bus.
+type Event struct {
+	Source     string
+	DetailType string
+	// Detail is marshaled to JSON, string, []byte and json.RawMessage are sent as is.
+	Detail any
+	// TraceHeader is the X-Ray trace header, optional.
+	TraceHeader string
+	Resources   []string
+	// Time defaults to the time the event is put.
+	Time time.Time
+}
+
+func (e Event) toEntry(busName string) (types.PutEventsRequestEntry, error) {
+	var detail string
+
+	switch d := e.Detail.(type) {
+	case nil:
+		detail = "{}"
+	case string:
+		detail = d
+	case []byte:
+		detail = string(d)
+	case json.RawMessage:
+		detail = string(d)
+	default:
+		raw, err := json.Marshal(d)
+		if err != nil {
+			return types.PutEventsRequestEntry{}, err
+		}
+
+		detail = string(raw)
+	}
+
+	entry := types.PutEventsRequestEntry{
+		Source:     aws.String(e.Source),
+		DetailType: aws.String(e.DetailType),
+		Detail:     aws.String(detail),
+		Resources:  e.Resources,
+	}
+
+	if busName != "" {
+		entry.EventBusName = aws.String(busName)
+	}
+
+	if e.TraceHeader != "" {
+		entry.TraceHeader = aws.String(e.TraceHeader)
+	}
+
+	if !e.Time.IsZero() {
+		entry.Time = aws.Time(e.Time)
+	}
+
+	return entry, nil
+}
+
+// entrySize returns the size of entry counted against the PutEvents limit,
+// see https://docs.aws.amazon.com/eventbridge/latest/userguide/eb-putevent-size.html
+func entrySize(entry types.PutEventsRequestEntry) int {
+	size := len(aws.ToString(entry.Source)) + len(aws.ToString(entry.DetailType)) + len(aws.ToString(entry.Detail))
+
+	if entry.Time != nil {
+		size += _putEventsTimeBytes
+	}
+
+	for _, r := range entry.Resources {
+		size += len(r)
+	}
+
+	return size
+}
+
+// CreateEventBus creates a custom event bus and returns its arn.
+func (w *EventWrapper) CreateEventBus(name string) (string, error) {
+	output, err := w.client.CreateEventBus(context.TODO(), &eventbridge.CreateEventBusInput{
+		Name: aws.String(name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.EventBusArn), nil
+}
+
+// DeleteEventBus deletes a custom event bus, rules on the bus must be deleted first.
+func (w *EventWrapper) DeleteEventBus(name string) error {
+	_, err := w.client.DeleteEventBus(context.TODO(), &eventbridge.DeleteEventBusInput{
+		Name: aws.String(name),
+	})
+
+	return err
+}
+
+// PutEvents publishes events to bus busName, empty busName means the default bus.
+// Events are sent in batches of up to 10 events and 256KB, returns the number of events accepted,
+// events over 256KB and entries rejected by EventBridge are reported in the returned error.
+//
+// Usage:
+//
+//	n, err := w.PutEvents("orders", Event{
+//		Source:     "app.orders",
+//		DetailType: "OrderCreated",
+//		Detail:     order,
+//	})
+func (w *EventWrapper) PutEvents(busName string, events ...Event) (int, error) {
+	entries := make([]types.PutEventsRequestEntry, 0, len(events))
+	sizes := make([]int, 0, len(events))
+
+	for i, e := range events {
+		entry, err := e.toEntry(busName)
+		if err != nil {
+			return 0, fmt.Errorf("cannot marshal detail of event %d: %w", i, err)
+		}
+
+		entries = append(entries, entry)
+		sizes = append(sizes, entrySize(entry))
+	}
+
+	sent := 0
+
+	var errs []error
+
+	for start := 0; start < len(entries); {
+		if sizes[start] > _putEventsMaxBytes {
+			errs = append(errs, fmt.Errorf("%w: event %d: %d bytes is over the limit of %d",
+				ErrPutEventsFailed, start, sizes[start], _putEventsMaxBytes))
+			start++
+
+			continue
+		}
+
+		end, size := start, 0
+
+		for end < len(entries) && end-start < _putEventsBatchSize && size+sizes[end] <= _putEventsMaxBytes {
+			size += sizes[end]
+			end++
+		}
+
+		output, err := w.client.PutEvents(context.TODO(), &eventbridge.PutEventsInput{
+			Entries: entries[start:end],
+		})
+		if err != nil {
+			return sent, err
+		}
+
+		for i, result := range output.Entries {
+			if result.ErrorCode == nil {
+				sent++
+				continue
+			}
+
+			w.log().Debug("event not put", "bus", busName, "index", start+i, "code", aws.ToString(result.ErrorCode))
+
+			errs = append(errs, fmt.Errorf("%w: event %d: %s %s",
+				ErrPutEventsFailed, start+i, aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage)))
+		}
+
+		start = end
+	}
+
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,53 @@
+package xaws
+
+type EventOpts struct {
+	busName     string
+	description string
+	roleArn     string
+	namePrefix  string
+}
+
+type EventOptFunc func(o *EventOpts)
+
+func bindEventOpts(opt *EventOpts, opts ...EventOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// bus returns the event bus name, nil for the default bus.
+func (o *EventOpts) bus() *string {
+	if o.busName == "" {
+		return nil
+	}
+
+	return &o.busName
+}
+
+// WithEventBus sets the event bus name or arn, the default bus is used if not set.
+func WithEventBus(name string) EventOptFunc {
+	return func(o *EventOpts) {
+		o.busName = name
+	}
+}
+
+// WithRuleDescription sets the description of rule.
+func WithRuleDescription(s string) EventOptFunc {
+	return func(o *EventOpts) {
+		o.description = s
+	}
+}
+
+// WithRuleRole sets the IAM role used by rule to invoke targets.
+func WithRuleRole(arn string) EventOptFunc {
+	return func(o *EventOpts) {
+		o.roleArn = arn
+	}
+}
+
+// WithRulePrefix only lists rules whose name starts with prefix.
+func WithRulePrefix(prefix string) EventOptFunc {
+	return func(o *EventOpts) {
+		o.namePrefix = prefix
+	}
+}
@@ -0,0 +1,111 @@
+package xaws
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// EventPattern builds an EventBridge event pattern.
+//
+// Usage:
+//
+//	pattern := NewEventPattern().
+//		Source("app.orders").
+//		DetailType("OrderCreated", "OrderUpdated").
+//		Detail("status", "PAID").
+//		DetailPrefix("customer.region", "eu-")
+//	err := w.PutPatternRule("paid-orders", pattern, WithEventBus("orders"))
+type EventPattern struct {
+	pattern map[string]any
+}
+
+func NewEventPattern() *EventPattern {
+	return &EventPattern{pattern: map[string]any{}}
+}
+
+// Source matches events from any of sources.
+func (p *EventPattern) Source(sources ...string) *EventPattern {
+	p.pattern["source"] = appendMatchers(p.pattern["source"], toAnySlice(sources)...)
+	return p
+}
+
+// DetailType matches events of any of detailTypes.
+func (p *EventPattern) DetailType(detailTypes ...string) *EventPattern {
+	p.pattern["detail-type"] = appendMatchers(p.pattern["detail-type"], toAnySlice(detailTypes)...)
+	return p
+}
+
+// Detail matches events whose detail field equals any of values,
+// field is a dot separated path, e.g. "order.status".
+func (p *EventPattern) Detail(field string, values ...any) *EventPattern {
+	return p.detail(field, values...)
+}
+
+// DetailPrefix matches events whose detail field starts with prefix.
+func (p *EventPattern) DetailPrefix(field, prefix string) *EventPattern {
+	return p.detail(field, map[string]any{"prefix": prefix})
+}
+
+// DetailNumeric matches events whose numeric detail field meets all conditions,
+// given as operator and value pairs, e.g. DetailNumeric("price", ">", 0, "<=", 100).
+func (p *EventPattern) DetailNumeric(field string, conditions ...any) *EventPattern {
+	return p.detail(field, map[string]any{"numeric": conditions})
+}
+
+// DetailExists matches events whose detail field exists, or not exists if exists is false.
+func (p *EventPattern) DetailExists(field string, exists bool) *EventPattern {
+	return p.detail(field, map[string]any{"exists": exists})
+}
+
+// DetailAnythingBut matches events whose detail field is none of values.
+func (p *EventPattern) DetailAnythingBut(field string, values ...any) *EventPattern {
+	return p.detail(field, map[string]any{"anything-but": values})
+}
+
+func (p *EventPattern) detail(field string, matchers ...any) *EventPattern {
+	node, ok := p.pattern["detail"].(map[string]any)
+	if !ok {
+		node = map[string]any{}
+		p.pattern["detail"] = node
+	}
+
+	keys := strings.Split(field, ".")
+	for _, key := range keys[:len(keys)-1] {
+		child, ok := node[key].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			node[key] = child
+		}
+
+		node = child
+	}
+
+	last := keys[len(keys)-1]
+	node[last] = appendMatchers(node[last], matchers...)
+
+	return p
+}
+
+func (p *EventPattern) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.pattern)
+}
+
+// String returns the pattern as JSON.
+func (p *EventPattern) String() string {
+	raw, _ := json.Marshal(p.pattern)
+	return string(raw)
+}
+
+func appendMatchers(existing any, matchers ...any) []any {
+	list, _ := existing.([]any)
+	return append(list, matchers...)
+}
+
+func toAnySlice(arr []string) []any {
+	out := make([]any, len(arr))
+	for i, v := range arr {
+		out[i] = v
+	}
+
+	return out
+}
@@ -0,0 +1,73 @@
+package xaws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EventPatternSuite struct {
+	suite.Suite
+}
+
+func TestEventPattern(t *testing.T) {
+	suite.Run(t, new(EventPatternSuite))
+}
+
+func (s *EventPatternSuite) Test_01_matchers() {
+	tests := []struct {
+		name    string
+		pattern *EventPattern
+		want    string
+	}{
+		{"empty", NewEventPattern(), `{}`},
+		{
+			"source and detail type",
+			NewEventPattern().Source("app.orders").DetailType("OrderCreated", "OrderUpdated"),
+			`{"detail-type":["OrderCreated","OrderUpdated"],"source":["app.orders"]}`,
+		},
+		{"source appended", NewEventPattern().Source("a").Source("b"), `{"source":["a","b"]}`},
+		{"equals", NewEventPattern().Detail("status", "PAID", 1, true), `{"detail":{"status":["PAID",1,true]}}`},
+		{"prefix", NewEventPattern().DetailPrefix("region", "eu-"), `{"detail":{"region":[{"prefix":"eu-"}]}}`},
+		{
+			"numeric",
+			NewEventPattern().DetailNumeric("price", ">", 0, "<=", 100),
+			`{"detail":{"price":[{"numeric":[">",0,"<=",100]}]}}`,
+		},
+		{"exists", NewEventPattern().DetailExists("coupon", true), `{"detail":{"coupon":[{"exists":true}]}}`},
+		{"not exists", NewEventPattern().DetailExists("coupon", false), `{"detail":{"coupon":[{"exists":false}]}}`},
+		{
+			"anything but",
+			NewEventPattern().DetailAnythingBut("status", "CANCELLED", "REFUNDED"),
+			`{"detail":{"status":[{"anything-but":["CANCELLED","REFUNDED"]}]}}`,
+		},
+		{
+			"matchers of a field are or-ed",
+			NewEventPattern().Detail("status", "PAID").DetailPrefix("status", "SHIP"),
+			`{"detail":{"status":["PAID",{"prefix":"SHIP"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Equal(tt.want, tt.pattern.String(), tt.name)
+	}
+}
+
+func (s *EventPatternSuite) Test_02_nestedDetail() {
+	pattern := NewEventPattern().
+		Source("app.orders").
+		Detail("order.status", "PAID").
+		DetailPrefix("order.customer.region", "eu-").
+		DetailNumeric("order.total", ">=", 100).
+		DetailExists("order.customer.vip", true).
+		Detail("channel", "web")
+
+	want := `{"detail":{"channel":["web"],"order":{"customer":{"region":[{"prefix":"eu-"}],"vip":[{"exists":true}]},` +
+		`"status":["PAID"],"total":[{"numeric":[">=",100]}]}},"source":["app.orders"]}`
+	s.Equal(want, pattern.String())
+
+	raw, err := json.Marshal(pattern)
+	s.Nil(err)
+	s.Equal(want, string(raw), "MarshalJSON matches String")
+}
@@ -0,0 +1,87 @@
+package xaws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventBridgeSuite struct {
+	suite.Suite
+}
+
+func TestEventBridge(t *testing.T) {
+	suite.Run(t, new(EventBridgeSuite))
+}
+
+func (s *EventBridgeSuite) Test_01_putEventsBatches() {
+	var batches []int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input struct {
+			Entries []json.RawMessage
+		}
+
+		s.Require().Nil(json.NewDecoder(r.Body).Decode(&input))
+		batches = append(batches, len(input.Entries))
+
+		entries := make([]map[string]string, len(input.Entries))
+		for i := range entries {
+			entries[i] = map[string]string{"EventId": "id"}
+		}
+
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_ = json.NewEncoder(w).Encode(map[string]any{"Entries": entries, "FailedEntryCount": 0})
+	}))
+	defer srv.Close()
+
+	w, err := NewEventWrapper(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("ak", "sk", ""),
+		BaseEndpoint: aws.String(srv.URL),
+	})
+	s.Require().Nil(err)
+
+	event := func(detailBytes int) Event {
+		return Event{Source: "app", DetailType: "t", Detail: `{"d":"` + strings.Repeat("x", detailBytes) + `"}`}
+	}
+
+	// 12 small events are split by count.
+	events := make([]Event, 12)
+	for i := range events {
+		events[i] = event(10)
+	}
+
+	n, err := w.PutEvents("", events...)
+	s.Nil(err)
+	s.Equal(12, n)
+	s.Equal([]int{10, 2}, batches)
+
+	// 100KB events are split by size, 2 per call.
+	batches = nil
+	events = make([]Event, 5)
+
+	for i := range events {
+		events[i] = event(100 * 1024)
+	}
+
+	n, err = w.PutEvents("", events...)
+	s.Nil(err)
+	s.Equal(5, n)
+	s.Equal([]int{2, 2, 1}, batches)
+
+	// an event over 256KB is reported, the others are sent.
+	batches = nil
+
+	n, err = w.PutEvents("", event(10), event(_putEventsMaxBytes), event(10))
+	s.ErrorIs(err, ErrPutEventsFailed)
+	s.ErrorContains(err, "event 1")
+	s.Equal(2, n)
+	s.Equal([]int{1, 1}, batches)
+}
@@ -0,0 +1,348 @@
+package xaws
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// FakeDynamo is an in-memory DynamodbAPI for unit tests, items are keyed by PartitionKey and SortKey.
+//
+// Conditions, key conditions and filters are evaluated in memory, see parseFakeExpr for the supported syntax.
+// Projections are ignored, and WithIndexName queries all items with the key condition,
+// so items without the index keys never match.
+type FakeDynamo struct {
+	PartitionKey string
+	// SortKey is optional, query results are ordered by it.
+	SortKey string
+
+	mu    sync.RWMutex
+	items map[string]fakeItem
+}
+
+var _ DynamodbAPI = (*FakeDynamo)(nil)
+
+func NewFakeDynamo(partitionKey, sortKey string) *FakeDynamo {
+	return &FakeDynamo{
+		PartitionKey: partitionKey,
+		SortKey:      sortKey,
+		items:        make(map[string]fakeItem),
+	}
+}
+
+func (f *FakeDynamo) keyOf(item fakeItem) (string, error) {
+	names := []string{f.PartitionKey}
+	if f.SortKey != "" {
+		names = append(names, f.SortKey)
+	}
+
+	key := ""
+
+	for _, name := range names {
+		switch v := item[name].(type) {
+		case *types.AttributeValueMemberS:
+			key += "S:" + v.Value + "\x00"
+		case *types.AttributeValueMemberN:
+			key += "N:" + v.Value + "\x00"
+		case *types.AttributeValueMemberB:
+			key += "B:" + string(v.Value) + "\x00"
+		default:
+			return "", fmt.Errorf("ValidationException: missing or invalid key attribute %s", name)
+		}
+	}
+
+	return key, nil
+}
+
+func (f *FakeDynamo) PutItem(data interface{}) error {
+	item, err := attributevalue.MarshalMap(data)
+	if err != nil {
+		return fmt.Errorf("cannot marshal item: %w", err)
+	}
+
+	k, err := f.keyOf(item)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.items[k] = item
+
+	return nil
+}
+
+func (f *FakeDynamo) GetItem(key map[string]types.AttributeValue, out interface{}, _ ...DdbOptFunc) error {
+	k, err := f.keyOf(key)
+	if err != nil {
+		return err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return attributevalue.UnmarshalMap(f.items[k], out)
+}
+
+// UpdateItem applies SET, ADD and REMOVE actions like DynamodbWrapper.UpdateItem,
+// a failed WithCondition returns *types.ConditionalCheckFailedException.
+func (f *FakeDynamo) UpdateItem(key map[string]types.AttributeValue, updates map[string]interface{}, opts ...DdbOptFunc) (map[string]types.AttributeValue, error) {
+	opt := &DdbOpts{returnValues: types.ReturnValueNone}
+	bindDdbOpts(opt, opts...)
+
+	k, err := f.keyOf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	old := f.items[k]
+
+	if opt.condition != nil {
+		ok, err := f.match(expression.NewBuilder().WithCondition(*opt.condition), old, expression.Expression.Condition)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+		}
+	}
+
+	item := make(fakeItem, len(old)+len(key)+len(updates))
+	for name, v := range old {
+		item[name] = v
+	}
+
+	for name, v := range key {
+		item[name] = v
+	}
+
+	var touched []string
+
+	for name, value := range updates {
+		v, err := attributevalue.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		item[name] = v
+		touched = append(touched, name)
+	}
+
+	for name, value := range opt.addValues {
+		v, err := attributevalue.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		sum, err := addFakeValues(item[name], v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot add to %s: %w", name, err)
+		}
+
+		item[name] = sum
+		touched = append(touched, name)
+	}
+
+	for _, name := range opt.removeAttr {
+		delete(item, name)
+		touched = append(touched, name)
+	}
+
+	f.items[k] = item
+
+	switch opt.returnValues {
+	case types.ReturnValueAllOld:
+		return old, nil
+	case types.ReturnValueAllNew:
+		return item, nil
+	case types.ReturnValueUpdatedOld:
+		return pickFakeAttrs(old, touched), nil
+	case types.ReturnValueUpdatedNew:
+		return pickFakeAttrs(item, touched), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (f *FakeDynamo) DeleteRow(key map[string]types.AttributeValue) error {
+	k, err := f.keyOf(key)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.items, k)
+
+	return nil
+}
+
+// Query returns items matching the key condition of expr, ordered by SortKey, at most WithLimit items.
+func (f *FakeDynamo) Query(expr expression.Expression, out interface{}, opts ...DdbOptFunc) error {
+	if expr.KeyCondition() == nil {
+		return fmt.Errorf("ValidationException: key condition is required")
+	}
+
+	items, err := f.filter(expr.KeyCondition(), expr)
+	if err != nil {
+		return err
+	}
+
+	if f.SortKey != "" {
+		sort.SliceStable(items, func(i, j int) bool {
+			return compareFakeValues(items[i][f.SortKey], items[j][f.SortKey]) < 0
+		})
+	}
+
+	return attributevalue.UnmarshalListOfMaps(limitFakeItems(items, opts), out)
+}
+
+// Scan returns items matching the filter of expr, all items if it has no filter, at most WithLimit items.
+func (f *FakeDynamo) Scan(expr expression.Expression, out interface{}, opts ...DdbOptFunc) error {
+	items, err := f.filter(expr.Filter(), expr)
+	if err != nil {
+		return err
+	}
+
+	return attributevalue.UnmarshalListOfMaps(limitFakeItems(items, opts), out)
+}
+
+// limitFakeItems applies WithLimit to items, the limit is on matching items since filters are not paginated.
+func limitFakeItems(items []fakeItem, opts []DdbOptFunc) []fakeItem {
+	opt := &DdbOpts{}
+	bindDdbOpts(opt, opts...)
+
+	if opt.limit > 0 && len(items) > int(opt.limit) {
+		return items[:opt.limit]
+	}
+
+	return items
+}
+
+// filter returns items matching cond, in key order.
+func (f *FakeDynamo) filter(cond *string, expr expression.Expression) ([]fakeItem, error) {
+	pred := fakePredicate(func(fakeItem) bool { return true })
+
+	if cond != nil {
+		var err error
+
+		pred, err = parseFakeExpr(*cond, expr.Names(), expr.Values())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	keys := make([]string, 0, len(f.items))
+	for k := range f.items {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var items []fakeItem
+
+	for _, k := range keys {
+		if pred(f.items[k]) {
+			items = append(items, f.items[k])
+		}
+	}
+
+	return items, nil
+}
+
+// match reports whether item meets the expression selected by part of builder.
+func (f *FakeDynamo) match(builder expression.Builder, item fakeItem, part func(expression.Expression) *string) (bool, error) {
+	expr, err := builder.Build()
+	if err != nil {
+		return false, err
+	}
+
+	pred, err := parseFakeExpr(aws.ToString(part(expr)), expr.Names(), expr.Values())
+	if err != nil {
+		return false, err
+	}
+
+	return pred(item), nil
+}
+
+// addFakeValues simulates ADD, numbers are summed and sets are merged, a missing attribute is set to v.
+func addFakeValues(cur, v types.AttributeValue) (types.AttributeValue, error) {
+	if cur == nil {
+		return v, nil
+	}
+
+	switch x := cur.(type) {
+	case *types.AttributeValueMemberN:
+		y, ok := v.(*types.AttributeValueMemberN)
+		if !ok {
+			break
+		}
+
+		a, ok1 := new(big.Rat).SetString(x.Value)
+		b, ok2 := new(big.Rat).SetString(y.Value)
+
+		if !ok1 || !ok2 {
+			break
+		}
+
+		sum := a.Add(a, b)
+		if sum.IsInt() {
+			return &types.AttributeValueMemberN{Value: sum.Num().String()}, nil
+		}
+
+		f, _ := sum.Float64()
+
+		return &types.AttributeValueMemberN{Value: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+	case *types.AttributeValueMemberSS:
+		if y, ok := v.(*types.AttributeValueMemberSS); ok {
+			return &types.AttributeValueMemberSS{Value: mergeFakeSet(x.Value, y.Value)}, nil
+		}
+	case *types.AttributeValueMemberNS:
+		if y, ok := v.(*types.AttributeValueMemberNS); ok {
+			return &types.AttributeValueMemberNS{Value: mergeFakeSet(x.Value, y.Value)}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ValidationException: %w", ErrFakeUnsupported)
+}
+
+func mergeFakeSet(a, b []string) []string {
+	merged := append([]string(nil), a...)
+
+	for _, s := range b {
+		if !slices.Contains(merged, s) {
+			merged = append(merged, s)
+		}
+	}
+
+	return merged
+}
+
+func pickFakeAttrs(item fakeItem, names []string) map[string]types.AttributeValue {
+	picked := make(map[string]types.AttributeValue)
+
+	for _, name := range names {
+		if v, ok := item[name]; ok {
+			picked[name] = v
+		}
+	}
+
+	return picked
+}
@@ -0,0 +1,404 @@
+package xaws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrFakeUnsupported is returned by fakes for requests they cannot simulate.
+var ErrFakeUnsupported = errors.New("not supported by fake")
+
+type fakeItem = map[string]types.AttributeValue
+
+// fakePredicate is a parsed condition, key condition or filter expression.
+type fakePredicate func(item fakeItem) bool
+
+// parseFakeExpr parses a condition expression built by the expression package, e.g.
+// "(#0 = :0) AND (begins_with (#1, :1))", supported are AND / OR / NOT, comparators, BETWEEN, IN,
+// begins_with, contains, attribute_exists and attribute_not_exists.
+func parseFakeExpr(src string, names map[string]string, values map[string]types.AttributeValue) (fakePredicate, error) {
+	p := &fakeExprParser{tokens: tokenizeFakeExpr(src), names: names, values: values}
+
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in %q: %w", p.tokens[p.pos], src, ErrFakeUnsupported)
+	}
+
+	return pred, nil
+}
+
+func tokenizeFakeExpr(src string) []string {
+	var tokens []string
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ':
+			i++
+		case c == '(' || c == ')' || c == ',' || c == '=':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '<' || c == '>':
+			if i+1 < len(src) && (src[i+1] == '=' || (c == '<' && src[i+1] == '>')) {
+				tokens = append(tokens, src[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, string(c))
+				i++
+			}
+		default:
+			j := i
+			for j < len(src) && !strings.ContainsRune(" (),=<>", rune(src[j])) {
+				j++
+			}
+
+			tokens = append(tokens, src[i:j])
+			i = j
+		}
+	}
+
+	return tokens
+}
+
+type fakeExprParser struct {
+	tokens []string
+	pos    int
+
+	names  map[string]string
+	values map[string]types.AttributeValue
+}
+
+// fakeOperand resolves to an attribute value of the item, nil if the attribute is missing.
+type fakeOperand func(item fakeItem) types.AttributeValue
+
+func (p *fakeExprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *fakeExprParser) next() string {
+	tok := p.peek()
+	p.pos++
+
+	return tok
+}
+
+func (p *fakeExprParser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		return fmt.Errorf("expected %q, got %q: %w", tok, got, ErrFakeUnsupported)
+	}
+
+	return nil
+}
+
+func (p *fakeExprParser) parseOr() (fakePredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "OR" {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(item fakeItem) bool { return l(item) || right(item) }
+	}
+
+	return left, nil
+}
+
+func (p *fakeExprParser) parseAnd() (fakePredicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "AND" {
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(item fakeItem) bool { return l(item) && right(item) }
+	}
+
+	return left, nil
+}
+
+func (p *fakeExprParser) parseNot() (fakePredicate, error) {
+	if p.peek() != "NOT" {
+		return p.parsePrimary()
+	}
+
+	p.next()
+
+	pred, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(item fakeItem) bool { return !pred(item) }, nil
+}
+
+func (p *fakeExprParser) parsePrimary() (fakePredicate, error) {
+	switch tok := p.peek(); tok {
+	case "(":
+		p.next()
+
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return pred, p.expect(")")
+	case "begins_with", "contains", "attribute_exists", "attribute_not_exists":
+		p.next()
+		return p.parseFunc(tok)
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op := p.next(); op {
+	case "=", "<>", "<", "<=", ">", ">=":
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(item fakeItem) bool { return compareFakeOp(left(item), op, right(item)) }, nil
+	case "BETWEEN":
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(item fakeItem) bool {
+			v := left(item)
+			return compareFakeOp(v, ">=", low(item)) && compareFakeOp(v, "<=", high(item))
+		}, nil
+	case "IN":
+		list, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(item fakeItem) bool {
+			v := left(item)
+			for _, o := range list {
+				if compareFakeOp(v, "=", o(item)) {
+					return true
+				}
+			}
+
+			return false
+		}, nil
+	default:
+		return nil, fmt.Errorf("operator %q: %w", op, ErrFakeUnsupported)
+	}
+}
+
+func (p *fakeExprParser) parseFunc(name string) (fakePredicate, error) {
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case name == "attribute_exists" && len(args) == 1:
+		return func(item fakeItem) bool { return args[0](item) != nil }, nil
+	case name == "attribute_not_exists" && len(args) == 1:
+		return func(item fakeItem) bool { return args[0](item) == nil }, nil
+	case name == "begins_with" && len(args) == 2:
+		return func(item fakeItem) bool {
+			s, ok1 := args[0](item).(*types.AttributeValueMemberS)
+			prefix, ok2 := args[1](item).(*types.AttributeValueMemberS)
+
+			return ok1 && ok2 && strings.HasPrefix(s.Value, prefix.Value)
+		}, nil
+	case name == "contains" && len(args) == 2:
+		return func(item fakeItem) bool { return containsFakeValue(args[0](item), args[1](item)) }, nil
+	default:
+		return nil, fmt.Errorf("function %s with %d args: %w", name, len(args), ErrFakeUnsupported)
+	}
+}
+
+func (p *fakeExprParser) parseArgs() ([]fakeOperand, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var args []fakeOperand
+
+	for {
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+
+		if p.peek() != "," {
+			break
+		}
+
+		p.next()
+	}
+
+	return args, p.expect(")")
+}
+
+func (p *fakeExprParser) parseOperand() (fakeOperand, error) {
+	tok := p.next()
+
+	if strings.HasPrefix(tok, ":") {
+		v, ok := p.values[tok]
+		if !ok {
+			return nil, fmt.Errorf("missing value %s", tok)
+		}
+
+		return func(fakeItem) types.AttributeValue { return v }, nil
+	}
+
+	var path []string
+
+	for _, part := range strings.Split(tok, ".") {
+		name, ok := p.names[part]
+		if !ok {
+			return nil, fmt.Errorf("operand %q: %w", tok, ErrFakeUnsupported)
+		}
+
+		path = append(path, name)
+	}
+
+	return func(item fakeItem) types.AttributeValue {
+		var v types.AttributeValue = &types.AttributeValueMemberM{Value: item}
+
+		for _, name := range path {
+			m, ok := v.(*types.AttributeValueMemberM)
+			if !ok {
+				return nil
+			}
+
+			v = m.Value[name]
+		}
+
+		return v
+	}, nil
+}
+
+func compareFakeOp(a types.AttributeValue, op string, b types.AttributeValue) bool {
+	if a == nil || b == nil {
+		return op == "<>" && (a != nil || b != nil)
+	}
+
+	switch op {
+	case "=":
+		return compareFakeValues(a, b) == 0
+	case "<>":
+		return compareFakeValues(a, b) != 0
+	}
+
+	cmp := compareFakeValues(a, b)
+	if cmp == fakeIncomparable {
+		return false
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+const fakeIncomparable = 2
+
+// compareFakeValues orders S, N and B values, other types are only equal (0) or fakeIncomparable.
+func compareFakeValues(a, b types.AttributeValue) int {
+	switch x := a.(type) {
+	case *types.AttributeValueMemberS:
+		if y, ok := b.(*types.AttributeValueMemberS); ok {
+			return strings.Compare(x.Value, y.Value)
+		}
+	case *types.AttributeValueMemberN:
+		if y, ok := b.(*types.AttributeValueMemberN); ok {
+			nx, ok1 := new(big.Rat).SetString(x.Value)
+			ny, ok2 := new(big.Rat).SetString(y.Value)
+
+			if ok1 && ok2 {
+				return nx.Cmp(ny)
+			}
+		}
+	case *types.AttributeValueMemberB:
+		if y, ok := b.(*types.AttributeValueMemberB); ok {
+			return bytes.Compare(x.Value, y.Value)
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return 0
+	}
+
+	return fakeIncomparable
+}
+
+func containsFakeValue(container, v types.AttributeValue) bool {
+	switch c := container.(type) {
+	case *types.AttributeValueMemberS:
+		s, ok := v.(*types.AttributeValueMemberS)
+		return ok && strings.Contains(c.Value, s.Value)
+	case *types.AttributeValueMemberSS:
+		s, ok := v.(*types.AttributeValueMemberS)
+		return ok && slices.Contains(c.Value, s.Value)
+	case *types.AttributeValueMemberNS:
+		n, ok := v.(*types.AttributeValueMemberN)
+		return ok && slices.Contains(c.Value, n.Value)
+	case *types.AttributeValueMemberL:
+		for _, e := range c.Value {
+			if compareFakeValues(e, v) == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
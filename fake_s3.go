@@ -0,0 +1,118 @@
+package xaws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FakeS3 is an in-memory S3API for unit tests, objects are kept per bucket,
+// WithBucket is honored and other options are ignored, content is stored as is.
+type FakeS3 struct {
+	Bucket string
+
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+var _ S3API = (*FakeS3)(nil)
+
+func NewFakeS3(bucket string) *FakeS3 {
+	return &FakeS3{
+		Bucket:  bucket,
+		buckets: make(map[string]map[string][]byte),
+	}
+}
+
+func (f *FakeS3) bucketOf(opts []S3OptionFunc) string {
+	opt := &S3Options{bucket: f.Bucket}
+	bindS3Options(opt, opts...)
+
+	return opt.bucket
+}
+
+func (f *FakeS3) PutObject(objectKey string, raw []byte, opts ...S3OptionFunc) error {
+	bucket := f.bucketOf(opts)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.buckets[bucket] == nil {
+		f.buckets[bucket] = make(map[string][]byte)
+	}
+
+	f.buckets[bucket][objectKey] = append([]byte(nil), raw...)
+
+	return nil
+}
+
+// GetObject returns nil without error when the object does not exist, same as S3Client.GetObject.
+func (f *FakeS3) GetObject(objectKey string, opts ...S3OptionFunc) ([]byte, error) {
+	bucket := f.bucketOf(opts)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	content, ok := f.buckets[bucket][objectKey]
+	if !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), content...), nil
+}
+
+func (f *FakeS3) HasObject(objectKey string, opts ...S3OptionFunc) (bool, error) {
+	bucket := f.bucketOf(opts)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	_, ok := f.buckets[bucket][objectKey]
+
+	return ok, nil
+}
+
+func (f *FakeS3) DeleteObject(objectKey string, opts ...S3OptionFunc) error {
+	bucket := f.bucketOf(opts)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.buckets[bucket], objectKey)
+
+	return nil
+}
+
+// ListObjects returns keys starting with prefix in lexical order.
+func (f *FakeS3) ListObjects(prefix string, opts ...S3OptionFunc) ([]string, error) {
+	bucket := f.bucketOf(opts)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var keys []string
+
+	for key := range f.buckets[bucket] {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// CopyObject copies srcKey of f.Bucket to dstKey of the bucket set by WithBucket, f.Bucket by default.
+func (f *FakeS3) CopyObject(srcKey, dstKey string, opts ...S3OptionFunc) error {
+	f.mu.RLock()
+	content, ok := f.buckets[f.Bucket][srcKey]
+	f.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("cannot head source object %s: NoSuchKey", srcKey)
+	}
+
+	return f.PutObject(dstKey, content, opts...)
+}
@@ -0,0 +1,138 @@
+package xaws
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	_fakeVisibilityTimeout = 30 * time.Second
+	_fakeBatchSize         = 10
+)
+
+// FakeSQS is an in-memory SqsAPI for unit tests, it simulates a standard queue:
+// received messages are invisible for VisibilityTimeout, and come back unless deleted.
+type FakeSQS struct {
+	VisibilityTimeout time.Duration
+	BatchSize         int
+
+	mu       sync.Mutex
+	seq      int
+	messages []*fakeMessage
+}
+
+type fakeMessage struct {
+	id        string
+	body      string
+	handle    string
+	visibleAt time.Time
+}
+
+var _ SqsAPI = (*FakeSQS)(nil)
+
+func NewFakeSQS() *FakeSQS {
+	return &FakeSQS{
+		VisibilityTimeout: _fakeVisibilityTimeout,
+		BatchSize:         _fakeBatchSize,
+	}
+}
+
+func (f *FakeSQS) SendMsg(message string) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	msg := &fakeMessage{id: fmt.Sprintf("fake-msg-%d", f.seq), body: message}
+	f.messages = append(f.messages, msg)
+
+	sum := md5.Sum([]byte(message)) //nolint:gosec
+
+	return &sqs.SendMessageOutput{
+		MessageId:        aws.String(msg.id),
+		MD5OfMessageBody: aws.String(hex.EncodeToString(sum[:])),
+	}, nil
+}
+
+func (f *FakeSQS) SendManyMessages(messages []string) (int, error) {
+	for _, m := range messages {
+		if _, err := f.SendMsg(m); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// GetMsgs receives up to BatchSize (or BatchSize option) visible messages, it never waits.
+func (f *FakeSQS) GetMsgs(opts ...SqsOptFunc) (*sqs.ReceiveMessageOutput, error) {
+	opt := SqsOpts{batchSize: f.BatchSize}
+	bindSqsOpts(&opt, opts...)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	output := &sqs.ReceiveMessageOutput{}
+
+	for _, msg := range f.messages {
+		if len(output.Messages) >= opt.batchSize {
+			break
+		}
+
+		if msg.visibleAt.After(now) {
+			continue
+		}
+
+		f.seq++
+		msg.handle = fmt.Sprintf("fake-handle-%d", f.seq)
+		msg.visibleAt = now.Add(f.VisibilityTimeout)
+
+		output.Messages = append(output.Messages, types.Message{
+			MessageId:     aws.String(msg.id),
+			Body:          aws.String(msg.body),
+			ReceiptHandle: aws.String(msg.handle),
+		})
+	}
+
+	return output, nil
+}
+
+// DeleteMsg deletes the message received with handle, only the latest handle of a message is valid.
+func (f *FakeSQS) DeleteMsg(handle *string) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, msg := range f.messages {
+		if msg.handle != "" && msg.handle == aws.ToString(handle) {
+			f.messages = append(f.messages[:i], f.messages[i+1:]...)
+			return &sqs.DeleteMessageOutput{}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ReceiptHandleIsInvalid: %s", aws.ToString(handle))
+}
+
+// GetRemainedItems returns the number of visible messages.
+func (f *FakeSQS) GetRemainedItems(_ ...SqsOptFunc) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+
+	var n int64
+
+	for _, msg := range f.messages {
+		if !msg.visibleAt.After(now) {
+			n++
+		}
+	}
+
+	return n, nil
+}
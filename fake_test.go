@@ -0,0 +1,159 @@
+package xaws
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type FakeSuite struct {
+	suite.Suite
+}
+
+func TestFake(t *testing.T) {
+	suite.Run(t, new(FakeSuite))
+}
+
+func (s *FakeSuite) Test_01_s3() {
+	var api S3API = NewFakeS3("bucket")
+
+	s.Nil(api.PutObject("a/1.txt", []byte("one")))
+	s.Nil(api.PutObject("b/2.txt", []byte("two")))
+	s.Nil(api.CopyObject("a/1.txt", "a/1.txt", WithBucket("backup")))
+
+	raw, err := api.GetObject("a/1.txt")
+	s.Nil(err)
+	s.Equal("one", string(raw))
+
+	raw, err = api.GetObject("missing")
+	s.Nil(err)
+	s.Nil(raw)
+
+	keys, err := api.ListObjects("a/")
+	s.Nil(err)
+	s.Equal([]string{"a/1.txt"}, keys)
+
+	s.Nil(api.DeleteObject("a/1.txt"))
+
+	ok, err := api.HasObject("a/1.txt")
+	s.Nil(err)
+	s.False(ok)
+
+	ok, err = api.HasObject("a/1.txt", WithBucket("backup"))
+	s.Nil(err)
+	s.True(ok)
+}
+
+func (s *FakeSuite) Test_02_sqs() {
+	fake := NewFakeSQS()
+	fake.VisibilityTimeout = 50 * time.Millisecond
+
+	var api SqsAPI = fake
+
+	n, err := api.SendManyMessages([]string{"a", "b", "c"})
+	s.Nil(err)
+	s.Equal(3, n)
+
+	output, err := api.GetMsgs(BatchSize(2))
+	s.Nil(err)
+	s.Len(output.Messages, 2)
+
+	remained, _ := api.GetRemainedItems()
+	s.Equal(int64(1), remained)
+
+	_, err = api.DeleteMsg(output.Messages[0].ReceiptHandle)
+	s.Nil(err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	remained, _ = api.GetRemainedItems()
+	s.Equal(int64(2), remained)
+}
+
+func (s *FakeSuite) Test_03_dynamo() {
+	type row struct {
+		ID    string `dynamodbav:"id"`
+		Ts    int    `dynamodbav:"ts"`
+		Views int    `dynamodbav:"views"`
+	}
+
+	var api DynamodbAPI = NewFakeDynamo("id", "ts")
+
+	for _, ts := range []int{3, 1, 2} {
+		s.Nil(api.PutItem(row{ID: "a", Ts: ts}))
+	}
+
+	s.Nil(api.PutItem(row{ID: "b", Ts: 1}))
+
+	key := map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "a"},
+		"ts": &types.AttributeValueMemberN{Value: "1"},
+	}
+
+	_, err := api.UpdateItem(key, nil, WithAddValues(map[string]interface{}{"views": 2}))
+	s.Nil(err)
+
+	got := row{}
+	s.Nil(api.GetItem(key, &got))
+	s.Equal(2, got.Views)
+
+	_, err = api.UpdateItem(key, map[string]interface{}{"views": 0},
+		WithCondition(expression.Name("views").GreaterThan(expression.Value(5))))
+
+	var ccf *types.ConditionalCheckFailedException
+	s.True(errors.As(err, &ccf))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(
+		expression.Key("id").Equal(expression.Value("a")).And(expression.Key("ts").GreaterThanEqual(expression.Value(2))),
+	).Build()
+	s.Nil(err)
+
+	var rows []row
+	s.Nil(api.Query(expr, &rows))
+	s.Len(rows, 2)
+	s.Equal(2, rows[0].Ts)
+
+	expr, err = expression.NewBuilder().WithFilter(expression.Name("views").Equal(expression.Value(2))).Build()
+	s.Nil(err)
+
+	rows = nil
+	s.Nil(api.Scan(expr, &rows))
+	s.Len(rows, 1)
+
+	s.Nil(api.DeleteRow(key))
+	s.Nil(api.Scan(expression.Expression{}, &rows))
+}
+
+func (s *FakeSuite) Test_04_generics() {
+	type row struct {
+		ID string `dynamodbav:"id"`
+		Ts int    `dynamodbav:"ts"`
+	}
+
+	fake := NewFakeDynamo("id", "ts")
+	for _, ts := range []int{2, 1} {
+		s.Nil(fake.PutItem(row{ID: "a", Ts: ts}))
+	}
+
+	got, err := GetItemAs[row](fake, map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: "a"},
+		"ts": &types.AttributeValueMemberN{Value: "2"},
+	})
+	s.Nil(err)
+	s.Equal(row{ID: "a", Ts: 2}, got)
+
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("id").Equal(expression.Value("a"))).Build()
+	s.Nil(err)
+
+	rows, err := QueryAs[row](fake, expr)
+	s.Nil(err)
+	s.Equal([]row{{ID: "a", Ts: 1}, {ID: "a", Ts: 2}}, rows)
+
+	rows, err = ScanAs[row](fake, expression.Expression{})
+	s.Nil(err)
+	s.Len(rows, 2)
+}
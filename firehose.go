@@ -0,0 +1,215 @@
+package xaws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/firehose/types"
+)
+
+const (
+	// _putRecordBatchMaxCount and _putRecordBatchMaxBytes are the limits of a PutRecordBatch call.
+	_putRecordBatchMaxCount = 500
+	_putRecordBatchMaxBytes = 4 * 1024 * 1024
+)
+
+var ErrPutRecordBatchFailed = errors.New("failed to put record batch")
+
+type FirehoseOpts struct {
+	newline bool
+	retry   RetryPolicy
+}
+
+type FirehoseOptFunc func(o *FirehoseOpts)
+
+func bindFirehoseOpts(opt *FirehoseOpts, opts ...FirehoseOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithNewlineDelimited appends "\n" to records not ending with it,
+// so JSON records land in S3 as JSON lines.
+func WithNewlineDelimited() FirehoseOptFunc {
+	return func(o *FirehoseOpts) {
+		o.newline = true
+	}
+}
+
+// WithFailedEntryRetry sets how entries rejected by Firehose are resent, DefaultRetryPolicy by default,
+// NoRetryPolicy disables resending.
+func WithFailedEntryRetry(p RetryPolicy) FirehoseOptFunc {
+	return func(o *FirehoseOpts) {
+		o.retry = p
+	}
+}
+
+type FirehoseWrapper struct {
+	client *firehose.Client
+
+	StreamName string
+
+	logger Logger
+}
+
+func NewFirehoseWrapper(stream string, cfg aws.Config, opts ...ClientOptFunc) *FirehoseWrapper {
+	opt := newClientOpts(opts...)
+
+	return &FirehoseWrapper{
+		client:     firehose.NewFromConfig(opt.apply(cfg)),
+		StreamName: stream,
+		logger:     opt.logger,
+	}
+}
+
+func NewFirehoseWrapperWithDefaultConfig(stream string, opts ...ClientOptFunc) (*FirehoseWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFirehoseWrapper(stream, cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *FirehoseWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *FirehoseWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// PutRecord puts a single record to the delivery stream.
+func (w *FirehoseWrapper) PutRecord(data []byte, opts ...FirehoseOptFunc) error {
+	opt := &FirehoseOpts{}
+	bindFirehoseOpts(opt, opts...)
+
+	_, err := w.client.PutRecord(context.TODO(), &firehose.PutRecordInput{
+		DeliveryStreamName: aws.String(w.StreamName),
+		Record:             &types.Record{Data: opt.delimit(data)},
+	})
+	if err != nil {
+		return fmt.Errorf("cannot put record to %s: %w", w.StreamName, err)
+	}
+
+	return nil
+}
+
+// PutRecordBatch puts records in batches of up to 500 records and 4MB, entries rejected by Firehose
+// are resent with WithFailedEntryRetry, returns the number of records accepted,
+// records still rejected are reported in the returned error.
+//
+// Usage:
+//
+//	n, err := w.PutRecordBatch(rows, WithNewlineDelimited())
+func (w *FirehoseWrapper) PutRecordBatch(records [][]byte, opts ...FirehoseOptFunc) (int, error) {
+	opt := &FirehoseOpts{retry: DefaultRetryPolicy}
+	bindFirehoseOpts(opt, opts...)
+
+	sent := 0
+
+	var errs []error
+
+	for start := 0; start < len(records); {
+		end, size := start, 0
+
+		var batch []types.Record
+
+		for end < len(records) && len(batch) < _putRecordBatchMaxCount {
+			data := opt.delimit(records[end])
+			if len(batch) > 0 && size+len(data) > _putRecordBatchMaxBytes {
+				break
+			}
+
+			batch = append(batch, types.Record{Data: data})
+			size += len(data)
+			end++
+		}
+
+		n, err := w.putBatch(batch, opt.retry)
+		sent += n
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("records %d-%d: %w", start, end-1, err))
+		}
+
+		start = end
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// PutJSON marshals records to JSON and puts them as JSON lines, see PutRecordBatch.
+func (w *FirehoseWrapper) PutJSON(records []any, opts ...FirehoseOptFunc) (int, error) {
+	raws := make([][]byte, 0, len(records))
+
+	for i, r := range records {
+		raw, err := json.Marshal(r)
+		if err != nil {
+			return 0, fmt.Errorf("cannot marshal record %d: %w", i, err)
+		}
+
+		raws = append(raws, raw)
+	}
+
+	return w.PutRecordBatch(raws, append([]FirehoseOptFunc{WithNewlineDelimited()}, opts...)...)
+}
+
+// putBatch puts batch and resends failed entries with policy.
+func (w *FirehoseWrapper) putBatch(batch []types.Record, policy RetryPolicy) (int, error) {
+	sent := 0
+
+	for attempt := 1; ; attempt++ {
+		output, err := w.client.PutRecordBatch(context.TODO(), &firehose.PutRecordBatchInput{
+			DeliveryStreamName: aws.String(w.StreamName),
+			Records:            batch,
+		})
+		if err != nil {
+			return sent, err
+		}
+
+		var (
+			failed  []types.Record
+			lastErr error
+		)
+
+		for i, result := range output.RequestResponses {
+			if result.ErrorCode == nil {
+				sent++
+				continue
+			}
+
+			failed = append(failed, batch[i])
+			lastErr = fmt.Errorf("%w: %s %s", ErrPutRecordBatchFailed, aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage))
+		}
+
+		if len(failed) == 0 {
+			return sent, nil
+		}
+
+		if attempt >= policy.maxAttempts() {
+			return sent, fmt.Errorf("%d records failed after %d attempts: %w", len(failed), attempt, lastErr)
+		}
+
+		delay, _ := policy.BackoffDelay(attempt, lastErr)
+		w.log().Warn("resend failed records", "stream", w.StreamName, "failed", len(failed), "attempt", attempt, "delay", delay)
+		time.Sleep(delay)
+
+		batch = failed
+	}
+}
+
+func (o *FirehoseOpts) delimit(data []byte) []byte {
+	if !o.newline || bytes.HasSuffix(data, []byte("\n")) {
+		return data
+	}
+
+	return append(append(make([]byte, 0, len(data)+1), data...), '\n')
+}
@@ -0,0 +1,207 @@
+package xaws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	_defaultIdempotencyTTL   = 24 * time.Hour
+	_defaultIdempotencyLease = 5 * time.Minute
+
+	_idempotencyInProgress = "IN_PROGRESS"
+	_idempotencyCompleted  = "COMPLETED"
+)
+
+var (
+	// ErrAlreadyProcessed is returned by IdempotencyStore.Put when the key is recorded with the same payload.
+	ErrAlreadyProcessed = errors.New("already processed")
+	// ErrPayloadMismatch is returned when the key is recorded with another payload, usually a key collision.
+	ErrPayloadMismatch = errors.New("idempotency key reused with another payload")
+	// ErrInProgress is returned by IdempotencyStore.Claim when the key is claimed and its lease is not expired.
+	ErrInProgress = errors.New("being processed")
+)
+
+// IdempotencyStore records processed keys with the hash of their payload in a DynamoDB table,
+// records expire after ttl.
+//
+// A key is either claimed, while it's being processed, or completed. A claim expires after the lease,
+// so a key whose processing crashed can be claimed again.
+//
+// Items have "payload_hash", "status" and "expires_at" (unix seconds), enable TTL on "expires_at" to delete expired records.
+//
+// Usage:
+//
+//	store := NewIdempotencyStore(ddb, "id", 24*time.Hour)
+//	if err := store.Claim(orderID, body); err != nil {
+//		return err
+//	}
+//	if err := process(body); err != nil {
+//		_ = store.Delete(orderID)
+//		return err
+//	}
+//	return store.Complete(orderID, body)
+type IdempotencyStore struct {
+	w       *DynamodbWrapper
+	keyName string
+	ttl     time.Duration
+	lease   time.Duration
+}
+
+// NewIdempotencyStore stores records in the table of w, whose partition key is keyName of type S,
+// ttl <= 0 uses 24 hours.
+func NewIdempotencyStore(w *DynamodbWrapper, keyName string, ttl time.Duration) *IdempotencyStore {
+	if ttl <= 0 {
+		ttl = _defaultIdempotencyTTL
+	}
+
+	return &IdempotencyStore{w: w, keyName: keyName, ttl: ttl, lease: _defaultIdempotencyLease}
+}
+
+// SetLease sets how long a claim is kept without being completed, 5 minutes by default,
+// it should cover the processing of a message.
+func (s *IdempotencyStore) SetLease(d time.Duration) {
+	s.lease = d
+}
+
+type idempotencyRecord struct {
+	PayloadHash string `dynamodbav:"payload_hash"`
+	// Status is empty for records written before claims, they are completed.
+	Status    string `dynamodbav:"status,omitempty"`
+	ExpiresAt int64  `dynamodbav:"expires_at"`
+}
+
+func payloadHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *IdempotencyStore) key(key string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{s.keyName: &types.AttributeValueMemberS{Value: key}}
+}
+
+// Put records key with payload as completed if it is not recorded or expired,
+// returns ErrAlreadyProcessed, ErrInProgress or ErrPayloadMismatch if it is recorded.
+func (s *IdempotencyStore) Put(key string, payload []byte) error {
+	return s.put(key, payload, _idempotencyCompleted, s.ttl)
+}
+
+// Claim records key with payload as being processed if it is not recorded or expired,
+// returns ErrAlreadyProcessed, ErrInProgress or ErrPayloadMismatch if it is recorded.
+// The claim expires after the lease unless Complete is called, Delete releases it.
+func (s *IdempotencyStore) Claim(key string, payload []byte) error {
+	return s.put(key, payload, _idempotencyInProgress, s.lease)
+}
+
+// Complete records key with payload as processed, whether it was claimed or not.
+func (s *IdempotencyStore) Complete(key string, payload []byte) error {
+	item, err := s.record(key, payload, _idempotencyCompleted, s.ttl)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.w.api().PutItem(s.w.DdbCtx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.w.TableName),
+		Item:      item,
+	})
+
+	return err
+}
+
+func (s *IdempotencyStore) record(key string, payload []byte, status string, ttl time.Duration) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(idempotencyRecord{
+		PayloadHash: payloadHash(payload),
+		Status:      status,
+		ExpiresAt:   time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item[s.keyName] = &types.AttributeValueMemberS{Value: key}
+
+	return item, nil
+}
+
+func (s *IdempotencyStore) put(key string, payload []byte, status string, ttl time.Duration) error {
+	now := time.Now()
+
+	item, err := s.record(key, payload, status, ttl)
+	if err != nil {
+		return err
+	}
+
+	cond := expression.AttributeNotExists(expression.Name(s.keyName)).
+		Or(expression.Name("expires_at").LessThanEqual(expression.Value(now.Unix())))
+
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.w.api().PutItem(s.w.DdbCtx, &dynamodb.PutItemInput{
+		TableName:                           aws.String(s.w.TableName),
+		Item:                                item,
+		ConditionExpression:                 expr.Condition(),
+		ExpressionAttributeNames:            expr.Names(),
+		ExpressionAttributeValues:           expr.Values(),
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+
+	var ccf *types.ConditionalCheckFailedException
+	if !errors.As(err, &ccf) {
+		return err
+	}
+
+	rec := idempotencyRecord{}
+	if err := attributevalue.UnmarshalMap(ccf.Item, &rec); err != nil {
+		return err
+	}
+
+	return s.compare(key, rec, payloadHash(payload))
+}
+
+// Check reports whether key is recorded as completed and not expired, returns ErrInProgress
+// if it is claimed, or ErrPayloadMismatch if it is recorded with another payload.
+func (s *IdempotencyStore) Check(key string, payload []byte) (bool, error) {
+	rec := idempotencyRecord{}
+	if err := s.w.GetItem(s.key(key), &rec); err != nil {
+		return false, err
+	}
+
+	if rec.PayloadHash == "" || rec.ExpiresAt <= time.Now().Unix() {
+		return false, nil
+	}
+
+	if err := s.compare(key, rec, payloadHash(payload)); !errors.Is(err, ErrAlreadyProcessed) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Delete removes the record or claim of key, so it can be processed again, e.g. after processing failed.
+func (s *IdempotencyStore) Delete(key string) error {
+	return s.w.DeleteRow(s.key(key))
+}
+
+func (s *IdempotencyStore) compare(key string, rec idempotencyRecord, hash string) error {
+	if rec.PayloadHash != hash {
+		return fmt.Errorf("%w: %s", ErrPayloadMismatch, key)
+	}
+
+	if rec.Status == _idempotencyInProgress {
+		return fmt.Errorf("%w: %s", ErrInProgress, key)
+	}
+
+	return fmt.Errorf("%w: %s", ErrAlreadyProcessed, key)
+}
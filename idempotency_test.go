@@ -0,0 +1,53 @@
+package xaws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencySuite struct {
+	suite.Suite
+}
+
+func TestIdempotency(t *testing.T) {
+	suite.Run(t, new(IdempotencySuite))
+}
+
+func (s *IdempotencySuite) Test_01_claim() {
+	store := NewIdempotencyStore(NewDynamodbWrapperWithClient("idem", newMemDdbSdk("id", ""), 0, 0), "id", time.Hour)
+	body := []byte("order-1")
+
+	s.Nil(store.Claim("k", body))
+	s.ErrorIs(store.Claim("k", body), ErrInProgress)
+
+	ok, err := store.Check("k", body)
+	s.False(ok)
+	s.ErrorIs(err, ErrInProgress)
+
+	// a failed processing releases the claim.
+	s.Nil(store.Delete("k"))
+	s.Nil(store.Claim("k", body))
+
+	s.Nil(store.Complete("k", body))
+	s.ErrorIs(store.Claim("k", body), ErrAlreadyProcessed)
+	s.ErrorIs(store.Claim("k", []byte("other")), ErrPayloadMismatch)
+
+	ok, err = store.Check("k", body)
+	s.True(ok)
+	s.Nil(err)
+}
+
+func (s *IdempotencySuite) Test_02_leaseExpired() {
+	store := NewIdempotencyStore(NewDynamodbWrapperWithClient("idem", newMemDdbSdk("id", ""), 0, 0), "id", time.Hour)
+	body := []byte("order-1")
+
+	// the claim of a crashed consumer expires, so the message is processed again.
+	store.SetLease(-time.Second)
+	s.Nil(store.Claim("k", body))
+
+	store.SetLease(time.Minute)
+	s.Nil(store.Claim("k", body))
+	s.ErrorIs(store.Claim("k", body), ErrInProgress)
+}
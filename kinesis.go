@@ -0,0 +1,419 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+const (
+	// _putRecordsMaxCount and _putRecordsMaxBytes are the limits of a PutRecords call.
+	_putRecordsMaxCount = 500
+	_putRecordsMaxBytes = 5 * 1024 * 1024
+
+	_kinesisPollInterval = time.Second
+	_streamActiveTimeout = 5 * time.Minute
+)
+
+var ErrPutRecordsFailed = errors.New("failed to put records")
+
+type KinesisWrapper struct {
+	client *kinesis.Client
+
+	StreamName string
+
+	logger Logger
+}
+
+func NewKinesisWrapper(stream string, cfg aws.Config, opts ...ClientOptFunc) *KinesisWrapper {
+	opt := newClientOpts(opts...)
+
+	return &KinesisWrapper{
+		client:     kinesis.NewFromConfig(opt.apply(cfg)),
+		StreamName: stream,
+		logger:     opt.logger,
+	}
+}
+
+func NewKinesisWrapperWithDefaultConfig(stream string, opts ...ClientOptFunc) (*KinesisWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKinesisWrapper(stream, cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *KinesisWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *KinesisWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// CreateStream creates the stream with shards and waits until it is active,
+// shards 0 creates an on-demand stream.
+func (w *KinesisWrapper) CreateStream(shards int32) error {
+	input := &kinesis.CreateStreamInput{
+		StreamName: aws.String(w.StreamName),
+		StreamModeDetails: &types.StreamModeDetails{
+			StreamMode: types.StreamModeOnDemand,
+		},
+	}
+
+	if shards > 0 {
+		input.ShardCount = aws.Int32(shards)
+		input.StreamModeDetails.StreamMode = types.StreamModeProvisioned
+	}
+
+	if _, err := w.client.CreateStream(context.TODO(), input); err != nil {
+		return fmt.Errorf("cannot create stream %s: %w", w.StreamName, err)
+	}
+
+	waiter := kinesis.NewStreamExistsWaiter(w.client)
+
+	return waiter.Wait(context.TODO(), &kinesis.DescribeStreamInput{
+		StreamName: aws.String(w.StreamName),
+	}, _streamActiveTimeout)
+}
+
+// DescribeStream returns the summary of the stream, e.g. status, mode and open shard count.
+func (w *KinesisWrapper) DescribeStream() (*types.StreamDescriptionSummary, error) {
+	output, err := w.client.DescribeStreamSummary(context.TODO(), &kinesis.DescribeStreamSummaryInput{
+		StreamName: aws.String(w.StreamName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe stream %s: %w", w.StreamName, err)
+	}
+
+	return output.StreamDescriptionSummary, nil
+}
+
+// DeleteStream deletes the stream.
+func (w *KinesisWrapper) DeleteStream() error {
+	_, err := w.client.DeleteStream(context.TODO(), &kinesis.DeleteStreamInput{
+		StreamName: aws.String(w.StreamName),
+	})
+
+	return err
+}
+
+// ListShards returns all shards of the stream, closed shards included.
+func (w *KinesisWrapper) ListShards() ([]types.Shard, error) {
+	input := &kinesis.ListShardsInput{StreamName: aws.String(w.StreamName)}
+
+	var shards []types.Shard
+
+	for {
+		output, err := w.client.ListShards(context.TODO(), input)
+		if err != nil {
+			return shards, fmt.Errorf("cannot list shards of %s: %w", w.StreamName, err)
+		}
+
+		shards = append(shards, output.Shards...)
+
+		if output.NextToken == nil {
+			return shards, nil
+		}
+
+		// StreamName must not be set with NextToken.
+		input = &kinesis.ListShardsInput{NextToken: output.NextToken}
+	}
+}
+
+// PutRecord puts data to the shard selected by partitionKey, returns the shard id and sequence number.
+func (w *KinesisWrapper) PutRecord(partitionKey string, data []byte) (string, string, error) {
+	output, err := w.client.PutRecord(context.TODO(), &kinesis.PutRecordInput{
+		StreamName:   aws.String(w.StreamName),
+		PartitionKey: aws.String(partitionKey),
+		Data:         data,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("cannot put record to %s: %w", w.StreamName, err)
+	}
+
+	return aws.ToString(output.ShardId), aws.ToString(output.SequenceNumber), nil
+}
+
+// KinesisRecord is a record of PutRecords.
+type KinesisRecord struct {
+	PartitionKey string
+	Data         []byte
+}
+
+// PutRecords puts records in batches of up to 500 records and 5MB, returns the number of records accepted,
+// records rejected by Kinesis are reported in the returned error.
+//
+// Usage:
+//
+//	n, err := w.PutRecords([]KinesisRecord{{PartitionKey: userID, Data: raw}})
+func (w *KinesisWrapper) PutRecords(records []KinesisRecord) (int, error) {
+	sent := 0
+
+	var errs []error
+
+	for start := 0; start < len(records); {
+		end, size := start, 0
+
+		for end < len(records) && end-start < _putRecordsMaxCount {
+			n := len(records[end].Data) + len(records[end].PartitionKey)
+			if end > start && size+n > _putRecordsMaxBytes {
+				break
+			}
+
+			size += n
+			end++
+		}
+
+		entries := make([]types.PutRecordsRequestEntry, 0, end-start)
+		for _, r := range records[start:end] {
+			entries = append(entries, types.PutRecordsRequestEntry{
+				PartitionKey: aws.String(r.PartitionKey),
+				Data:         r.Data,
+			})
+		}
+
+		output, err := w.client.PutRecords(context.TODO(), &kinesis.PutRecordsInput{
+			StreamName: aws.String(w.StreamName),
+			Records:    entries,
+		})
+		if err != nil {
+			return sent, err
+		}
+
+		for i, result := range output.Records {
+			if result.ErrorCode == nil {
+				sent++
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("%w: record %d: %s %s",
+				ErrPutRecordsFailed, start+i, aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage)))
+		}
+
+		start = end
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// Consume reads all shards of the stream concurrently and calls handler with each record,
+// until ctx is done, all shards are closed, or handler / checkpoint returns an error.
+//
+// A shard is resumed after the sequence number from WithCheckpointLoader, otherwise it is read from
+// WithIteratorType, and WithCheckpoint is called after each batch of records.
+// After resharding, a child shard is read once all its parents are drained, so the records of
+// a partition key are handled in order. Child shards are followed from the closed parents,
+// the consumer keeps running across reshards without listing the shards again.
+//
+// Usage:
+//
+//	err := w.Consume(ctx, func(rec types.Record) error {
+//		return process(rec.Data)
+//	}, WithCheckpointLoader(store.Load), WithCheckpoint(store.Save))
+func (w *KinesisWrapper) Consume(ctx context.Context, handler func(rec types.Record) error, opts ...KinesisOptFunc) error {
+	opt := &KinesisOpts{
+		iteratorType: types.ShardIteratorTypeTrimHorizon,
+		pollInterval: _kinesisPollInterval,
+	}
+	bindKinesisOpts(opt, opts...)
+
+	shards, err := w.ListShards()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+
+		// parents of each known shard, a parent that is not known has expired and counts as drained.
+		parents = make(map[string][]string, len(shards))
+		started = make(map[string]bool, len(shards))
+		drained = make(map[string]bool, len(shards))
+	)
+
+	for _, shard := range shards {
+		parents[aws.ToString(shard.ShardId)] = shardParents(aws.ToString(shard.ParentShardId), aws.ToString(shard.AdjacentParentShardId))
+	}
+
+	var start func(shardID string)
+
+	// startReady starts the known shards whose parents are all drained, mu must be held.
+	startReady := func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		for shardID, ps := range parents {
+			if started[shardID] {
+				continue
+			}
+
+			ready := true
+
+			for _, p := range ps {
+				if _, known := parents[p]; known && !drained[p] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				started[shardID] = true
+				start(shardID)
+			}
+		}
+	}
+
+	start = func(shardID string) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			children, closed, err := w.consumeShard(ctx, shardID, handler, opt)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("shard %s: %w", shardID, err))
+
+				cancel()
+
+				return
+			}
+
+			if !closed {
+				return
+			}
+
+			drained[shardID] = true
+
+			for _, child := range children {
+				if id := aws.ToString(child.ShardId); id != "" {
+					if _, known := parents[id]; !known {
+						parents[id] = child.ParentShards
+					}
+				}
+			}
+
+			startReady()
+		}()
+	}
+
+	mu.Lock()
+	startReady()
+	mu.Unlock()
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func shardParents(ids ...string) []string {
+	parents := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		if id != "" {
+			parents = append(parents, id)
+		}
+	}
+
+	return parents
+}
+
+// consumeShard reads shard until it is closed or ctx is done, closed reports whether the shard is drained,
+// with the child shards returned by its last read.
+func (w *KinesisWrapper) consumeShard(ctx context.Context, shardID string, handler func(rec types.Record) error, opt *KinesisOpts) ([]types.ChildShard, bool, error) {
+	input := &kinesis.GetShardIteratorInput{
+		StreamName:        aws.String(w.StreamName),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: opt.iteratorType,
+	}
+
+	if opt.loader != nil {
+		seq, err := opt.loader(shardID)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot load checkpoint: %w", err)
+		}
+
+		if seq != "" {
+			input.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+			input.StartingSequenceNumber = aws.String(seq)
+		}
+	}
+
+	it, err := w.client.GetShardIterator(ctx, input)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("cannot get shard iterator: %w", err)
+	}
+
+	var children []types.ChildShard
+
+	iterator := it.ShardIterator
+
+	for iterator != nil {
+		if ctx.Err() != nil {
+			return nil, false, nil
+		}
+
+		records := &kinesis.GetRecordsInput{ShardIterator: iterator}
+		if opt.limit > 0 {
+			records.Limit = aws.Int32(opt.limit)
+		}
+
+		output, err := w.client.GetRecords(ctx, records)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, false, nil
+			}
+
+			return nil, false, fmt.Errorf("cannot get records: %w", err)
+		}
+
+		for _, rec := range output.Records {
+			if err := handler(rec); err != nil {
+				return nil, false, err
+			}
+		}
+
+		if n := len(output.Records); n > 0 && opt.checkpoint != nil {
+			if err := opt.checkpoint(shardID, aws.ToString(output.Records[n-1].SequenceNumber)); err != nil {
+				return nil, false, fmt.Errorf("cannot checkpoint: %w", err)
+			}
+		}
+
+		iterator = output.NextShardIterator
+		children = output.ChildShards
+
+		if iterator != nil && len(output.Records) == 0 && aws.ToInt64(output.MillisBehindLatest) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, false, nil
+			case <-time.After(opt.pollInterval):
+			}
+		}
+	}
+
+	w.log().Info("shard closed", "stream", w.StreamName, "shard", shardID, "children", len(children))
+
+	return children, true, nil
+}
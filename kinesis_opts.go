@@ -0,0 +1,66 @@
+package xaws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// KinesisCheckpoint is called with the sequence number of the last handled record of shard,
+// after each batch of records is handled.
+type KinesisCheckpoint func(shardID, sequenceNumber string) error
+
+// KinesisCheckpointLoader returns the checkpointed sequence number of shard, empty if none.
+type KinesisCheckpointLoader func(shardID string) (string, error)
+
+type KinesisOpts struct {
+	iteratorType types.ShardIteratorType
+	checkpoint   KinesisCheckpoint
+	loader       KinesisCheckpointLoader
+
+	pollInterval time.Duration
+	limit        int32
+}
+
+type KinesisOptFunc func(o *KinesisOpts)
+
+func bindKinesisOpts(opt *KinesisOpts, opts ...KinesisOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithIteratorType sets where to read a shard without checkpoint, TRIM_HORIZON by default.
+func WithIteratorType(t types.ShardIteratorType) KinesisOptFunc {
+	return func(o *KinesisOpts) {
+		o.iteratorType = t
+	}
+}
+
+// WithCheckpoint sets the callback to save progress, a returned error stops the consumer.
+func WithCheckpoint(fn KinesisCheckpoint) KinesisOptFunc {
+	return func(o *KinesisOpts) {
+		o.checkpoint = fn
+	}
+}
+
+// WithCheckpointLoader sets the callback to resume each shard after its checkpointed sequence number.
+func WithCheckpointLoader(fn KinesisCheckpointLoader) KinesisOptFunc {
+	return func(o *KinesisOpts) {
+		o.loader = fn
+	}
+}
+
+// WithPollInterval sets the wait between reads of a shard without new records, 1s by default.
+func WithPollInterval(d time.Duration) KinesisOptFunc {
+	return func(o *KinesisOpts) {
+		o.pollInterval = d
+	}
+}
+
+// WithRecordLimit sets the max records of each read, up to 10000.
+func WithRecordLimit(n int32) KinesisOptFunc {
+	return func(o *KinesisOpts) {
+		o.limit = n
+	}
+}
@@ -3,48 +3,56 @@ package xaws
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/coghost/xpretty"
-	"github.com/rs/zerolog/log"
 )
 
 type FunctionWrapper struct {
 	client *lambda.Client
+	logs   *cloudwatchlogs.Client
 
 	funcName  string
 	dryRun    bool
 	asyncMode bool
+
+	dryRunHook DryRunHook
+	logger     Logger
 }
 
-func NewFunctionWrapper(funcName string, dryRun bool, cfg aws.Config) (*FunctionWrapper, error) {
-	c := lambda.NewFromConfig(cfg, func(o *lambda.Options) {
-		o.RetryMaxAttempts = 0
-	})
+// NewFunctionWrapper creates a wrapper of funcName, the lambda and logs clients don't retry unless
+// WithRetryPolicy is set, since invoke is not idempotent.
+func NewFunctionWrapper(funcName string, dryRun bool, cfg aws.Config, opts ...ClientOptFunc) (*FunctionWrapper, error) {
+	opt := ClientOpts{retryPolicy: &NoRetryPolicy}
+	bindClientOpts(&opt, opts...)
+
+	cfg = opt.apply(cfg)
 
 	return &FunctionWrapper{
-		client:   c,
+		client:   lambda.NewFromConfig(cfg),
+		logs:     cloudwatchlogs.NewFromConfig(cfg),
 		funcName: funcName,
 		dryRun:   dryRun,
+		logger:   opt.logger,
 	}, nil
 }
 
-func NewFunctionWrapperWithDefaultConfig(funcName string, dryRun bool) (*FunctionWrapper, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), func(lo *config.LoadOptions) error {
-		lo.RetryMaxAttempts = 0
-		return nil
-	})
+func NewFunctionWrapperWithDefaultConfig(funcName string, dryRun bool, opts ...ClientOptFunc) (*FunctionWrapper, error) {
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewFunctionWrapper(funcName, dryRun, cfg)
+	return NewFunctionWrapper(funcName, dryRun, cfg, opts...)
 }
 
 // GetConfig gets data about function.
@@ -89,6 +97,79 @@ func (w *FunctionWrapper) InvokeAsync(payload []byte, getLog bool) (*lambda.Invo
 	return w.Invoke(payload, false, false)
 }
 
+// AsyncInvokeResult is the result of one payload of InvokeAsyncBatch.
+type AsyncInvokeResult struct {
+	// StatusCode is 202 when the event is accepted.
+	StatusCode int32
+	Err        error
+}
+
+// InvokeAsyncBatch invokes the function asynchronously (Event type) once per payload,
+// with at most concurrency invocations in flight.
+//
+// Results are in the same order as payloads, use FailedAsyncInvokes to pick failures.
+func (w *FunctionWrapper) InvokeAsyncBatch(payloads [][]byte, concurrency int) []AsyncInvokeResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]AsyncInvokeResult, len(payloads))
+
+	if w.dryRun {
+		for _, payload := range payloads {
+			w.doDryRun("invoke async", string(payload))
+		}
+
+		return results
+	}
+
+	var wg sync.WaitGroup
+
+	sem := make(chan struct{}, concurrency)
+
+	for i, payload := range payloads {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(i int, payload []byte) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			output, err := w.client.Invoke(context.TODO(), &lambda.InvokeInput{
+				FunctionName:   aws.String(w.funcName),
+				Payload:        payload,
+				InvocationType: types.InvocationTypeEvent,
+			})
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+
+			results[i].StatusCode = output.StatusCode
+		}(i, payload)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// FailedAsyncInvokes returns the index of payloads failed in results and their errors.
+func FailedAsyncInvokes(results []AsyncInvokeResult) map[int]error {
+	failed := make(map[int]error)
+
+	for i, r := range results {
+		if r.Err != nil {
+			failed[i] = r.Err
+		}
+	}
+
+	return failed
+}
+
 func (w *FunctionWrapper) Invoke(payload []byte, getLog bool, asyncMode bool) (*lambda.InvokeOutput, error) {
 	logType := types.LogTypeNone
 	if getLog {
@@ -118,19 +199,85 @@ func (w *FunctionWrapper) Invoke(payload []byte, getLog bool, asyncMode bool) (*
 	return output, err
 }
 
+// LambdaExecutionError is the error payload returned by a function failed with FunctionError.
+type LambdaExecutionError struct {
+	// FunctionError is "Unhandled" for runtime errors, or "Handled" for errors returned by handler.
+	FunctionError string   `json:"-"`
+	ErrorMessage  string   `json:"errorMessage"`
+	ErrorType     string   `json:"errorType"`
+	StackTrace    []string `json:"stackTrace,omitempty"`
+}
+
+func (e *LambdaExecutionError) Error() string {
+	return fmt.Sprintf("lambda %s error: %s: %s", e.FunctionError, e.ErrorType, e.ErrorMessage)
+}
+
+// InvokeJSON invokes the function synchronously with request marshaled as JSON,
+// and unmarshals the successful payload into response (skipped if response is nil).
+//
+// When the function fails, a *LambdaExecutionError decoded from the error payload is returned.
+//
+// Usage:
+//
+//	var resp Result
+//	err := w.InvokeJSON(ctx, Req{ID: 1}, &resp)
+//	var lerr *LambdaExecutionError
+//	if errors.As(err, &lerr) {
+//		log.Error().Str("type", lerr.ErrorType).Msg(lerr.ErrorMessage)
+//	}
+func (w *FunctionWrapper) InvokeJSON(ctx context.Context, request any, response any) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("cannot marshal request: %w", err)
+	}
+
+	if w.dryRun {
+		w.doDryRun("invoke", string(payload))
+		return nil
+	}
+
+	output, err := w.client.Invoke(ctx, &lambda.InvokeInput{
+		FunctionName:   aws.String(w.funcName),
+		Payload:        payload,
+		InvocationType: types.InvocationTypeRequestResponse,
+	})
+	if err != nil {
+		return err
+	}
+
+	if output.FunctionError != nil {
+		lerr := &LambdaExecutionError{FunctionError: *output.FunctionError}
+		if err := json.Unmarshal(output.Payload, lerr); err != nil {
+			lerr.ErrorMessage = string(output.Payload)
+		}
+
+		return lerr
+	}
+
+	if response == nil || len(output.Payload) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(output.Payload, response); err != nil {
+		return fmt.Errorf("cannot unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
 func (w *FunctionWrapper) PrintInvokeOutput(output *lambda.InvokeOutput) {
 	if output == nil || w.asyncMode {
 		return
 	}
 
 	if output.LogResult == nil {
-		log.Warn().Msg("log result is nil")
+		w.log().Warn("log result is nil")
 		return
 	}
 
 	raw, err := base64.StdEncoding.DecodeString(*output.LogResult)
 	if err != nil {
-		log.Error().Err(err).Msg("cannot decode log result.")
+		w.log().Error("cannot decode log result", "error", err)
 	}
 
 	const (
@@ -148,56 +295,226 @@ func (w *FunctionWrapper) PrintInvokeOutput(output *lambda.InvokeOutput) {
 	xpretty.CyanPrintf("%[1]s >Lambda Output< %[1]s\n", strings.Repeat("=", hintlen))
 }
 
-// doDryRun only prints the func name and its command.
-func (w *FunctionWrapper) doDryRun(name, cmd string) {
-	const hintlen = 32
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *FunctionWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *FunctionWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// SetDryRunHook sets hook to capture actions skipped in dry-run mode,
+// the actions are still logged.
+func (w *FunctionWrapper) SetDryRunHook(hook DryRunHook) {
+	w.dryRunHook = hook
+}
+
+// DryRun tells if the wrapper is in dry-run mode.
+func (w *FunctionWrapper) DryRun() bool {
+	return w.dryRun
+}
 
-	xpretty.CyanPrintf("%[1]s >Dry Run< %[1]s\n", strings.Repeat("=", hintlen))
-	xpretty.YellowPrintf("[%s]: %s\n", name, cmd)
-	xpretty.CyanPrintf("%[1]s >Dry Run< %[1]s\n", strings.Repeat("=", hintlen))
+// doDryRun logs the action with a summary of its input, see dryRunSummary,
+// and passes the raw input to the dry-run hook if set.
+func (w *FunctionWrapper) doDryRun(name string, input any) {
+	w.log().Info("dry run", "function", w.funcName, "action", name, "input", dryRunSummary(input))
+
+	if w.dryRunHook != nil {
+		w.dryRunHook(DryRunRecord{
+			Service: "lambda",
+			Action:  name,
+			Target:  w.funcName,
+			Input:   input,
+			At:      time.Now(),
+		})
+	}
 }
 
 const (
 	lambdaTimeout = 900
+	lambdaMemory  = 512
 )
 
-func (w *FunctionWrapper) Create(functionName string, handlerName string, iamRoleArn *string, data []byte) types.State {
-	var state types.State
+var ErrInvalidCodeSource = errors.New("invalid code source")
+
+// CreateOptions configures CreateWithOptions, zero values use the defaults of Create:
+// provided.al2 runtime on arm64 with 512MB memory and 900s timeout.
+//
+// Exactly one code source must be set: ZipFile, S3Bucket/S3Key or ImageURI, otherwise
+// CreateWithOptions fails with ErrInvalidCodeSource.
+type CreateOptions struct {
+	// Code source
+	ZipFile  []byte
+	S3Bucket string
+	S3Key    string
+	// ImageURI is an ECR image uri, Runtime and Handler are ignored for container functions.
+	ImageURI string
+
+	Handler      string
+	Runtime      types.Runtime
+	Architecture types.Architecture
+	MemoryMB     int
+	TimeoutSecs  int
+	Description  string
+
+	Env       map[string]string
+	Layers    []string
+	VpcConfig *types.VpcConfig
+
+	// DisablePublish skips publishing version 1 on creation.
+	DisablePublish bool
+}
+
+// validate checks that exactly one code source is set, and the S3 source has both bucket and key.
+func (o CreateOptions) validate() error {
+	sources := 0
 
-	var mem int32 = 512
+	for _, set := range []bool{len(o.ZipFile) != 0, o.S3Bucket != "" || o.S3Key != "", o.ImageURI != ""} {
+		if set {
+			sources++
+		}
+	}
+
+	switch {
+	case sources != 1:
+		return fmt.Errorf("%w: %d of zip file, s3 object and image uri are set, want exactly 1", ErrInvalidCodeSource, sources)
+	case (o.S3Bucket == "") != (o.S3Key == ""):
+		return fmt.Errorf("%w: s3 bucket and key must be set together", ErrInvalidCodeSource)
+	}
+
+	return nil
+}
+
+func (o CreateOptions) toInput(functionName, roleArn string) *lambda.CreateFunctionInput {
+	arch := o.Architecture
+	if arch == "" {
+		arch = types.ArchitectureArm64
+	}
 
-	_, err := w.client.CreateFunction(context.TODO(), &lambda.CreateFunctionInput{
-		Code:          &types.FunctionCode{ZipFile: data},
+	mem, timeout := o.MemoryMB, o.TimeoutSecs
+	if mem == 0 {
+		mem = lambdaMemory
+	}
+
+	if timeout == 0 {
+		timeout = lambdaTimeout
+	}
+
+	input := &lambda.CreateFunctionInput{
 		FunctionName:  aws.String(functionName),
-		Role:          iamRoleArn,
-		Handler:       aws.String(handlerName),
-		Architectures: []types.Architecture{types.ArchitectureArm64},
-		Publish:       true,
-		Runtime:       types.RuntimeProvidedal2,
-		Timeout:       aws.Int32(lambdaTimeout),
-		MemorySize:    aws.Int32(mem),
+		Role:          aws.String(roleArn),
+		Architectures: []types.Architecture{arch},
+		Publish:       !o.DisablePublish,
+		Timeout:       aws.Int32(int32(timeout)),
+		MemorySize:    aws.Int32(int32(mem)),
+		Layers:        o.Layers,
+		VpcConfig:     o.VpcConfig,
+	}
+
+	if o.Description != "" {
+		input.Description = aws.String(o.Description)
+	}
+
+	if len(o.Env) != 0 {
+		input.Environment = &types.Environment{Variables: o.Env}
+	}
+
+	switch {
+	case o.ImageURI != "":
+		input.PackageType = types.PackageTypeImage
+		input.Code = &types.FunctionCode{ImageUri: aws.String(o.ImageURI)}
+
+		return input
+	case o.S3Bucket != "":
+		input.Code = &types.FunctionCode{S3Bucket: aws.String(o.S3Bucket), S3Key: aws.String(o.S3Key)}
+	default:
+		input.Code = &types.FunctionCode{ZipFile: o.ZipFile}
+	}
+
+	runtime := o.Runtime
+	if runtime == "" {
+		runtime = types.RuntimeProvidedal2
+	}
+
+	input.PackageType = types.PackageTypeZip
+	input.Runtime = runtime
+	input.Handler = aws.String(o.Handler)
+
+	return input
+}
+
+// CreateWithOptions creates a function and waits until it is active, returns its state.
+//
+// Usage:
+//
+//	state, err := w.CreateWithOptions("my-func", roleArn, CreateOptions{
+//		ImageURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com/my-func:latest",
+//		MemoryMB: 1024,
+//		Env:      map[string]string{"STAGE": "prod"},
+//	})
+func (w *FunctionWrapper) CreateWithOptions(functionName, roleArn string, opts CreateOptions) (types.State, error) {
+	if err := opts.validate(); err != nil {
+		return "", err
+	}
+
+	input := opts.toInput(functionName, roleArn)
+
+	if w.dryRun {
+		w.doDryRun("create", input)
+		return types.StateActive, nil
+	}
+
+	_, err := w.client.CreateFunction(context.TODO(), input)
+	if err != nil {
+		return "", err
+	}
+
+	waiter := lambda.NewFunctionActiveV2Waiter(w.client)
+
+	funcOutput, err := waiter.WaitForOutput(context.TODO(), &lambda.GetFunctionInput{
+		FunctionName: aws.String(functionName),
+	}, 1*time.Minute)
+	if err != nil {
+		return "", fmt.Errorf("couldn't wait for function %v to be active: %w", functionName, err)
+	}
+
+	return funcOutput.Configuration.State, nil
+}
+
+// Create creates function from a zip, an existing function is reported as active,
+// it panics on other errors like the Must* helpers, see CreateWithOptions to handle them.
+func (w *FunctionWrapper) Create(functionName string, handlerName string, iamRoleArn *string, data []byte) types.State {
+	state, err := w.CreateWithOptions(functionName, aws.ToString(iamRoleArn), CreateOptions{
+		ZipFile: data,
+		Handler: handlerName,
 	})
 	if err != nil {
 		var resConflict *types.ResourceConflictException
 		if errors.As(err, &resConflict) {
-			log.Printf("Function %v already exists.\n", functionName)
+			w.log().Info("function already exists", "function", functionName)
 
-			state = types.StateActive
-		} else {
-			log.Fatal().Msgf("Couldn't create function %v. Here's why: %v\n", functionName, err)
+			return types.StateActive
 		}
-	} else {
-		waiter := lambda.NewFunctionActiveV2Waiter(w.client)
-		funcOutput, err := waiter.WaitForOutput(context.TODO(), &lambda.GetFunctionInput{
-			FunctionName: aws.String(functionName),
-		}, 1*time.Minute)
 
-		if err != nil {
-			log.Fatal().Msgf("Couldn't wait for function %v to be active. Here's why: %v\n", functionName, err)
-		} else {
-			state = funcOutput.Configuration.State
-		}
+		panic(fmt.Errorf("cannot create function %s: %w", functionName, err))
 	}
 
 	return state
 }
+
+// DeleteFunction deletes function, including all its versions and aliases.
+func (w *FunctionWrapper) DeleteFunction() error {
+	input := &lambda.DeleteFunctionInput{
+		FunctionName: aws.String(w.funcName),
+	}
+
+	if w.dryRun {
+		w.doDryRun("delete", input)
+		return nil
+	}
+
+	_, err := w.client.DeleteFunction(context.TODO(), input)
+
+	return err
+}
@@ -0,0 +1,98 @@
+package xaws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// UpdateConfig updates function configuration with input and waits until the update finished,
+// FunctionName of input is always set to the wrapper's function.
+func (w *FunctionWrapper) UpdateConfig(input *lambda.UpdateFunctionConfigurationInput) error {
+	input.FunctionName = aws.String(w.funcName)
+
+	if w.dryRun {
+		w.doDryRun("update config", input)
+		return nil
+	}
+
+	if _, err := w.client.UpdateFunctionConfiguration(context.TODO(), input); err != nil {
+		return err
+	}
+
+	return w.WaitUpdated()
+}
+
+// SetEnv replaces all environment variables of function with env.
+func (w *FunctionWrapper) SetEnv(env map[string]string) error {
+	return w.UpdateConfig(&lambda.UpdateFunctionConfigurationInput{
+		Environment: &types.Environment{Variables: env},
+	})
+}
+
+// SetMemory sets memory size of function in MB (128-10240).
+func (w *FunctionWrapper) SetMemory(mb int) error {
+	return w.UpdateConfig(&lambda.UpdateFunctionConfigurationInput{
+		MemorySize: aws.Int32(int32(mb)),
+	})
+}
+
+// SetTimeout sets timeout of function in seconds (1-900).
+func (w *FunctionWrapper) SetTimeout(seconds int) error {
+	return w.UpdateConfig(&lambda.UpdateFunctionConfigurationInput{
+		Timeout: aws.Int32(int32(seconds)),
+	})
+}
+
+// PutReservedConcurrency reserves n concurrent executions for function, 0 disables the function.
+func (w *FunctionWrapper) PutReservedConcurrency(n int) error {
+	input := &lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 aws.String(w.funcName),
+		ReservedConcurrentExecutions: aws.Int32(int32(n)),
+	}
+
+	if w.dryRun {
+		w.doDryRun("put reserved concurrency", input)
+		return nil
+	}
+
+	_, err := w.client.PutFunctionConcurrency(context.TODO(), input)
+
+	return err
+}
+
+// DeleteReservedConcurrency removes the reserved concurrency of function.
+func (w *FunctionWrapper) DeleteReservedConcurrency() error {
+	input := &lambda.DeleteFunctionConcurrencyInput{
+		FunctionName: aws.String(w.funcName),
+	}
+
+	if w.dryRun {
+		w.doDryRun("delete reserved concurrency", input)
+		return nil
+	}
+
+	_, err := w.client.DeleteFunctionConcurrency(context.TODO(), input)
+
+	return err
+}
+
+// PutProvisionedConcurrency keeps n instances initialized for qualifier, an alias or version.
+func (w *FunctionWrapper) PutProvisionedConcurrency(qualifier string, n int) error {
+	input := &lambda.PutProvisionedConcurrencyConfigInput{
+		FunctionName:                    aws.String(w.funcName),
+		Qualifier:                       aws.String(qualifier),
+		ProvisionedConcurrentExecutions: aws.Int32(int32(n)),
+	}
+
+	if w.dryRun {
+		w.doDryRun("put provisioned concurrency", input)
+		return nil
+	}
+
+	_, err := w.client.PutProvisionedConcurrencyConfig(context.TODO(), input)
+
+	return err
+}
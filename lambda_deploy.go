@@ -0,0 +1,148 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+const (
+	_lambdaUpdateWait = 5 * time.Minute
+)
+
+// UpdateCode replaces the code of function with the zip package.
+func (w *FunctionWrapper) UpdateCode(zip []byte) error {
+	return w.updateCode(&lambda.UpdateFunctionCodeInput{
+		FunctionName: aws.String(w.funcName),
+		ZipFile:      zip,
+	})
+}
+
+// UpdateCodeFromS3 replaces the code of function with the zip package stored in s3.
+func (w *FunctionWrapper) UpdateCodeFromS3(bucket, key string) error {
+	return w.updateCode(&lambda.UpdateFunctionCodeInput{
+		FunctionName: aws.String(w.funcName),
+		S3Bucket:     aws.String(bucket),
+		S3Key:        aws.String(key),
+	})
+}
+
+func (w *FunctionWrapper) updateCode(input *lambda.UpdateFunctionCodeInput) error {
+	if w.dryRun {
+		w.doDryRun("update code", input)
+		return nil
+	}
+
+	_, err := w.client.UpdateFunctionCode(context.TODO(), input)
+	return err
+}
+
+// WaitUpdated waits until LastUpdateStatus of function is Successful.
+func (w *FunctionWrapper) WaitUpdated() error {
+	if w.dryRun {
+		return nil
+	}
+
+	waiter := lambda.NewFunctionUpdatedV2Waiter(w.client)
+
+	return waiter.Wait(context.TODO(), &lambda.GetFunctionInput{
+		FunctionName: aws.String(w.funcName),
+	}, _lambdaUpdateWait)
+}
+
+// PublishVersion publishes the current code and configuration as a new version, and returns the version.
+func (w *FunctionWrapper) PublishVersion(description string) (string, error) {
+	input := &lambda.PublishVersionInput{
+		FunctionName: aws.String(w.funcName),
+		Description:  aws.String(description),
+	}
+
+	if w.dryRun {
+		w.doDryRun("publish version", input)
+		return "", nil
+	}
+
+	output, err := w.client.PublishVersion(context.TODO(), input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.Version), nil
+}
+
+// CreateAlias creates alias name pointing to version.
+func (w *FunctionWrapper) CreateAlias(name, version string) error {
+	input := &lambda.CreateAliasInput{
+		FunctionName:    aws.String(w.funcName),
+		Name:            aws.String(name),
+		FunctionVersion: aws.String(version),
+	}
+
+	if w.dryRun {
+		w.doDryRun("create alias", input)
+		return nil
+	}
+
+	_, err := w.client.CreateAlias(context.TODO(), input)
+
+	return err
+}
+
+// UpdateAlias points alias name to version.
+func (w *FunctionWrapper) UpdateAlias(name, version string) error {
+	input := &lambda.UpdateAliasInput{
+		FunctionName:    aws.String(w.funcName),
+		Name:            aws.String(name),
+		FunctionVersion: aws.String(version),
+	}
+
+	if w.dryRun {
+		w.doDryRun("update alias", input)
+		return nil
+	}
+
+	_, err := w.client.UpdateAlias(context.TODO(), input)
+
+	return err
+}
+
+// Deploy is a mini deployment flow:
+//  1. updates function code with zip
+//  2. waits for LastUpdateStatus=Successful
+//  3. publishes a new version
+//  4. points alias to the new version, alias is created if not exists
+//
+// Returns the published version.
+func (w *FunctionWrapper) Deploy(zip []byte, alias string) (string, error) {
+	if err := w.UpdateCode(zip); err != nil {
+		return "", err
+	}
+
+	if err := w.WaitUpdated(); err != nil {
+		return "", err
+	}
+
+	version, err := w.PublishVersion("")
+	if err != nil {
+		return "", err
+	}
+
+	err = w.UpdateAlias(alias, version)
+
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		err = w.CreateAlias(alias, version)
+	}
+
+	if err != nil {
+		return version, err
+	}
+
+	w.log().Info("deployed", "function", w.funcName, "version", version, "alias", alias)
+
+	return version, nil
+}
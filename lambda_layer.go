@@ -0,0 +1,134 @@
+package xaws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+// PublishLayerVersion publishes the zip package as a new version of layer, and returns the layer version arn.
+func (w *FunctionWrapper) PublishLayerVersion(layerName, description string, zip []byte, runtimes ...types.Runtime) (string, error) {
+	return w.publishLayerVersion(&lambda.PublishLayerVersionInput{
+		LayerName:          aws.String(layerName),
+		Description:        aws.String(description),
+		Content:            &types.LayerVersionContentInput{ZipFile: zip},
+		CompatibleRuntimes: runtimes,
+	})
+}
+
+// PublishLayerVersionFromS3 publishes the zip package stored in s3 as a new version of layer,
+// and returns the layer version arn.
+func (w *FunctionWrapper) PublishLayerVersionFromS3(layerName, description, bucket, key string, runtimes ...types.Runtime) (string, error) {
+	return w.publishLayerVersion(&lambda.PublishLayerVersionInput{
+		LayerName:   aws.String(layerName),
+		Description: aws.String(description),
+		Content: &types.LayerVersionContentInput{
+			S3Bucket: aws.String(bucket),
+			S3Key:    aws.String(key),
+		},
+		CompatibleRuntimes: runtimes,
+	})
+}
+
+func (w *FunctionWrapper) publishLayerVersion(input *lambda.PublishLayerVersionInput) (string, error) {
+	if w.dryRun {
+		w.doDryRun("publish layer version", input)
+		return "", nil
+	}
+
+	output, err := w.client.PublishLayerVersion(context.TODO(), input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.LayerVersionArn), nil
+}
+
+// ListLayerVersions lists all versions of layer, newest first.
+func (w *FunctionWrapper) ListLayerVersions(layerName string) ([]types.LayerVersionsListItem, error) {
+	var versions []types.LayerVersionsListItem
+
+	paginator := lambda.NewListLayerVersionsPaginator(w.client, &lambda.ListLayerVersionsInput{
+		LayerName: aws.String(layerName),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return versions, err
+		}
+
+		versions = append(versions, page.LayerVersions...)
+	}
+
+	return versions, nil
+}
+
+// DeleteLayerVersion deletes version of layer, functions using it keep working.
+func (w *FunctionWrapper) DeleteLayerVersion(layerName string, version int64) error {
+	input := &lambda.DeleteLayerVersionInput{
+		LayerName:     aws.String(layerName),
+		VersionNumber: aws.Int64(version),
+	}
+
+	if w.dryRun {
+		w.doDryRun("delete layer version", input)
+		return nil
+	}
+
+	_, err := w.client.DeleteLayerVersion(context.TODO(), input)
+
+	return err
+}
+
+// AttachLayers adds layer version arns to function, an attached version of the same layer
+// is replaced, other layers are kept in order.
+//
+// Usage:
+//
+//	arn, _ := w.PublishLayerVersion("deps", "", zip, types.RuntimeProvidedal2)
+//	err := w.AttachLayers(arn)
+func (w *FunctionWrapper) AttachLayers(layerVersionArns ...string) error {
+	cfg, err := w.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	added := make(map[string]string, len(layerVersionArns))
+	for _, arn := range layerVersionArns {
+		added[layerArnOf(arn)] = arn
+	}
+
+	layers := make([]string, 0, len(cfg.Layers)+len(layerVersionArns))
+
+	for _, layer := range cfg.Layers {
+		arn := aws.ToString(layer.Arn)
+		if _, ok := added[layerArnOf(arn)]; ok {
+			continue
+		}
+
+		layers = append(layers, arn)
+	}
+
+	layers = append(layers, layerVersionArns...)
+
+	return w.UpdateConfig(&lambda.UpdateFunctionConfigurationInput{
+		Layers: layers,
+	})
+}
+
+// layerArnOf strips the version from a layer version arn.
+func layerArnOf(layerVersionArn string) string {
+	// arn:aws:lambda:region:account:layer:name:version
+	const layerArnParts = 7
+
+	parts := strings.Split(layerVersionArn, ":")
+	if len(parts) > layerArnParts {
+		return strings.Join(parts[:layerArnParts], ":")
+	}
+
+	return layerVersionArn
+}
@@ -0,0 +1,177 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+const (
+	_logPollInterval = 2 * time.Second
+	_logFollowWait   = 2 * time.Minute
+
+	// _invocationLogSkew widens the search window of invocation logs for clock skew,
+	// _invocationLogSpan is the max run time of a function (15m) plus the skew.
+	_invocationLogSkew = time.Minute
+	_invocationLogSpan = 16 * time.Minute
+)
+
+var ErrInvocationLogsNotFound = errors.New("invocation logs not found")
+
+// LogLine is a log event of function.
+type LogLine struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// RequestIDOf returns the request id of an invocation, used to find its logs.
+func RequestIDOf(output *lambda.InvokeOutput) string {
+	if output == nil {
+		return ""
+	}
+
+	id, _ := awsmiddleware.GetRequestIDMetadata(output.ResultMetadata)
+
+	return id
+}
+
+// GetInvocationLogs returns the full logs of the invocation requestID from CloudWatch Logs,
+// unlike LogResult of InvokeSync which is limited to the last 4KB.
+//
+// The logs are searched from invokedAt, the time the invocation was sent, to the max run time of a function.
+// Logs are delivered with a delay, when follow is true it polls until "END RequestId" appears
+// (up to 2 minutes), otherwise it returns what is available right now.
+//
+// Usage:
+//
+//	invokedAt := time.Now()
+//	output, _ := w.InvokeSync(payload, false)
+//	lines, err := w.GetInvocationLogs(RequestIDOf(output), invokedAt, true)
+func (w *FunctionWrapper) GetInvocationLogs(requestID string, invokedAt time.Time, follow bool) ([]LogLine, error) {
+	ctx := context.TODO()
+	group := "/aws/lambda/" + w.funcName
+	deadline := time.Now().Add(_logFollowWait)
+
+	from := invokedAt.Add(-_invocationLogSkew).UnixMilli()
+	to := invokedAt.Add(_invocationLogSpan).UnixMilli()
+
+	var (
+		stream string
+		start  int64
+	)
+
+	for stream == "" {
+		var err error
+
+		stream, start, err = w.findInvocationStart(ctx, group, requestID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		if stream != "" {
+			break
+		}
+
+		if !follow || time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s", ErrInvocationLogsNotFound, requestID)
+		}
+
+		time.Sleep(_logPollInterval)
+	}
+
+	var (
+		lines     []LogLine
+		nextToken *string
+		started   bool
+	)
+
+	end := "END RequestId: " + requestID
+
+	for {
+		output, err := w.logs.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  aws.String(group),
+			LogStreamName: aws.String(stream),
+			StartTime:     aws.Int64(start),
+			EndTime:       aws.Int64(to),
+			StartFromHead: aws.Bool(true),
+			NextToken:     nextToken,
+		})
+		if err != nil {
+			return lines, err
+		}
+
+		for _, event := range output.Events {
+			msg := strings.TrimRight(aws.ToString(event.Message), "\n")
+
+			// a stream is shared by sequential invocations of the same instance.
+			if !started {
+				started = strings.Contains(msg, "START RequestId: "+requestID)
+				if !started {
+					continue
+				}
+			}
+
+			lines = append(lines, LogLine{
+				Timestamp: time.UnixMilli(aws.ToInt64(event.Timestamp)),
+				Message:   msg,
+			})
+
+			if strings.HasPrefix(msg, end) {
+				return lines, nil
+			}
+		}
+
+		samePage := aws.ToString(output.NextForwardToken) == aws.ToString(nextToken)
+		nextToken = output.NextForwardToken
+
+		if !samePage {
+			continue
+		}
+
+		if !follow || time.Now().After(deadline) {
+			return lines, nil
+		}
+
+		time.Sleep(_logPollInterval)
+	}
+}
+
+// findInvocationStart returns the stream and timestamp of the "START RequestId" line of requestID in [from, to],
+// the stream is empty if it is not found. A filtered page can be empty with more pages to read, so it follows NextToken.
+func (w *FunctionWrapper) findInvocationStart(ctx context.Context, group, requestID string, from, to int64) (string, int64, error) {
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName:  aws.String(group),
+		FilterPattern: aws.String(fmt.Sprintf(`"START RequestId: %s"`, requestID)),
+		StartTime:     aws.Int64(from),
+		EndTime:       aws.Int64(to),
+	}
+
+	for {
+		found, err := w.logs.FilterLogEvents(ctx, input)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if len(found.Events) != 0 {
+			return aws.ToString(found.Events[0].LogStreamName), aws.ToInt64(found.Events[0].Timestamp), nil
+		}
+
+		if found.NextToken == nil {
+			return "", 0, nil
+		}
+
+		input.NextToken = found.NextToken
+	}
+}
+
+// QueryLogs runs a Logs Insights query on the log group of function over [start, end], see LogsWrapper.Query.
+func (w *FunctionWrapper) QueryLogs(query string, start, end time.Time) ([]LogQueryRow, error) {
+	return newLogsWrapper(w.logs).Query([]string{"/aws/lambda/" + w.funcName}, query, start, end)
+}
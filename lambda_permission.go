@@ -0,0 +1,187 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+const (
+	_actionInvokeFunction = "lambda:InvokeFunction"
+
+	PrincipalEventBridge = "events.amazonaws.com"
+	PrincipalScheduler   = "scheduler.amazonaws.com"
+	PrincipalS3          = "s3.amazonaws.com"
+	PrincipalSNS         = "sns.amazonaws.com"
+	PrincipalSQS         = "sqs.amazonaws.com"
+)
+
+// AddPermission allows principal to invoke function from sourceArn,
+// it is required before EventBridge/S3/SNS etc. can trigger the function.
+//
+// An existing statement with the same statementID, principal and sourceArn is left untouched,
+// so it is safe to call repeatedly. A conflicting statement with the same statementID,
+// or a conflict like a function update in progress, is returned as error.
+//
+// Usage:
+//
+//	err := w.AddPermission("allow-nightly-rule", PrincipalEventBridge, ruleArn)
+func (w *FunctionWrapper) AddPermission(statementID, principal, sourceArn string) error {
+	input := &lambda.AddPermissionInput{
+		FunctionName: aws.String(w.funcName),
+		StatementId:  aws.String(statementID),
+		Action:       aws.String(_actionInvokeFunction),
+		Principal:    aws.String(principal),
+	}
+
+	if sourceArn != "" {
+		input.SourceArn = aws.String(sourceArn)
+	}
+
+	if w.dryRun {
+		w.doDryRun("add permission", input)
+		return nil
+	}
+
+	_, err := w.client.AddPermission(context.TODO(), input)
+
+	var conflict *types.ResourceConflictException
+	if !errors.As(err, &conflict) {
+		return err
+	}
+
+	statements, perr := w.GetPolicy()
+	if perr != nil {
+		return errors.Join(err, perr)
+	}
+
+	for _, statement := range statements {
+		if statement.Sid != statementID {
+			continue
+		}
+
+		if statement.grants(_actionInvokeFunction, principal, sourceArn) {
+			return nil
+		}
+
+		return fmt.Errorf("statement %s of %s grants another principal or source arn: %w", statementID, w.funcName, err)
+	}
+
+	return err
+}
+
+// RemovePermission removes statementID from the resource policy of function.
+func (w *FunctionWrapper) RemovePermission(statementID string) error {
+	input := &lambda.RemovePermissionInput{
+		FunctionName: aws.String(w.funcName),
+		StatementId:  aws.String(statementID),
+	}
+
+	if w.dryRun {
+		w.doDryRun("remove permission", input)
+		return nil
+	}
+
+	_, err := w.client.RemovePermission(context.TODO(), input)
+
+	return err
+}
+
+// PolicyStatement is a statement of the function's resource policy.
+type PolicyStatement struct {
+	Sid    string
+	Effect string
+	// Principal is keyed by principal type, e.g. {"Service": "events.amazonaws.com"},
+	// a wildcard principal is {"*": "*"}.
+	Principal map[string]string
+	Action    string
+	Resource  string
+	// Condition is keyed by operator then condition key, e.g. {"ArnLike": {"AWS:SourceArn": "arn:..."}}.
+	Condition map[string]map[string]string
+}
+
+// SourceArn returns the AWS:SourceArn condition of statement, empty if not set.
+func (s PolicyStatement) SourceArn() string {
+	for _, cond := range s.Condition {
+		if arn, ok := cond["AWS:SourceArn"]; ok {
+			return arn
+		}
+	}
+
+	return ""
+}
+
+// grants tells if statement allows principal to call action from sourceArn,
+// an account principal is stored as its root arn, e.g. "arn:aws:iam::123456789012:root".
+func (s PolicyStatement) grants(action, principal, sourceArn string) bool {
+	if s.Action != action || s.SourceArn() != sourceArn {
+		return false
+	}
+
+	for _, p := range s.Principal {
+		if p == principal || strings.HasSuffix(p, "::"+principal+":root") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *PolicyStatement) UnmarshalJSON(data []byte) error {
+	type statement PolicyStatement
+
+	var raw struct {
+		statement
+		Principal json.RawMessage
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = PolicyStatement(raw.statement)
+
+	if len(raw.Principal) == 0 {
+		return nil
+	}
+
+	var wildcard string
+	if err := json.Unmarshal(raw.Principal, &wildcard); err == nil {
+		s.Principal = map[string]string{wildcard: wildcard}
+		return nil
+	}
+
+	return json.Unmarshal(raw.Principal, &s.Principal)
+}
+
+// GetPolicy returns the statements of function's resource policy,
+// an empty list is returned when function has no policy.
+func (w *FunctionWrapper) GetPolicy() ([]PolicyStatement, error) {
+	output, err := w.client.GetPolicy(context.TODO(), &lambda.GetPolicyInput{
+		FunctionName: aws.String(w.funcName),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var policy struct {
+		Statement []PolicyStatement
+	}
+
+	if err := json.Unmarshal([]byte(aws.ToString(output.Policy)), &policy); err != nil {
+		return nil, fmt.Errorf("cannot parse policy of %s: %w", w.funcName, err)
+	}
+
+	return policy.Statement, nil
+}
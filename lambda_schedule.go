@@ -0,0 +1,133 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var ErrScheduleNotWired = errors.New("schedule is not wired to function")
+
+// ScheduleWithEventBridge triggers function by an EventBridge rule, it:
+//  1. creates or updates rule ruleName with schedule
+//  2. grants the rule lambda:InvokeFunction on function
+//  3. puts function as target of rule, invoked with payload
+//  4. verifies both the target and the permission are in place
+//
+// Usage:
+//
+//	err := fn.ScheduleWithEventBridge(events, "nightly-report", "cron(0 2 * * ? *)", `{"mode":"full"}`)
+func (w *FunctionWrapper) ScheduleWithEventBridge(events *EventWrapper, ruleName, schedule, payload string, opts ...EventOptFunc) error {
+	functionArn, functionName, err := w.functionArn()
+	if err != nil {
+		return err
+	}
+
+	if err := events.PutRule(ruleName, schedule, opts...); err != nil {
+		return fmt.Errorf("cannot put rule %s: %w", ruleName, err)
+	}
+
+	rule, err := events.DescribeRule(ruleName, opts...)
+	if err != nil {
+		return err
+	}
+
+	ruleArn := aws.ToString(rule.Arn)
+
+	if err := w.AddPermission(permissionStatementID(ruleName), PrincipalEventBridge, ruleArn); err != nil {
+		return fmt.Errorf("cannot grant invoke permission to rule %s: %w", ruleName, err)
+	}
+
+	if err := events.PutTarget(ruleName, functionArn, functionName, payload, opts...); err != nil {
+		return err
+	}
+
+	if err := w.verifyEventBridgeWiring(events, ruleName, ruleArn, functionArn, opts...); err != nil {
+		return err
+	}
+
+	w.log().Info("scheduled", "rule", ruleName, "function", functionArn, "schedule", schedule)
+
+	return nil
+}
+
+// ScheduleWithScheduler triggers function by an EventBridge Scheduler schedule, it:
+//  1. creates or updates schedule name with spec, invoked with payload
+//  2. verifies the schedule targets function
+//
+// The scheduler invokes function with roleArn, which must allow lambda:InvokeFunction,
+// so no resource policy is added to function.
+//
+// Usage:
+//
+//	err := fn.ScheduleWithScheduler(sched, "nightly-report", ScheduleCron("0 2 * * ? *"), roleArn, `{"mode":"full"}`)
+func (w *FunctionWrapper) ScheduleWithScheduler(sched *SchedulerWrapper, name string, spec ScheduleSpec, roleArn, payload string) error {
+	functionArn, _, err := w.functionArn()
+	if err != nil {
+		return err
+	}
+
+	if _, err := sched.UpsertWithTarget(name, spec, NewLambdaTarget(functionArn, roleArn, payload)); err != nil {
+		return fmt.Errorf("cannot upsert schedule %s: %w", name, err)
+	}
+
+	existing, err := sched.GetSchedule(name)
+	if err != nil {
+		return err
+	}
+
+	if existing.Target == nil || aws.ToString(existing.Target.Arn) != functionArn {
+		return fmt.Errorf("%w: schedule %s does not target %s", ErrScheduleNotWired, name, functionArn)
+	}
+
+	return nil
+}
+
+func (w *FunctionWrapper) verifyEventBridgeWiring(events *EventWrapper, ruleName, ruleArn, functionArn string, opts ...EventOptFunc) error {
+	targets, err := events.ListTargets(ruleName, opts...)
+	if err != nil {
+		return err
+	}
+
+	targeted := false
+
+	for _, t := range targets {
+		if aws.ToString(t.Arn) == functionArn {
+			targeted = true
+			break
+		}
+	}
+
+	if !targeted {
+		return fmt.Errorf("%w: rule %s does not target %s", ErrScheduleNotWired, ruleName, functionArn)
+	}
+
+	statements, err := w.GetPolicy()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statements {
+		if s.SourceArn() == ruleArn {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s has no invoke permission for rule %s", ErrScheduleNotWired, functionArn, ruleName)
+}
+
+// functionArn returns the arn and the plain name of function, funcName of wrapper may be either of them.
+func (w *FunctionWrapper) functionArn() (string, string, error) {
+	cfg, err := w.GetConfig()
+	if err != nil {
+		return "", "", fmt.Errorf("cannot get function %s: %w", w.funcName, err)
+	}
+
+	return aws.ToString(cfg.FunctionArn), aws.ToString(cfg.FunctionName), nil
+}
+
+// permissionStatementID is the statement id granting ruleName to invoke function.
+func permissionStatementID(ruleName string) string {
+	return "xaws-events-" + ruleName
+}
@@ -0,0 +1,144 @@
+package xaws
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type LambdaSuite struct {
+	suite.Suite
+}
+
+func TestLambda(t *testing.T) {
+	suite.Run(t, new(LambdaSuite))
+}
+
+func (s *LambdaSuite) Test_01_createCodeSource() {
+	w, err := NewFunctionWrapper("my-func", true, aws.Config{})
+	s.Require().Nil(err)
+
+	tests := []struct {
+		name string
+		opts CreateOptions
+		ok   bool
+	}{
+		{"zip", CreateOptions{ZipFile: []byte("zip")}, true},
+		{"s3", CreateOptions{S3Bucket: "code", S3Key: "f.zip"}, true},
+		{"image", CreateOptions{ImageURI: "123456789012.dkr.ecr.us-west-2.amazonaws.com/f:latest"}, true},
+		{"none", CreateOptions{}, false},
+		{"zip and image", CreateOptions{ZipFile: []byte("zip"), ImageURI: "f:latest"}, false},
+		{"s3 and image", CreateOptions{S3Bucket: "code", S3Key: "f.zip", ImageURI: "f:latest"}, false},
+		{"s3 without key", CreateOptions{S3Bucket: "code"}, false},
+	}
+
+	for _, tt := range tests {
+		state, err := w.CreateWithOptions("my-func", "role", tt.opts)
+		if tt.ok {
+			s.Nil(err, tt.name)
+			s.Equal(types.StateActive, state, tt.name)
+
+			continue
+		}
+
+		s.ErrorIs(err, ErrInvalidCodeSource, tt.name)
+	}
+}
+
+func (s *LambdaSuite) Test_02_dryRunSummary() {
+	input := &lambda.CreateFunctionInput{
+		FunctionName: aws.String("my-func"),
+		Handler:      aws.String("bootstrap"),
+		Runtime:      types.RuntimeProvidedal2,
+		MemorySize:   aws.Int32(512),
+		Code:         &types.FunctionCode{ZipFile: make([]byte, 2048)},
+		Environment:  &types.Environment{Variables: map[string]string{"STAGE": "prod"}},
+	}
+
+	summary := dryRunSummary(input)
+	for _, want := range []string{
+		"Code:{ZipFile:<2048 bytes>}", "FunctionName:my-func", "Environment:{Variables:map[STAGE:prod]}",
+		"Handler:bootstrap", "MemorySize:512", "Runtime:provided.al2",
+	} {
+		s.Contains(summary, want)
+	}
+
+	s.NotContains(summary, "Role", "zero fields are skipped")
+
+	s.Equal("{FunctionName:my-func ZipFile:<3 bytes>}", dryRunSummary(&lambda.UpdateFunctionCodeInput{
+		FunctionName: aws.String("my-func"),
+		ZipFile:      []byte("zip"),
+	}))
+	s.Equal(`{"k":1}`, dryRunSummary(`{"k":1}`))
+
+	var records []DryRunRecord
+
+	w, err := NewFunctionWrapper("my-func", true, aws.Config{})
+	s.Require().Nil(err)
+	w.SetDryRunHook(func(rec DryRunRecord) { records = append(records, rec) })
+
+	_, err = w.CreateWithOptions("my-func", "role", CreateOptions{ZipFile: []byte("zip")})
+	s.Nil(err)
+	s.Require().Len(records, 1)
+	s.IsType(&lambda.CreateFunctionInput{}, records[0].Input, "the hook gets the raw input")
+}
+
+// conflictServer rejects AddPermission with ResourceConflictException, and serves policy from GetPolicy.
+func (s *LambdaSuite) conflictServer(message, policy string) *FunctionWrapper {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			w.Header().Set("X-Amzn-Errortype", "ResourceConflictException")
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(map[string]string{"Type": "User", "message": message})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"Policy": policy, "RevisionId": "1"})
+	}))
+	s.T().Cleanup(srv.Close)
+
+	cfg := aws.Config{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("ak", "sk", ""),
+		BaseEndpoint: aws.String(srv.URL),
+	}
+
+	w, err := NewFunctionWrapper("my-func", false, cfg)
+	s.Require().Nil(err)
+
+	return w
+}
+
+func (s *LambdaSuite) Test_03_addPermissionConflict() {
+	const (
+		ruleArn = "arn:aws:events:us-east-1:123456789012:rule/nightly"
+		policy  = `{"Version":"2012-10-17","Statement":[{"Sid":"allow-nightly","Effect":"Allow",` +
+			`"Principal":{"Service":"events.amazonaws.com"},"Action":"lambda:InvokeFunction",` +
+			`"Resource":"arn:aws:lambda:us-east-1:123456789012:function:my-func",` +
+			`"Condition":{"ArnLike":{"AWS:SourceArn":"` + ruleArn + `"}}}]}`
+	)
+
+	var conflict *types.ResourceConflictException
+
+	// the same statement already exists.
+	w := s.conflictServer("The statement id (allow-nightly) provided already exists.", policy)
+	s.Nil(w.AddPermission("allow-nightly", PrincipalEventBridge, ruleArn))
+
+	// the statement id is taken by another principal or source arn.
+	s.ErrorAs(w.AddPermission("allow-nightly", PrincipalS3, ruleArn), &conflict)
+	s.ErrorAs(w.AddPermission("allow-nightly", PrincipalEventBridge, ruleArn+"-v2"), &conflict)
+
+	// the function is being updated, the statement was not added.
+	w = s.conflictServer("The operation cannot be performed at this time. An update is in progress.", `{"Statement":[]}`)
+	s.ErrorAs(w.AddPermission("allow-nightly", PrincipalEventBridge, ruleArn), &conflict)
+}
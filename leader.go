@@ -0,0 +1,121 @@
+package xaws
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LeaderElector elects one leader among workers with a lease, the lease is a Lock renewed by the leader,
+// when the leader stops or cannot renew in ttl, another worker takes over.
+//
+// Usage:
+//
+//	elector := NewLeaderElector(ddb, "id", "report-cron", 30*time.Second)
+//	elector.OnAcquire(func() { log.Println("leading") })
+//	go elector.Run(ctx)
+//
+//	for range ticker.C {
+//		if elector.IsLeader() {
+//			runReport()
+//		}
+//	}
+type LeaderElector struct {
+	lock   *Lock
+	leader atomic.Bool
+
+	mu        sync.Mutex
+	onAcquire func()
+	onLose    func()
+}
+
+// NewLeaderElector elects with lease item name in the table of w, whose partition key is keyName of type S,
+// ttl <= 0 uses 30s.
+func NewLeaderElector(w *DynamodbWrapper, keyName, name string, ttl time.Duration) *LeaderElector {
+	return newLeaderElector(NewDynamodbLockBackend(w, keyName), name, ttl)
+}
+
+func newLeaderElector(backend LockBackend, name string, ttl time.Duration) *LeaderElector {
+	return &LeaderElector{lock: NewLock(backend, name, ttl)}
+}
+
+// SetLogger sets the logger of elector, nil falls back to the default logger.
+func (e *LeaderElector) SetLogger(l Logger) {
+	e.lock.SetLogger(l)
+}
+
+// OnAcquire sets fn called when the worker becomes leader.
+func (e *LeaderElector) OnAcquire(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.onAcquire = fn
+}
+
+// OnLose sets fn called when the worker is no longer leader, because of a lost lease or Run returned.
+func (e *LeaderElector) OnLose(fn func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.onLose = fn
+}
+
+// IsLeader reports whether the worker holds the lease, it turns false before the lease expires
+// when the leader cannot renew it, so a cut off leader never overlaps with the next one.
+func (e *LeaderElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// ID returns the owner id of the worker in the lease.
+func (e *LeaderElector) ID() string {
+	return e.lock.Owner
+}
+
+// Run campaigns for the lease until ctx is done, the lease is released on return
+// so another worker takes over without waiting for it to expire.
+func (e *LeaderElector) Run(ctx context.Context) error {
+	for {
+		if err := e.lock.Lock(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			e.lock.log().Warn("cannot acquire lease", "lease", e.lock.Name, "error", err)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(_lockRetryInterval):
+				continue
+			}
+		}
+
+		e.setLeader(true)
+		e.lock.log().Info("became leader", "lease", e.lock.Name, "id", e.ID())
+
+		select {
+		case <-e.lock.Lost():
+			e.setLeader(false)
+			e.lock.log().Warn("lost leadership", "lease", e.lock.Name, "id", e.ID())
+		case <-ctx.Done():
+			e.setLeader(false)
+			return e.lock.Unlock()
+		}
+	}
+}
+
+func (e *LeaderElector) setLeader(leader bool) {
+	e.leader.Store(leader)
+
+	e.mu.Lock()
+	fn := e.onLose
+	if leader {
+		fn = e.onAcquire
+	}
+	e.mu.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+}
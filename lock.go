@@ -0,0 +1,204 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultLockTTL      = 30 * time.Second
+	_lockRetryInterval   = time.Second
+	_lockIDRandomLength  = 8
+	_lockHeartbeatPeriod = 3
+)
+
+var (
+	// ErrLockHeld is returned when the lock is held by another owner and not expired.
+	ErrLockHeld = errors.New("lock is held by another owner")
+	// ErrLockLost is returned when the lock expired and was taken over, or released by another owner.
+	ErrLockLost = errors.New("lock is lost")
+)
+
+// LockBackend stores locks, see NewS3LockBackend and NewDynamodbLockBackend.
+type LockBackend interface {
+	// Acquire creates lock name owned by owner for ttl, an expired lock is taken over,
+	// returns ErrLockHeld if it is held by another owner.
+	Acquire(name, owner string, ttl time.Duration) error
+	// Renew extends lock name owned by owner for ttl, returns ErrLockLost if owner does not hold it.
+	Renew(name, owner string, ttl time.Duration) error
+	// Release deletes lock name if owner holds it.
+	Release(name, owner string) error
+}
+
+// lockRecord is the stored lock, ExpiresAt is unix milliseconds.
+type lockRecord struct {
+	Owner     string `json:"owner" dynamodbav:"owner"`
+	ExpiresAt int64  `json:"expires_at" dynamodbav:"expires_at"`
+}
+
+func (r lockRecord) expired(now time.Time) bool {
+	return now.UnixMilli() >= r.ExpiresAt
+}
+
+// Lock is a distributed lock with ttl-based expiry, the holder renews it in the background
+// every ttl/3 until Unlock, so a crashed holder releases it after at most ttl.
+//
+// Usage:
+//
+//	lock := NewLock(NewS3LockBackend(s3Client, "locks/"), "daily-report", 30*time.Second)
+//	if err := lock.TryLock(); errors.Is(err, ErrLockHeld) {
+//		return nil // another worker runs it
+//	}
+//	defer lock.Unlock()
+//
+//	select {
+//	case <-lock.Lost():
+//		// stop the exclusive work
+//	case <-done:
+//	}
+type Lock struct {
+	backend LockBackend
+
+	Name  string
+	Owner string
+	ttl   time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+	lost chan struct{}
+	wg   sync.WaitGroup
+
+	logger Logger
+}
+
+// NewLock creates lock name on backend, ttl <= 0 uses 30s, the owner is the hostname with a random suffix.
+func NewLock(backend LockBackend, name string, ttl time.Duration) *Lock {
+	if ttl <= 0 {
+		ttl = _defaultLockTTL
+	}
+
+	host, _ := os.Hostname()
+
+	return &Lock{
+		backend: backend,
+		Name:    name,
+		Owner:   fmt.Sprintf("%s-%s", host, randSeq(_lockIDRandomLength)),
+		ttl:     ttl,
+	}
+}
+
+// SetLogger sets the logger of lock, nil falls back to the default logger.
+func (l *Lock) SetLogger(lg Logger) {
+	l.logger = lg
+}
+
+func (l *Lock) log() Logger {
+	return orDefaultLogger(l.logger)
+}
+
+// TryLock acquires the lock once, returns ErrLockHeld if another owner holds it.
+func (l *Lock) TryLock() error {
+	if err := l.backend.Acquire(l.Name, l.Owner, l.ttl); err != nil {
+		return err
+	}
+
+	l.startHeartbeat()
+
+	return nil
+}
+
+// Lock waits until the lock is acquired or ctx is done.
+func (l *Lock) Lock(ctx context.Context) error {
+	for {
+		err := l.TryLock()
+		if !errors.Is(err, ErrLockHeld) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(_lockRetryInterval):
+		}
+	}
+}
+
+// Lost is closed when the lock is taken over, or cannot be renewed before it expires,
+// the exclusive work should stop.
+func (l *Lock) Lost() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lost
+}
+
+// Unlock stops renewing and releases the lock.
+func (l *Lock) Unlock() error {
+	l.mu.Lock()
+	if l.stop != nil {
+		close(l.stop)
+		l.stop = nil
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+
+	return l.backend.Release(l.Name, l.Owner)
+}
+
+func (l *Lock) startHeartbeat() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stop != nil {
+		return
+	}
+
+	stop, lost := make(chan struct{}), make(chan struct{})
+	l.stop, l.lost = stop, lost
+
+	l.wg.Add(1)
+
+	go func() {
+		defer l.wg.Done()
+
+		period := l.ttl / _lockHeartbeatPeriod
+		lastRenew := time.Now()
+
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			err := l.backend.Renew(l.Name, l.Owner, l.ttl)
+			if err == nil {
+				lastRenew = time.Now()
+				continue
+			}
+
+			l.log().Warn("cannot renew lock", "lock", l.Name, "owner", l.Owner, "error", err)
+
+			// the lock may expire before the next heartbeat, another owner can take it over by then.
+			if errors.Is(err, ErrLockLost) || time.Since(lastRenew)+period >= l.ttl {
+				l.mu.Lock()
+				if l.stop == stop {
+					l.stop = nil
+				}
+				l.mu.Unlock()
+
+				close(lost)
+
+				return
+			}
+		}
+	}()
+}
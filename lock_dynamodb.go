@@ -0,0 +1,108 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamodbLockBackend stores each lock as an item keyed by the lock name, written with conditional puts.
+//
+// Items have "owner", "expires_at" (unix milliseconds) and "ttl" (unix seconds),
+// enable TTL on "ttl" to delete expired locks.
+type DynamodbLockBackend struct {
+	w       *DynamodbWrapper
+	keyName string
+}
+
+var _ LockBackend = (*DynamodbLockBackend)(nil)
+
+// NewDynamodbLockBackend stores locks in the table of w, whose partition key is keyName of type S.
+func NewDynamodbLockBackend(w *DynamodbWrapper, keyName string) *DynamodbLockBackend {
+	return &DynamodbLockBackend{w: w, keyName: keyName}
+}
+
+func (b *DynamodbLockBackend) key(name string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{b.keyName: &types.AttributeValueMemberS{Value: name}}
+}
+
+func (b *DynamodbLockBackend) Acquire(name, owner string, ttl time.Duration) error {
+	now := time.Now()
+
+	item, err := attributevalue.MarshalMap(lockRecord{Owner: owner, ExpiresAt: now.Add(ttl).UnixMilli()})
+	if err != nil {
+		return err
+	}
+
+	item[b.keyName] = &types.AttributeValueMemberS{Value: name}
+	item["ttl"] = &types.AttributeValueMemberN{Value: fmt.Sprint(now.Add(ttl).Unix())}
+
+	cond := expression.AttributeNotExists(expression.Name(b.keyName)).
+		Or(expression.Name("expires_at").LessThanEqual(expression.Value(now.UnixMilli()))).
+		Or(expression.Name("owner").Equal(expression.Value(owner)))
+
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.w.api().PutItem(b.w.DdbCtx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(b.w.TableName),
+		Item:                      item,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return fmt.Errorf("%w: %s", ErrLockHeld, name)
+	}
+
+	return err
+}
+
+func (b *DynamodbLockBackend) Renew(name, owner string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl)
+
+	_, err := b.w.UpdateItem(b.key(name), map[string]interface{}{
+		"expires_at": expiresAt.UnixMilli(),
+		"ttl":        expiresAt.Unix(),
+	}, WithCondition(expression.Name("owner").Equal(expression.Value(owner))))
+
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return fmt.Errorf("%w: %s", ErrLockLost, name)
+	}
+
+	return err
+}
+
+func (b *DynamodbLockBackend) Release(name, owner string) error {
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.Name("owner").Equal(expression.Value(owner))).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.w.api().DeleteItem(b.w.DdbCtx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(b.w.TableName),
+		Key:                       b.key(name),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+
+	var ccf *types.ConditionalCheckFailedException
+	if errors.As(err, &ccf) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,168 @@
+package xaws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// S3LockBackend stores each lock as a JSON object under prefix, written with S3 conditional writes:
+// If-None-Match to create, If-Match with the ETag to take over or renew.
+type S3LockBackend struct {
+	w      *S3Client
+	prefix string
+}
+
+var _ LockBackend = (*S3LockBackend)(nil)
+
+// NewS3LockBackend stores locks in the bucket of w under prefix, e.g. "locks/".
+func NewS3LockBackend(w *S3Client, prefix string) *S3LockBackend {
+	return &S3LockBackend{w: w, prefix: prefix}
+}
+
+func (b *S3LockBackend) Acquire(name, owner string, ttl time.Duration) error {
+	err := b.put(name, lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}, "If-None-Match", "*")
+	if !isPreconditionFailed(err) {
+		return err
+	}
+
+	rec, etag, err := b.get(name)
+	if errors.Is(err, ErrLockLost) {
+		// released in between, try once more.
+		return b.put(name, lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}, "If-None-Match", "*")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if rec.Owner != owner && !rec.expired(time.Now()) {
+		return fmt.Errorf("%w: %s by %s", ErrLockHeld, name, rec.Owner)
+	}
+
+	err = b.put(name, lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}, "If-Match", etag)
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: %s", ErrLockHeld, name)
+	}
+
+	return err
+}
+
+func (b *S3LockBackend) Renew(name, owner string, ttl time.Duration) error {
+	rec, etag, err := b.get(name)
+	if err != nil {
+		return err
+	}
+
+	if rec.Owner != owner {
+		return fmt.Errorf("%w: %s taken by %s", ErrLockLost, name, rec.Owner)
+	}
+
+	err = b.put(name, lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}, "If-Match", etag)
+	if isPreconditionFailed(err) {
+		return fmt.Errorf("%w: %s", ErrLockLost, name)
+	}
+
+	return err
+}
+
+func (b *S3LockBackend) Release(name, owner string) error {
+	rec, etag, err := b.get(name)
+	if errors.Is(err, ErrLockLost) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if rec.Owner != owner {
+		return nil
+	}
+
+	// the lock may expire and be taken over after get, only delete the version just read.
+	_, err = b.w.api().DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(b.w.Bucket),
+		Key:    aws.String(b.prefix + name),
+	}, func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue("If-Match", etag))
+	})
+	if isPreconditionFailed(err) {
+		return nil
+	}
+
+	return err
+}
+
+// put writes rec with the conditional header.
+func (b *S3LockBackend) put(name string, rec lockRecord, header, value string) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.w.api().PutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(b.w.Bucket),
+		Key:         aws.String(b.prefix + name),
+		Body:        bytes.NewReader(raw),
+		ContentType: aws.String("application/json"),
+	}, func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue(header, value))
+	})
+
+	return err
+}
+
+// get returns the lock and its ETag, ErrLockLost if it does not exist.
+func (b *S3LockBackend) get(name string) (lockRecord, string, error) {
+	var rec lockRecord
+
+	output, err := b.w.api().GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(b.w.Bucket),
+		Key:    aws.String(b.prefix + name),
+	})
+	if err != nil {
+		var notFound *types.NoSuchKey
+		if errors.As(err, &notFound) {
+			return rec, "", fmt.Errorf("%w: %s not exists", ErrLockLost, name)
+		}
+
+		return rec, "", err
+	}
+	defer output.Body.Close()
+
+	raw, err := io.ReadAll(output.Body)
+	if err != nil {
+		return rec, "", err
+	}
+
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return rec, "", fmt.Errorf("cannot parse lock %s: %w", name, err)
+	}
+
+	return rec, aws.ToString(output.ETag), nil
+}
+
+// isPreconditionFailed reports whether a conditional write was rejected,
+// 409 is returned when a concurrent conditional write is in progress.
+func isPreconditionFailed(err error) bool {
+	var respErr *awshttp.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+
+	code := respErr.HTTPStatusCode()
+
+	return code == http.StatusPreconditionFailed || code == http.StatusConflict
+}
@@ -0,0 +1,230 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// memLockBackend is a LockBackend in memory.
+type memLockBackend struct {
+	mu    sync.Mutex
+	locks map[string]lockRecord
+}
+
+func (b *memLockBackend) Acquire(name, owner string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if rec, ok := b.locks[name]; ok && rec.Owner != owner && !rec.expired(time.Now()) {
+		return ErrLockHeld
+	}
+
+	b.locks[name] = lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}
+
+	return nil
+}
+
+func (b *memLockBackend) Renew(name, owner string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if rec, ok := b.locks[name]; !ok || rec.Owner != owner {
+		return ErrLockLost
+	}
+
+	b.locks[name] = lockRecord{Owner: owner, ExpiresAt: time.Now().Add(ttl).UnixMilli()}
+
+	return nil
+}
+
+func (b *memLockBackend) Release(name, owner string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if rec, ok := b.locks[name]; ok && rec.Owner == owner {
+		delete(b.locks, name)
+	}
+
+	return nil
+}
+
+// flakyLockBackend is a memLockBackend whose Acquire and Renew fail with a non-lock error when down is set,
+// like a holder cut off from the backend.
+type flakyLockBackend struct {
+	*memLockBackend
+
+	down atomic.Bool
+}
+
+func (b *flakyLockBackend) Acquire(name, owner string, ttl time.Duration) error {
+	if b.down.Load() {
+		return errors.New("connection refused")
+	}
+
+	return b.memLockBackend.Acquire(name, owner, ttl)
+}
+
+func (b *flakyLockBackend) Renew(name, owner string, ttl time.Duration) error {
+	if b.down.Load() {
+		return errors.New("connection refused")
+	}
+
+	return b.memLockBackend.Renew(name, owner, ttl)
+}
+
+type LockSuite struct {
+	suite.Suite
+}
+
+func TestLock(t *testing.T) {
+	suite.Run(t, new(LockSuite))
+}
+
+func (s *LockSuite) Test_01_exclusive() {
+	backend := &memLockBackend{locks: map[string]lockRecord{}}
+
+	a := NewLock(backend, "job", 90*time.Millisecond)
+	b := NewLock(backend, "job", 90*time.Millisecond)
+
+	s.Nil(a.TryLock())
+	s.True(errors.Is(b.TryLock(), ErrLockHeld))
+
+	// heartbeat keeps the lock past its ttl.
+	time.Sleep(200 * time.Millisecond)
+	s.True(errors.Is(b.TryLock(), ErrLockHeld))
+
+	s.Nil(a.Unlock())
+	s.Nil(b.TryLock())
+	s.Nil(b.Unlock())
+}
+
+func (s *LockSuite) Test_02_lost() {
+	backend := &memLockBackend{locks: map[string]lockRecord{}}
+
+	a := NewLock(backend, "job", 60*time.Millisecond)
+	s.Nil(a.TryLock())
+
+	backend.mu.Lock()
+	backend.locks["job"] = lockRecord{Owner: "other", ExpiresAt: time.Now().Add(time.Hour).UnixMilli()}
+	backend.mu.Unlock()
+
+	select {
+	case <-a.Lost():
+	case <-time.After(time.Second):
+		s.Fail("lock not lost")
+	}
+
+	s.Nil(a.Unlock())
+}
+
+func (s *LockSuite) Test_03_leader() {
+	backend := &memLockBackend{locks: map[string]lockRecord{}}
+
+	a := newLeaderElector(backend, "cron", 90*time.Millisecond)
+	b := newLeaderElector(backend, "cron", 90*time.Millisecond)
+
+	var lost atomic.Int32
+	a.OnLose(func() { lost.Add(1) })
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+
+	defer cancelB()
+
+	doneA := make(chan error)
+	go func() { doneA <- a.Run(ctxA) }()
+
+	s.Eventually(a.IsLeader, time.Second, 10*time.Millisecond)
+
+	go b.Run(ctxB) //nolint:errcheck
+
+	time.Sleep(200 * time.Millisecond)
+	s.False(b.IsLeader())
+
+	cancelA()
+	s.Nil(<-doneA)
+	s.False(a.IsLeader())
+	s.Equal(int32(1), lost.Load())
+
+	s.Eventually(b.IsLeader, 3*time.Second, 10*time.Millisecond)
+}
+
+func (s *LockSuite) Test_04_relockAfterLost() {
+	backend := &memLockBackend{locks: map[string]lockRecord{}}
+
+	a := NewLock(backend, "job", 60*time.Millisecond)
+	s.Nil(a.TryLock())
+
+	backend.mu.Lock()
+	delete(backend.locks, "job")
+	backend.mu.Unlock()
+
+	<-a.Lost()
+
+	s.Nil(a.TryLock())
+
+	select {
+	case <-a.Lost():
+		s.Fail("relocked lock is lost")
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	s.Nil(a.Unlock())
+}
+
+func (s *LockSuite) Test_05_lostWhenRenewKeepsFailing() {
+	backend := &flakyLockBackend{memLockBackend: &memLockBackend{locks: map[string]lockRecord{}}}
+
+	a := NewLock(backend, "job", 90*time.Millisecond)
+	s.Nil(a.TryLock())
+
+	backend.down.Store(true)
+	start := time.Now()
+
+	select {
+	case <-a.Lost():
+		s.Less(time.Since(start), 90*time.Millisecond+30*time.Millisecond)
+	case <-time.After(time.Second):
+		s.Fail("lock not lost")
+	}
+
+	s.Nil(a.Unlock())
+}
+
+func (s *LockSuite) Test_06_leaderCutOff() {
+	backend := &memLockBackend{locks: map[string]lockRecord{}}
+	flaky := &flakyLockBackend{memLockBackend: backend}
+
+	a := newLeaderElector(flaky, "cron", 90*time.Millisecond)
+	b := newLeaderElector(backend, "cron", 90*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go a.Run(ctx) //nolint:errcheck
+
+	s.Eventually(a.IsLeader, time.Second, 10*time.Millisecond)
+
+	go b.Run(ctx) //nolint:errcheck
+
+	flaky.down.Store(true)
+	start := time.Now()
+
+	s.Eventually(func() bool { return !a.IsLeader() }, time.Second, 5*time.Millisecond)
+	s.Less(time.Since(start), 90*time.Millisecond)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !b.IsLeader() && time.Now().Before(deadline) {
+		s.False(a.IsLeader() && b.IsLeader(), "two leaders")
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	s.True(b.IsLeader())
+	s.False(a.IsLeader())
+}
@@ -0,0 +1,103 @@
+package xaws
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Logger is the logger used by wrappers, keyvals are alternating keys and values,
+// e.g. logger.Warn("cannot delete temporary object", "key", key, "error", err).
+type Logger interface {
+	Debug(msg string, keyvals ...any)
+	Info(msg string, keyvals ...any)
+	Warn(msg string, keyvals ...any)
+	Error(msg string, keyvals ...any)
+}
+
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(loggerHolder{NewZerologLogger(nil)})
+}
+
+// loggerHolder keeps the concrete type stored in defaultLogger constant.
+type loggerHolder struct {
+	Logger
+}
+
+// SetDefaultLogger sets the logger of wrappers without their own logger, nil silences them.
+func SetDefaultLogger(l Logger) {
+	if l == nil {
+		l = NopLogger{}
+	}
+
+	defaultLogger.Store(loggerHolder{l})
+}
+
+// DefaultLogger returns the logger of wrappers without their own logger.
+func DefaultLogger() Logger {
+	return defaultLogger.Load().(loggerHolder).Logger
+}
+
+// orDefaultLogger returns l, or the default logger if l is nil.
+func orDefaultLogger(l Logger) Logger {
+	if l == nil {
+		return DefaultLogger()
+	}
+
+	return l
+}
+
+// NopLogger discards all logs, it is for consumers who do their own logging.
+type NopLogger struct{}
+
+func (NopLogger) Debug(string, ...any) {}
+func (NopLogger) Info(string, ...any)  {}
+func (NopLogger) Warn(string, ...any)  {}
+func (NopLogger) Error(string, ...any) {}
+
+// ZerologLogger is the default logger, it writes to the global zerolog logger if created with nil.
+type ZerologLogger struct {
+	logger *zerolog.Logger
+}
+
+func NewZerologLogger(logger *zerolog.Logger) *ZerologLogger {
+	return &ZerologLogger{logger: logger}
+}
+
+func (l *ZerologLogger) Debug(msg string, keyvals ...any) { l.write(l.zl().Debug(), msg, keyvals) }
+func (l *ZerologLogger) Info(msg string, keyvals ...any)  { l.write(l.zl().Info(), msg, keyvals) }
+func (l *ZerologLogger) Warn(msg string, keyvals ...any)  { l.write(l.zl().Warn(), msg, keyvals) }
+func (l *ZerologLogger) Error(msg string, keyvals ...any) { l.write(l.zl().Error(), msg, keyvals) }
+
+func (l *ZerologLogger) zl() *zerolog.Logger {
+	if l.logger == nil {
+		return &log.Logger
+	}
+
+	return l.logger
+}
+
+func (l *ZerologLogger) write(e *zerolog.Event, msg string, keyvals []any) {
+	if e == nil {
+		return
+	}
+
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key, ok := keyvals[i].(string)
+		if !ok {
+			continue
+		}
+
+		if err, ok := keyvals[i+1].(error); ok {
+			e = e.AnErr(key, err)
+			continue
+		}
+
+		e = e.Interface(key, keyvals[i+1])
+	}
+
+	e.Msg(msg)
+}
@@ -0,0 +1,292 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+const (
+	// _putLogEventsMaxCount and _putLogEventsMaxBytes are the limits of a PutLogEvents call,
+	// each event counts its message plus 26 bytes.
+	_putLogEventsMaxCount = 10000
+	_putLogEventsMaxBytes = 1024 * 1024
+	_logEventOverhead     = 26
+
+	_logQueryPollInterval = time.Second
+	_logQueryTimeout      = 5 * time.Minute
+)
+
+var ErrLogQueryFailed = errors.New("logs insights query failed")
+
+// LogsWrapper writes to and queries CloudWatch Logs.
+type LogsWrapper struct {
+	client *cloudwatchlogs.Client
+
+	// tokens are the next sequence tokens, by group and stream.
+	mu     sync.Mutex
+	tokens map[string]*string
+
+	logger Logger
+}
+
+func NewLogsWrapper(cfg aws.Config, opts ...ClientOptFunc) *LogsWrapper {
+	opt := newClientOpts(opts...)
+
+	w := newLogsWrapper(cloudwatchlogs.NewFromConfig(opt.apply(cfg)))
+	w.logger = opt.logger
+
+	return w
+}
+
+func NewLogsWrapperWithDefaultConfig(opts ...ClientOptFunc) (*LogsWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogsWrapper(cfg, opts...), nil
+}
+
+func newLogsWrapper(client *cloudwatchlogs.Client) *LogsWrapper {
+	return &LogsWrapper{
+		client: client,
+		tokens: make(map[string]*string),
+	}
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *LogsWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *LogsWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// CreateLogGroup creates log group, an existing group is not an error.
+func (w *LogsWrapper) CreateLogGroup(group string) error {
+	_, err := w.client.CreateLogGroup(context.TODO(), &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(group),
+	})
+
+	var exists *types.ResourceAlreadyExistsException
+	if err != nil && !errors.As(err, &exists) {
+		return fmt.Errorf("cannot create log group %s: %w", group, err)
+	}
+
+	return nil
+}
+
+// CreateLogStream creates stream in log group, an existing stream is not an error.
+func (w *LogsWrapper) CreateLogStream(group, stream string) error {
+	_, err := w.client.CreateLogStream(context.TODO(), &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+	})
+
+	var exists *types.ResourceAlreadyExistsException
+	if err != nil && !errors.As(err, &exists) {
+		return fmt.Errorf("cannot create log stream %s/%s: %w", group, stream, err)
+	}
+
+	return nil
+}
+
+// PutLogEvents puts lines to stream of log group, lines are sorted by time and sent in batches,
+// a zero Timestamp means now.
+//
+// The sequence token of the stream is tracked, and refreshed from the error if it is rejected.
+//
+// Usage:
+//
+//	_ = w.CreateLogGroup(group)
+//	_ = w.CreateLogStream(group, stream)
+//	err := w.PutLogEvents(group, stream, LogLine{Message: "started"})
+func (w *LogsWrapper) PutLogEvents(group, stream string, lines ...LogLine) error {
+	now := time.Now()
+
+	events := make([]types.InputLogEvent, 0, len(lines))
+	for _, line := range lines {
+		ts := line.Timestamp
+		if ts.IsZero() {
+			ts = now
+		}
+
+		events = append(events, types.InputLogEvent{
+			Message:   aws.String(line.Message),
+			Timestamp: aws.Int64(ts.UnixMilli()),
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToInt64(events[i].Timestamp) < aws.ToInt64(events[j].Timestamp)
+	})
+
+	for start := 0; start < len(events); {
+		end, size := start, 0
+
+		for end < len(events) && end-start < _putLogEventsMaxCount {
+			n := len(aws.ToString(events[end].Message)) + _logEventOverhead
+			if end > start && size+n > _putLogEventsMaxBytes {
+				break
+			}
+
+			size += n
+			end++
+		}
+
+		if err := w.putLogEvents(group, stream, events[start:end]); err != nil {
+			return err
+		}
+
+		start = end
+	}
+
+	return nil
+}
+
+func (w *LogsWrapper) putLogEvents(group, stream string, events []types.InputLogEvent) error {
+	key := group + ":" + stream
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for retried := false; ; retried = true {
+		output, err := w.client.PutLogEvents(context.TODO(), &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(group),
+			LogStreamName: aws.String(stream),
+			LogEvents:     events,
+			SequenceToken: w.tokens[key],
+		})
+
+		var (
+			invalid  *types.InvalidSequenceTokenException
+			accepted *types.DataAlreadyAcceptedException
+		)
+
+		switch {
+		case err == nil:
+			w.tokens[key] = output.NextSequenceToken
+
+			if info := output.RejectedLogEventsInfo; info != nil {
+				w.log().Warn("log events rejected", "group", group, "stream", stream,
+					"too_old_end", aws.ToInt32(info.TooOldLogEventEndIndex),
+					"too_new_start", aws.ToInt32(info.TooNewLogEventStartIndex),
+					"expired_end", aws.ToInt32(info.ExpiredLogEventEndIndex))
+			}
+
+			return nil
+		case errors.As(err, &accepted):
+			w.tokens[key] = accepted.ExpectedSequenceToken
+			return nil
+		case errors.As(err, &invalid) && !retried:
+			w.tokens[key] = invalid.ExpectedSequenceToken
+		default:
+			return fmt.Errorf("cannot put log events to %s/%s: %w", group, stream, err)
+		}
+	}
+}
+
+// LogQueryRow is a result row of a Logs Insights query, by field name, e.g. "@timestamp" and "@message".
+type LogQueryRow map[string]string
+
+// Timestamp parses field "@timestamp", zero if it is missing.
+func (r LogQueryRow) Timestamp() time.Time {
+	t, _ := time.Parse("2006-01-02 15:04:05.000", r["@timestamp"])
+	return t
+}
+
+// Message returns field "@message".
+func (r LogQueryRow) Message() string {
+	return r["@message"]
+}
+
+// Float parses field as a number, e.g. the result of stats count(*).
+func (r LogQueryRow) Float(field string) (float64, error) {
+	return strconv.ParseFloat(r[field], 64)
+}
+
+// StartQuery starts a Logs Insights query on groups over [start, end] and returns the query id.
+func (w *LogsWrapper) StartQuery(groups []string, query string, start, end time.Time) (string, error) {
+	output, err := w.client.StartQuery(context.TODO(), &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: groups,
+		QueryString:   aws.String(query),
+		StartTime:     aws.Int64(start.Unix()),
+		EndTime:       aws.Int64(end.Unix()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot start query: %w", err)
+	}
+
+	return aws.ToString(output.QueryId), nil
+}
+
+// GetQueryResults returns the rows of query queryID so far and its status.
+func (w *LogsWrapper) GetQueryResults(queryID string) ([]LogQueryRow, types.QueryStatus, error) {
+	output, err := w.client.GetQueryResults(context.TODO(), &cloudwatchlogs.GetQueryResultsInput{
+		QueryId: aws.String(queryID),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot get query results: %w", err)
+	}
+
+	rows := make([]LogQueryRow, 0, len(output.Results))
+
+	for _, fields := range output.Results {
+		row := make(LogQueryRow, len(fields))
+		for _, f := range fields {
+			row[aws.ToString(f.Field)] = aws.ToString(f.Value)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, output.Status, nil
+}
+
+// Query runs a Logs Insights query on groups over [start, end] and polls until it completes,
+// the query is stopped if it does not complete in 5 minutes.
+//
+// Usage:
+//
+//	rows, err := w.Query([]string{"/aws/lambda/my-func"},
+//		`fields @timestamp, @message | filter @message like /ERROR/ | limit 50`,
+//		time.Now().Add(-time.Hour), time.Now())
+func (w *LogsWrapper) Query(groups []string, query string, start, end time.Time) ([]LogQueryRow, error) {
+	queryID, err := w.StartQuery(groups, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(_logQueryTimeout)
+
+	for {
+		rows, status, err := w.GetQueryResults(queryID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status {
+		case types.QueryStatusComplete:
+			return rows, nil
+		case types.QueryStatusFailed, types.QueryStatusCancelled, types.QueryStatusTimeout:
+			return nil, fmt.Errorf("%w: %s %s", ErrLogQueryFailed, queryID, status)
+		}
+
+		if time.Now().After(deadline) {
+			_, _ = w.client.StopQuery(context.TODO(), &cloudwatchlogs.StopQueryInput{QueryId: aws.String(queryID)})
+			return nil, fmt.Errorf("%w: %s not complete in %s", ErrLogQueryFailed, queryID, _logQueryTimeout)
+		}
+
+		time.Sleep(_logQueryPollInterval)
+	}
+}
@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/coghost/xaws"
+	"github.com/stretchr/testify/mock"
+)
+
+// DynamodbAPI mocks xaws.DynamodbAPI, use mock.Run to fill out of GetItem/Query/Scan:
+//
+//	m.On("GetItem", key, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+//		*args.Get(1).(*Item) = Item{ID: "1"}
+//	}).Return(nil)
+type DynamodbAPI struct {
+	mock.Mock
+}
+
+var _ xaws.DynamodbAPI = (*DynamodbAPI)(nil)
+
+func (m *DynamodbAPI) PutItem(data interface{}) error {
+	return m.Called(data).Error(0)
+}
+
+func (m *DynamodbAPI) GetItem(key map[string]types.AttributeValue, out interface{}, opts ...xaws.DdbOptFunc) error {
+	return m.Called(key, out, opts).Error(0)
+}
+
+func (m *DynamodbAPI) UpdateItem(
+	key map[string]types.AttributeValue, updates map[string]interface{}, opts ...xaws.DdbOptFunc,
+) (map[string]types.AttributeValue, error) {
+	ret := m.Called(key, updates, opts)
+
+	attrs, _ := ret.Get(0).(map[string]types.AttributeValue)
+
+	return attrs, ret.Error(1)
+}
+
+func (m *DynamodbAPI) DeleteRow(key map[string]types.AttributeValue) error {
+	return m.Called(key).Error(0)
+}
+
+func (m *DynamodbAPI) Query(expr expression.Expression, out interface{}, opts ...xaws.DdbOptFunc) error {
+	return m.Called(expr, out, opts).Error(0)
+}
+
+func (m *DynamodbAPI) Scan(expr expression.Expression, out interface{}, opts ...xaws.DdbOptFunc) error {
+	return m.Called(expr, out, opts).Error(0)
+}
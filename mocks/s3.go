@@ -0,0 +1,51 @@
+// Package mocks provides testify mocks of the xaws wrapper interfaces.
+//
+// Variadic options are passed to Called as a single slice argument, match them with mock.Anything:
+//
+//	m := new(mocks.S3API)
+//	m.On("GetObject", "a.json", mock.Anything).Return([]byte(`{}`), nil)
+package mocks
+
+import (
+	"github.com/coghost/xaws"
+	"github.com/stretchr/testify/mock"
+)
+
+type S3API struct {
+	mock.Mock
+}
+
+var _ xaws.S3API = (*S3API)(nil)
+
+func (m *S3API) PutObject(objectKey string, raw []byte, opts ...xaws.S3OptionFunc) error {
+	return m.Called(objectKey, raw, opts).Error(0)
+}
+
+func (m *S3API) GetObject(objectKey string, opts ...xaws.S3OptionFunc) ([]byte, error) {
+	ret := m.Called(objectKey, opts)
+
+	content, _ := ret.Get(0).([]byte)
+
+	return content, ret.Error(1)
+}
+
+func (m *S3API) HasObject(objectKey string, opts ...xaws.S3OptionFunc) (bool, error) {
+	ret := m.Called(objectKey, opts)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *S3API) DeleteObject(objectKey string, opts ...xaws.S3OptionFunc) error {
+	return m.Called(objectKey, opts).Error(0)
+}
+
+func (m *S3API) ListObjects(prefix string, opts ...xaws.S3OptionFunc) ([]string, error) {
+	ret := m.Called(prefix, opts)
+
+	keys, _ := ret.Get(0).([]string)
+
+	return keys, ret.Error(1)
+}
+
+func (m *S3API) CopyObject(srcKey, dstKey string, opts ...xaws.S3OptionFunc) error {
+	return m.Called(srcKey, dstKey, opts).Error(0)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/coghost/xaws"
+	"github.com/stretchr/testify/mock"
+)
+
+type SqsAPI struct {
+	mock.Mock
+}
+
+var _ xaws.SqsAPI = (*SqsAPI)(nil)
+
+func (m *SqsAPI) SendMsg(message string) (*sqs.SendMessageOutput, error) {
+	ret := m.Called(message)
+
+	output, _ := ret.Get(0).(*sqs.SendMessageOutput)
+
+	return output, ret.Error(1)
+}
+
+func (m *SqsAPI) SendManyMessages(messages []string) (int, error) {
+	ret := m.Called(messages)
+	return ret.Int(0), ret.Error(1)
+}
+
+func (m *SqsAPI) GetMsgs(opts ...xaws.SqsOptFunc) (*sqs.ReceiveMessageOutput, error) {
+	ret := m.Called(opts)
+
+	output, _ := ret.Get(0).(*sqs.ReceiveMessageOutput)
+
+	return output, ret.Error(1)
+}
+
+func (m *SqsAPI) DeleteMsg(handle *string) (*sqs.DeleteMessageOutput, error) {
+	ret := m.Called(handle)
+
+	output, _ := ret.Get(0).(*sqs.DeleteMessageOutput)
+
+	return output, ret.Error(1)
+}
+
+func (m *SqsAPI) GetRemainedItems(opts ...xaws.SqsOptFunc) (int64, error) {
+	ret := m.Called(opts)
+
+	remained, _ := ret.Get(0).(int64)
+
+	return remained, ret.Error(1)
+}
@@ -0,0 +1,230 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+)
+
+const (
+	_domainPollInterval = 30 * time.Second
+)
+
+var ErrDomainDeleted = errors.New("domain is deleted")
+
+type OpensearchWrapper struct {
+	client *opensearch.Client
+	cfg    aws.Config
+
+	logger Logger
+}
+
+// NewOpensearchWrapper creates wrapper with the default config.
+func NewOpensearchWrapper(opts ...ClientOptFunc) (*OpensearchWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOpensearchWrapperWithConfig(cfg, opts...), nil
+}
+
+// NewOpensearchWrapperWithConfig creates wrapper with cfg, e.g. an assumed-role config.
+func NewOpensearchWrapperWithConfig(cfg aws.Config, opts ...ClientOptFunc) *OpensearchWrapper {
+	opt := newClientOpts(opts...)
+	cfg = opt.apply(cfg)
+
+	return &OpensearchWrapper{
+		client: opensearch.NewFromConfig(cfg),
+		cfg:    cfg,
+		logger: opt.logger,
+	}
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *OpensearchWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *OpensearchWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// DomainSpec is the capacity of a domain, zero fields are left to the service default on create,
+// and unchanged on update.
+type DomainSpec struct {
+	// EngineVersion is e.g. "OpenSearch_2.11", only used on create.
+	EngineVersion string
+
+	InstanceType  types.OpenSearchPartitionInstanceType
+	InstanceCount int32
+
+	// VolumeSize is the EBS volume size of each data node in GiB, 0 disables EBS on create.
+	VolumeSize int32
+	VolumeType types.VolumeType
+}
+
+func (s DomainSpec) clusterConfig() *types.ClusterConfig {
+	if s.InstanceType == "" && s.InstanceCount == 0 {
+		return nil
+	}
+
+	cc := &types.ClusterConfig{InstanceType: s.InstanceType}
+	if s.InstanceCount > 0 {
+		cc.InstanceCount = aws.Int32(s.InstanceCount)
+	}
+
+	return cc
+}
+
+func (s DomainSpec) ebsOptions() *types.EBSOptions {
+	if s.VolumeSize == 0 && s.VolumeType == "" {
+		return nil
+	}
+
+	ebs := &types.EBSOptions{EBSEnabled: aws.Bool(true), VolumeType: s.VolumeType}
+	if s.VolumeSize > 0 {
+		ebs.VolumeSize = aws.Int32(s.VolumeSize)
+	}
+
+	return ebs
+}
+
+// CreateDomain creates domain name with spec, the domain takes minutes to be active, see WaitForDomainActive.
+func (w *OpensearchWrapper) CreateDomain(name string, spec DomainSpec) (*types.DomainStatus, error) {
+	input := &opensearch.CreateDomainInput{
+		DomainName:    aws.String(name),
+		ClusterConfig: spec.clusterConfig(),
+		EBSOptions:    spec.ebsOptions(),
+	}
+
+	if spec.EngineVersion != "" {
+		input.EngineVersion = aws.String(spec.EngineVersion)
+	}
+
+	output, err := w.client.CreateDomain(context.TODO(), input)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create domain %s: %w", name, err)
+	}
+
+	return output.DomainStatus, nil
+}
+
+// DeleteDomain deletes domain name and all its data.
+func (w *OpensearchWrapper) DeleteDomain(name string) error {
+	_, err := w.client.DeleteDomain(context.TODO(), &opensearch.DeleteDomainInput{
+		DomainName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete domain %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// DescribeDomain returns the status of domain name, e.g. its endpoint and whether it is processing.
+func (w *OpensearchWrapper) DescribeDomain(name string) (*types.DomainStatus, error) {
+	output, err := w.client.DescribeDomain(context.TODO(), &opensearch.DescribeDomainInput{
+		DomainName: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe domain %s: %w", name, err)
+	}
+
+	return output.DomainStatus, nil
+}
+
+// DescribeDomainNodes returns the nodes of domain name.
+func (w *OpensearchWrapper) DescribeDomainNodes(name string) ([]types.DomainNodesStatus, error) {
+	output, err := w.client.DescribeDomainNodes(context.TODO(), &opensearch.DescribeDomainNodesInput{
+		DomainName: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe nodes of domain %s: %w", name, err)
+	}
+
+	return output.DomainNodesStatusList, nil
+}
+
+// UpdateDomainConfig changes instance type / count and EBS of domain name to the non-zero fields of spec,
+// it starts a blue/green deployment, see WaitForDomainActive.
+func (w *OpensearchWrapper) UpdateDomainConfig(name string, spec DomainSpec) (*types.DomainConfig, error) {
+	output, err := w.client.UpdateDomainConfig(context.TODO(), &opensearch.UpdateDomainConfigInput{
+		DomainName:    aws.String(name),
+		ClusterConfig: spec.clusterConfig(),
+		EBSOptions:    spec.ebsOptions(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot update domain %s: %w", name, err)
+	}
+
+	return output.DomainConfig, nil
+}
+
+// ListDomainNames returns names of all domains.
+func (w *OpensearchWrapper) ListDomainNames() ([]string, error) {
+	output, err := w.client.ListDomainNames(context.TODO(), &opensearch.ListDomainNamesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list domains: %w", err)
+	}
+
+	names := make([]string, 0, len(output.DomainNames))
+	for _, d := range output.DomainNames {
+		names = append(names, aws.ToString(d.DomainName))
+	}
+
+	return names, nil
+}
+
+// WaitForDomainActive polls domain name every 30s until it has an endpoint and no change is processing,
+// returns ErrDomainDeleted if it is being deleted.
+func (w *OpensearchWrapper) WaitForDomainActive(name string, timeout time.Duration) (*types.DomainStatus, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := w.DescribeDomain(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if aws.ToBool(status.Deleted) {
+			return status, fmt.Errorf("%w: %s", ErrDomainDeleted, name)
+		}
+
+		hasEndpoint := status.Endpoint != nil || len(status.Endpoints) > 0
+		if hasEndpoint && !aws.ToBool(status.Processing) && !aws.ToBool(status.UpgradeProcessing) {
+			return status, nil
+		}
+
+		if time.Now().After(deadline) {
+			return status, fmt.Errorf("domain %s not active in %s", name, timeout)
+		}
+
+		w.log().Debug("waiting for domain", "domain", name, "processing", aws.ToBool(status.Processing))
+		time.Sleep(_domainPollInterval)
+	}
+}
+
+// NewClient returns a data-plane client of domain name, signed with the config of wrapper.
+func (w *OpensearchWrapper) NewClient(name string) (*OpensearchClient, error) {
+	status, err := w.DescribeDomain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := aws.ToString(status.Endpoint)
+	if endpoint == "" {
+		endpoint = status.Endpoints["vpc"]
+	}
+
+	if endpoint == "" {
+		return nil, fmt.Errorf("domain %s has no endpoint yet", name)
+	}
+
+	return NewOpensearchClient(w.cfg, endpoint), nil
+}
@@ -0,0 +1,304 @@
+package xaws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// _opensearchService is the signing name of managed domains, serverless collections use "aoss".
+	_opensearchService = "es"
+
+	_bulkMaxCount = 500
+	_bulkMaxBytes = 5 * 1024 * 1024
+
+	_opensearchTimeout = time.Minute
+)
+
+var ErrBulkIndexFailed = errors.New("failed to index documents")
+
+// OpensearchError is a non-2xx response of OpenSearch.
+type OpensearchError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *OpensearchError) Error() string {
+	return fmt.Sprintf("opensearch responded %d: %s", e.StatusCode, e.Body)
+}
+
+// sigV4Transport signs each request with SigV4 before sending it with base.
+type sigV4Transport struct {
+	base    http.RoundTripper
+	signer  *v4.Signer
+	creds   aws.CredentialsProvider
+	region  string
+	service string
+}
+
+// NewSigV4Transport returns a transport signing requests to service with the credentials and region of cfg,
+// nil base uses http.DefaultTransport.
+func NewSigV4Transport(cfg aws.Config, service string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &sigV4Transport{
+		base:    base,
+		signer:  v4.NewSigner(),
+		creds:   cfg.Credentials,
+		region:  cfg.Region,
+		service: service,
+	}
+}
+
+func (t *sigV4Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		raw, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Body.Close()
+
+		body = raw
+	}
+
+	// RoundTrip must not modify the request.
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	creds, err := t.creds.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve credentials: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	if err := t.signer.SignHTTP(req.Context(), creds, req, hex.EncodeToString(sum[:]), t.service, t.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("cannot sign request: %w", err)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// OpensearchClient indexes and searches documents of a domain over signed HTTP.
+//
+// Usage:
+//
+//	client, err := wrapper.NewClient("scraped")
+//	err = client.IndexDocument("pages", page.URL, page)
+//	raw, err := client.Search("pages", json.RawMessage(`{"query":{"match":{"title":"go"}}}`))
+type OpensearchClient struct {
+	endpoint string
+	http     *http.Client
+}
+
+// NewOpensearchClient creates client of endpoint, e.g. the Endpoint of DescribeDomain,
+// requests are signed with cfg.
+func NewOpensearchClient(cfg aws.Config, endpoint string) *OpensearchClient {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+
+	return &OpensearchClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		http: &http.Client{
+			Transport: NewSigV4Transport(cfg, _opensearchService, nil),
+			Timeout:   _opensearchTimeout,
+		},
+	}
+}
+
+// IndexDocument indexes doc to index with id, empty id lets OpenSearch generate one,
+// doc is marshaled to JSON, json.RawMessage is sent as is.
+func (c *OpensearchClient) IndexDocument(index, id string, doc any) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("cannot marshal document: %w", err)
+	}
+
+	method, path := http.MethodPost, "/"+url.PathEscape(index)+"/_doc"
+	if id != "" {
+		method, path = http.MethodPut, path+"/"+url.PathEscape(id)
+	}
+
+	_, err = c.do(method, path, "application/json", raw)
+
+	return err
+}
+
+// OpensearchDoc is a document of BulkIndex, empty ID lets OpenSearch generate one.
+type OpensearchDoc struct {
+	ID     string
+	Source any
+}
+
+// BulkIndex indexes docs to index in chunks of up to 500 documents and 5MB, returns the number of documents indexed,
+// documents rejected by OpenSearch are reported in the returned error.
+func (c *OpensearchClient) BulkIndex(index string, docs []OpensearchDoc) (int, error) {
+	indexed := 0
+
+	var (
+		errs  []error
+		buf   bytes.Buffer
+		count int
+	)
+
+	flush := func() error {
+		if count == 0 {
+			return nil
+		}
+
+		n, err := c.bulk(buf.Bytes())
+		indexed += n
+
+		buf.Reset()
+		count = 0
+
+		return err
+	}
+
+	for i, doc := range docs {
+		action := map[string]map[string]string{"index": {"_index": index}}
+		if doc.ID != "" {
+			action["index"]["_id"] = doc.ID
+		}
+
+		meta, err := json.Marshal(action)
+		if err != nil {
+			return indexed, err
+		}
+
+		source, err := json.Marshal(doc.Source)
+		if err != nil {
+			return indexed, fmt.Errorf("cannot marshal document %d: %w", i, err)
+		}
+
+		size := len(meta) + len(source) + 2
+		if count > 0 && (count >= _bulkMaxCount || buf.Len()+size > _bulkMaxBytes) {
+			if err := flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		buf.Write(meta)
+		buf.WriteByte('\n')
+		buf.Write(source)
+		buf.WriteByte('\n')
+		count++
+	}
+
+	if err := flush(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return indexed, errors.Join(errs...)
+}
+
+// bulk sends a _bulk body and returns the number of succeeded items.
+func (c *OpensearchClient) bulk(body []byte) (int, error) {
+	raw, err := c.do(http.MethodPost, "/_bulk", "application/x-ndjson", body)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID     string          `json:"_id"`
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return 0, fmt.Errorf("cannot parse bulk response: %w", err)
+	}
+
+	ok := 0
+
+	var errs []error
+
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status < http.StatusMultipleChoices {
+				ok++
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("%w: %s %d %s", ErrBulkIndexFailed, result.ID, result.Status, result.Error))
+		}
+	}
+
+	return ok, errors.Join(errs...)
+}
+
+// Search runs query DSL on index, and returns the raw response, empty index searches all indices.
+func (c *OpensearchClient) Search(index string, query json.RawMessage) (json.RawMessage, error) {
+	path := "/_search"
+	if index != "" {
+		path = "/" + url.PathEscape(index) + path
+	}
+
+	return c.do(http.MethodPost, path, "application/json", query)
+}
+
+// DeleteByQuery deletes documents of index matching query DSL, returns the number of deleted documents.
+func (c *OpensearchClient) DeleteByQuery(index string, query json.RawMessage) (int64, error) {
+	raw, err := c.do(http.MethodPost, "/"+url.PathEscape(index)+"/_delete_by_query", "application/json", query)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		Deleted int64 `json:"deleted"`
+	}
+
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return 0, fmt.Errorf("cannot parse delete_by_query response: %w", err)
+	}
+
+	return resp.Deleted, nil
+}
+
+func (c *OpensearchClient) do(method, path, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(context.TODO(), method, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, &OpensearchError{StatusCode: resp.StatusCode, Body: string(raw)}
+	}
+
+	return raw, nil
+}
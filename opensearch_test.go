@@ -0,0 +1,78 @@
+package xaws
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/suite"
+)
+
+type OpensearchSuite struct {
+	suite.Suite
+}
+
+func TestOpensearch(t *testing.T) {
+	suite.Run(t, new(OpensearchSuite))
+}
+
+func (s *OpensearchSuite) Test_01_bulkIndex() {
+	var requests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		s.True(strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256"))
+		s.Equal("/_bulk", r.URL.Path)
+
+		var items []string
+
+		scanner := bufio.NewScanner(r.Body)
+		for i := 0; scanner.Scan(); i++ {
+			if i%2 == 0 {
+				items = append(items, `{"index":{"_id":"x","status":201}}`)
+			}
+		}
+
+		fmt.Fprintf(w, `{"errors":false,"items":[%s]}`, strings.Join(items, ","))
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("ak", "sk", ""),
+	}
+	client := NewOpensearchClient(cfg, srv.URL)
+
+	docs := make([]OpensearchDoc, _bulkMaxCount+1)
+	for i := range docs {
+		docs[i] = OpensearchDoc{ID: fmt.Sprint(i), Source: map[string]int{"n": i}}
+	}
+
+	n, err := client.BulkIndex("pages", docs)
+	s.Nil(err)
+	s.Equal(len(docs), n)
+	s.Equal(2, requests)
+}
+
+func (s *OpensearchSuite) Test_02_error() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"index_not_found_exception"}`))
+	}))
+	defer srv.Close()
+
+	cfg := aws.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("ak", "sk", "")}
+
+	_, err := NewOpensearchClient(cfg, srv.URL).Search("missing", json.RawMessage(`{}`))
+
+	var osErr *OpensearchError
+	s.ErrorAs(err, &osErr)
+	s.Equal(http.StatusNotFound, osErr.StatusCode)
+}
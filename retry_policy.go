@@ -0,0 +1,151 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// RetryPolicy configures the sdk retryer of the clients a wrapper creates.
+//
+// The *WithDefaultConfig constructors use DefaultRetryPolicy, constructors taking an aws.Config keep
+// the retryer of the config, except Lambda and Scheduler which use NoRetryPolicy.
+// Set the policy of a wrapper with WithRetryPolicy:
+//
+//	w, err := NewFunctionWrapper("my-func", false, cfg, WithRetryPolicy(DefaultRetryPolicy))
+//	sqs := NewSqsClient(queue, cfg, 10, 60, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, Jitter: true}))
+type RetryPolicy struct {
+	// MaxAttempts is the max attempts including the first call, 1 disables retry, 0 uses the sdk default (3).
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it doubles on every retry up to MaxBackoff.
+	BaseDelay time.Duration
+	// MaxBackoff caps the delay between two attempts, 0 uses the sdk default (20s).
+	MaxBackoff time.Duration
+	// Jitter randomizes each delay in [0, delay) to spread retries of concurrent callers.
+	Jitter bool
+	// Retryable classifies extra errors as retryable, on top of the sdk's own classification.
+	Retryable func(err error) bool
+}
+
+var (
+	// DefaultRetryPolicy is used by the *WithDefaultConfig constructors.
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts: retry.DefaultMaxAttempts,
+		BaseDelay:   100 * time.Millisecond,
+		MaxBackoff:  retry.DefaultMaxBackoff,
+		Jitter:      true,
+		Retryable:   IsRetryableError,
+	}
+
+	// NoRetryPolicy disables retry, it suits non-idempotent calls like Lambda invoke.
+	NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+)
+
+// NewRetryer creates an sdk retryer with p.
+func (p RetryPolicy) NewRetryer() aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		if p.MaxAttempts > 0 {
+			o.MaxAttempts = p.MaxAttempts
+		}
+
+		if p.MaxBackoff > 0 {
+			o.MaxBackoff = p.MaxBackoff
+		}
+
+		if p.BaseDelay > 0 {
+			o.Backoff = p
+		}
+
+		if p.Retryable != nil {
+			o.Retryables = append([]retry.IsErrorRetryable{
+				retry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+					if p.Retryable(err) {
+						return aws.TrueTernary
+					}
+
+					return aws.UnknownTernary
+				}),
+			}, o.Retryables...)
+		}
+	})
+}
+
+// BackoffDelay implements retry.BackoffDelayer, attempt starts from 1.
+func (p RetryPolicy) BackoffDelay(attempt int, _ error) (time.Duration, error) {
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = retry.DefaultMaxBackoff
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, maxBackoff)
+
+	if p.Jitter && delay > 0 {
+		//nolint:gosec
+		delay = time.Duration(rand.Int63n(int64(delay)))
+	}
+
+	return delay, nil
+}
+
+// maxAttempts returns MaxAttempts, or the sdk default if it is not set.
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts > 0 {
+		return p.MaxAttempts
+	}
+
+	return retry.DefaultMaxAttempts
+}
+
+// Apply returns a copy of cfg whose clients retry with p.
+func (p RetryPolicy) Apply(cfg aws.Config) aws.Config {
+	cfg = cfg.Copy()
+	cfg.Retryer = p.NewRetryer
+	cfg.RetryMaxAttempts = 0
+
+	return cfg
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable, or MaxAttempts calls are made,
+// waiting BackoffDelay between calls, attempt starts from 1. Errors are classified like the sdk retryer,
+// plus Retryable. Use it for retries the sdk retryer can't do, and disable the retryer of the calls in fn.
+func (p RetryPolicy) Do(ctx context.Context, fn func(attempt int) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt >= p.maxAttempts() || !p.isRetryable(err) {
+			return err
+		}
+
+		delay, _ := p.BackoffDelay(attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p RetryPolicy) isRetryable(err error) bool {
+	if p.Retryable != nil && p.Retryable(err) {
+		return true
+	}
+
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// loadDefaultConfig loads the default config with DefaultRetryPolicy.
+func loadDefaultConfig(optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	optFns = append(optFns, config.WithRetryer(DefaultRetryPolicy.NewRetryer))
+
+	return config.LoadDefaultConfig(context.TODO(), optFns...)
+}
@@ -0,0 +1,61 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetryPolicySuite struct {
+	suite.Suite
+}
+
+func TestRetryPolicy(t *testing.T) {
+	suite.Run(t, new(RetryPolicySuite))
+}
+
+func (s *RetryPolicySuite) Test_01_do() {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	p := RetryPolicy{MaxAttempts: 3}
+
+	calls := 0
+	err := p.Do(context.Background(), func(int) error {
+		calls++
+		return throttled
+	})
+	s.ErrorIs(err, throttled)
+	s.Equal(3, calls)
+
+	// errors the sdk doesn't retry are returned at once.
+	calls = 0
+	boom := errors.New("boom")
+	err = p.Do(context.Background(), func(int) error {
+		calls++
+		return boom
+	})
+	s.ErrorIs(err, boom)
+	s.Equal(1, calls)
+
+	p.Retryable = func(err error) bool { return errors.Is(err, boom) }
+	err = p.Do(context.Background(), func(attempt int) error {
+		if attempt < 2 {
+			return boom
+		}
+
+		return nil
+	})
+	s.Nil(err)
+}
+
+func (s *RetryPolicySuite) Test_02_clientOpts() {
+	cfg := aws.Config{RetryMaxAttempts: 7}
+
+	s.Equal(cfg.RetryMaxAttempts, applyClientOpts(cfg).RetryMaxAttempts)
+
+	got := applyClientOpts(cfg, WithRetryPolicy(NoRetryPolicy))
+	s.Equal(1, got.Retryer().MaxAttempts())
+}
@@ -2,32 +2,31 @@ package xaws
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
+	"mime"
+	"net/url"
 	"os"
+	"path"
 	"strings"
 	"time"
 
-	"github.com/avast/retry-go"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/aws/aws-sdk-go-v2/service/s3/types"
-	"github.com/aws/smithy-go"
 	"github.com/gookit/goutil/fsutil"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
-	_retryTimes    = 3
 	_defaultSaveTo = "/tmp"
+
+	// _mustUploadAttempts and _mustUploadBackoff are the retry of MustUploadWithAutoGzipped unless set by WithRetry.
+	_mustUploadAttempts = 3
+	_mustUploadBackoff  = time.Second
 )
 
 const (
@@ -39,38 +38,46 @@ var ErrGzSuffixRequired = errors.New("non gz format: .gz is required")
 type S3Client struct {
 	Config aws.Config
 	Client *s3.Client
+	// sdk is the client calls are made with, Client or the S3SdkClient given to NewS3WrapperWithClient.
+	sdk S3SdkClient
 
 	Bucket string
 	// upload timeout
 	Timeout int
 
 	SaveTo string
+
+	logger Logger
 }
 
 func NewS3Wrapper(bucket string, cfg aws.Config, opts ...S3OptionFunc) *S3Client {
 	opt := &S3Options{timeout: _defaultTimeoutSecs, saveTo: _defaultSaveTo}
 	bindS3Options(opt, opts...)
 
-	return &S3Client{
-		Config: cfg,
-		Client: s3.NewFromConfig(cfg),
-		Bucket: bucket,
-		// timeout
-		Timeout: opt.timeout,
-		SaveTo:  opt.saveTo,
-	}
+	cfg = applyClientOpts(cfg, opt.clientOpts...)
+
+	w := NewS3WrapperWithClient(bucket, s3.NewFromConfig(cfg, opt.clientOptions()...), opts...)
+	w.Config = cfg
+
+	return w
 }
 
-func NewS3WrapperWithClient(bucket string, client *s3.Client, opts ...S3OptionFunc) *S3Client {
+// NewS3WrapperWithClient creates wrapper with client, e.g. a mocked S3SdkClient,
+// Client is only set if client is an *s3.Client.
+func NewS3WrapperWithClient(bucket string, client S3SdkClient, opts ...S3OptionFunc) *S3Client {
 	opt := &S3Options{timeout: _defaultTimeoutSecs, saveTo: _defaultSaveTo}
 	bindS3Options(opt, opts...)
 
+	concrete, _ := client.(*s3.Client)
+
 	return &S3Client{
-		Client: client,
+		Client: concrete,
+		sdk:    client,
 		Bucket: bucket,
 		// timeout
 		Timeout: opt.timeout,
 		SaveTo:  opt.saveTo,
+		logger:  opt.logger,
 	}
 }
 
@@ -84,7 +91,7 @@ func MustNewS3WrapperWithDefaultConfig(bucket string, opts ...S3OptionFunc) *S3C
 }
 
 func NewS3WrapperWithDefaultConfig(bucket string, opts ...S3OptionFunc) (*S3Client, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -92,84 +99,176 @@ func NewS3WrapperWithDefaultConfig(bucket string, opts ...S3OptionFunc) (*S3Clie
 	return NewS3Wrapper(bucket, cfg, opts...), nil
 }
 
-func (w *S3Client) ListBuckets() (*s3.ListBucketsOutput, error) {
-	return w.Client.ListBuckets(context.TODO(), nil)
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *S3Client) SetLogger(l Logger) {
+	w.logger = l
 }
 
-func (w *S3Client) UploadToBucketWithAutoGzipped(localFile, s3path, bucket string) (*manager.UploadOutput, error) {
-	ctx, cancelFn := context.WithTimeout(context.Background(), time.Duration(w.Timeout)*time.Second)
-	if cancelFn != nil {
-		defer cancelFn()
+func (w *S3Client) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// api returns the client calls are made with, Client if the wrapper is not created by a constructor.
+func (w *S3Client) api() S3SdkClient {
+	if w.sdk != nil {
+		return w.sdk
 	}
 
-	raw, err := os.Open(localFile)
+	return w.Client
+}
+
+// callContext returns a context with timeout set by WithTimeout,
+// or defaultTimeout (seconds) when not set, 0 means no timeout.
+func callContext(opt *S3Options, defaultTimeout int) (context.Context, context.CancelFunc) {
+	timeout := opt.timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+
+	return context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+}
+
+// do runs fn with the per-call timeout set by WithTimeout, retries are done by the sdk retryer,
+// see WithRetry.
+func (w *S3Client) do(opt *S3Options, defaultTimeout int, fn func(ctx context.Context) error) error {
+	ctx, cancelFn := callContext(opt, defaultTimeout)
+	defer cancelFn()
+
+	return fn(ctx)
+}
+
+func (w *S3Client) ListBuckets(opts ...S3OptionFunc) (*s3.ListBucketsOutput, error) {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	var output *s3.ListBucketsOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		output, err = w.api().ListBuckets(ctx, nil, opt.clientOptions()...)
+
+		return err
+	})
+
+	return output, err
+}
+
+// UploadToBucketWithAutoGzipped compresses localFile on the fly and uploads it to bucket,
+// gzip is used by default and can be changed by WithCompression.
+func (w *S3Client) UploadToBucketWithAutoGzipped(localFile, s3path, bucket string, opts ...S3OptionFunc) (*manager.UploadOutput, error) {
+	opt := &S3Options{compression: CompressionGzip}
+	bindS3Options(opt, opts...)
+
+	s3path = opt.buildKey(s3path)
+
+	codec, err := GetCodec(opt.compression)
 	if err != nil {
 		return nil, err
 	}
-	defer raw.Close()
 
-	// Add .gz suffix if not present
-	if !strings.HasSuffix(s3path, ".gz") {
-		s3path += ".gz"
+	// Add codec suffix (.gz by default) if not present
+	if !strings.HasSuffix(s3path, codec.Ext()) {
+		s3path += codec.Ext()
 	}
 
-	reader, writer := io.Pipe()
+	var resp *manager.UploadOutput
 
-	go func() {
-		gzWriter := gzip.NewWriter(writer)
+	err = w.do(opt, w.Timeout, func(ctx context.Context) error {
+		raw, err := os.Open(localFile)
+		if err != nil {
+			return err
+		}
+		defer raw.Close()
 
-		_, err := io.Copy(gzWriter, raw)
+		stat, err := raw.Stat()
 		if err != nil {
-			writer.CloseWithError(err)
-			return
+			return err
 		}
 
-		if err := gzWriter.Close(); err != nil {
-			writer.CloseWithError(err)
-			return
+		src := newProgressReader(raw, stat.Size(), opt.progress)
+
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(s3path),
+			Body:   compressTo(codec, src),
 		}
+		applyUploadOptions(input, opt)
+		applyContentEncoding(input, codec)
 
-		writer.Close()
-	}()
+		up := manager.NewUploader(w.api(), opt.uploaderOptions)
+		resp, err = up.Upload(ctx, input)
 
-	up := manager.NewUploader(w.Client)
-	resp, err := up.Upload(ctx, &s3.PutObjectInput{
-		Bucket:          aws.String(bucket),
-		Key:             aws.String(s3path),
-		Body:            reader,
-		ContentEncoding: aws.String("gzip"),
+		return err
 	})
 
 	return resp, err
 }
 
-func (w *S3Client) UploadWithAutoGzipped(localFile, s3path string) (*manager.UploadOutput, error) {
-	return w.UploadToBucketWithAutoGzipped(localFile, s3path, w.Bucket)
+// UploadWithAutoGzipped is UploadToBucketWithAutoGzipped to w.Bucket, or the bucket set by WithBucket.
+func (w *S3Client) UploadWithAutoGzipped(localFile, s3path string, opts ...S3OptionFunc) (*manager.UploadOutput, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	return w.UploadToBucketWithAutoGzipped(localFile, s3path, opt.bucket, opts...)
 }
 
-func (w *S3Client) MustUploadWithAutoGzipped(localFile, s3path string) {
-	err := retry.Do(
-		func() error {
-			_, e := w.UploadWithAutoGzipped(localFile, s3path)
-			return e
-		},
-		retry.Attempts(_retryTimes),
-	)
+// MustUploadWithAutoGzipped is UploadWithAutoGzipped retried 3 times unless changed by WithRetry,
+// it panics if the upload still fails.
+func (w *S3Client) MustUploadWithAutoGzipped(localFile, s3path string, opts ...S3OptionFunc) {
+	opts = append([]S3OptionFunc{WithRetry(_mustUploadAttempts, _mustUploadBackoff)}, opts...)
 
+	_, err := w.UploadWithAutoGzipped(localFile, s3path, opts...)
 	panicIfErr(err)
 }
 
-func (w *S3Client) GetObjectContent(objectKey string) ([]byte, error) {
-	result, err := w.Client.GetObject(context.TODO(), &s3.GetObjectInput{
-		Bucket: aws.String(w.Bucket),
-		Key:    aws.String(objectKey),
+func (w *S3Client) GetObjectContent(objectKey string, opts ...S3OptionFunc) ([]byte, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	content, _, err := w.getObjectContent(objectKey, opt)
+
+	return content, err
+}
+
+// getObjectContent returns the content of objectKey and the response without body.
+func (w *S3Client) getObjectContent(objectKey string, opt *S3Options) ([]byte, *s3.GetObjectOutput, error) {
+	var (
+		content []byte
+		result  *s3.GetObjectOutput
+	)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+
+		result, err = w.api().GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+		if err != nil {
+			return err
+		}
+		defer result.Body.Close()
+
+		total := int64(-1)
+		if result.ContentLength != nil {
+			total = *result.ContentLength
+		}
+
+		content, err = io.ReadAll(newProgressReader(result.Body, total, opt.progress))
+
+		return err
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	defer result.Body.Close()
 
-	return io.ReadAll(result.Body)
+	result.Body = nil
+
+	return content, result, nil
 }
 
 // Deprecated: please use get object in the future
@@ -195,13 +294,18 @@ func (w *S3Client) DownloadFile(objectKey string, fileName string) error {
 	return err
 }
 
+// GetObject returns the content of objectKey, nil if it doesn't exist.
+//
+// Content is decompressed with WithCompression, or with the compression detected by WithAutoUnGzip
+// from magic bytes (gzip, zstd), Content-Encoding and Content-Type.
+// ErrDecompressFailed is returned if content is clearly compressed but cannot be decompressed.
 func (w *S3Client) GetObject(objectKey string, opts ...S3OptionFunc) ([]byte, error) {
-	opt := &S3Options{}
+	opt := &S3Options{bucket: w.Bucket}
 	bindS3Options(opt, opts...)
 
-	has, err := w.HasObject(objectKey)
+	has, err := w.HasObject(objectKey, opts...)
 	if err != nil {
-		log.Error().Err(err).Msg("got error when check file exist status")
+		w.log().Error("got error when check file exist status", "key", objectKey, "error", err)
 		return nil, err
 	}
 
@@ -209,30 +313,42 @@ func (w *S3Client) GetObject(objectKey string, opts ...S3OptionFunc) ([]byte, er
 		return nil, nil
 	}
 
-	content, err := w.GetObjectContent(objectKey)
+	content, result, err := w.getObjectContent(objectKey, opt)
 	if err != nil {
 		return nil, err
 	}
 
-	if opt.autoUnGzip {
-		// Attempt to ungzip only when autoUnGzip is true
-		reader, err := gzip.NewReader(bytes.NewReader(content))
-		if err == nil {
-			// Successfully created gzip reader, try to uncompress
-			defer reader.Close()
+	compression, certain := opt.compression, false
 
-			decompressed, err := io.ReadAll(reader)
-			if err == nil {
-				return decompressed, nil
-			}
+	switch {
+	case compression != "":
+		certain = sniffCompression(content) == compression || aws.ToString(result.ContentEncoding) == compression
+	case opt.autoUnGzip:
+		compression, certain = detectCompression(content, aws.ToString(result.ContentEncoding), aws.ToString(result.ContentType))
+	}
 
-			// If uncompression fails, log a warning
-			log.Warn().Err(err).Msg("failed to uncompress content, returning original content")
-		}
+	if compression == "" || compression == CompressionNone {
+		return content, nil
 	}
 
-	// Return original content if autoUnGzip is false,
-	// or if it's not gzipped, or if uncompression fails
+	codec, err := GetCodec(compression)
+	if err != nil {
+		return nil, err
+	}
+
+	decompressed, err := decompress(codec, content)
+	if err == nil {
+		return decompressed, nil
+	}
+
+	// content is clearly compressed, returning it as is would only hide the problem.
+	if certain {
+		return nil, fmt.Errorf("%w: %s (%s): %w", ErrDecompressFailed, objectKey, compression, err)
+	}
+
+	// content may just not be compressed, e.g. WithCompression on a plain object.
+	w.log().Warn("failed to uncompress content, returning original content", "compression", compression, "error", err)
+
 	return content, nil
 }
 
@@ -261,6 +377,9 @@ func (w *S3Client) GetObject(objectKey string, opts ...S3OptionFunc) ([]byte, er
 //   - WithSavedName(name string):
 //     Specifies a custom name for the downloaded file. This overrides the original filename.
 //
+//   - WithProgress(fn ProgressFunc):
+//     Reports downloaded bytes, e.g. to render a progress bar.
+//
 // Returns:
 //
 //	string: The full path of the downloaded (or existing) file.
@@ -276,7 +395,7 @@ func (w *S3Client) GetObject(objectKey string, opts ...S3OptionFunc) ([]byte, er
 //	    fmt.Println("Download failed")
 //	}
 func (w *S3Client) Download(objectKey string, opts ...S3OptionFunc) (string, error) {
-	opt := &S3Options{folderLevel: 1}
+	opt := &S3Options{bucket: w.Bucket, folderLevel: 1}
 	bindS3Options(opt, opts...)
 
 	name := fsutil.Name(objectKey)
@@ -309,55 +428,57 @@ func (w *S3Client) Download(objectKey string, opts ...S3OptionFunc) (string, err
 	}
 
 	// Use GetObject instead of DownloadFile
-	content, err := w.GetObject(objectKey)
+	content, err := w.GetObject(objectKey, WithBucket(opt.bucket), WithProgress(opt.progress),
+		WithRequestPayer(opt.requestPayer), WithTransferAcceleration(opt.accelerate))
 	if err != nil {
-		log.Error().Err(err).Msg("cannot download file")
+		w.log().Error("cannot download file", "key", objectKey, "error", err)
 		return "", err
 	}
 
 	// Write content to file
 	err = os.WriteFile(dst, content, 0o644) //nolint:mnd
 	if err != nil {
-		log.Error().Err(err).Msg("cannot write downloaded content to file")
+		w.log().Error("cannot write downloaded content to file", "file", dst, "error", err)
 		return "", err
 	}
 
 	return dst, nil
 }
 
-func (w *S3Client) HasObject(objectKey string) (bool, error) {
-	_, err := w.Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
-		Bucket: aws.String(w.Bucket),
-		Key:    aws.String(objectKey),
-	})
-	if err != nil {
-		var apiError smithy.APIError
-		if errors.As(err, &apiError) {
-			switch apiError.(type) {
-			case *types.NotFound:
-				return false, nil
-			default:
-				return false, err
-			}
-		}
+// HasObject reports whether objectKey exists, see StatObject for its metadata.
+func (w *S3Client) HasObject(objectKey string, opts ...S3OptionFunc) (bool, error) {
+	_, err := w.StatObject(objectKey, opts...)
+	if errors.Is(err, ErrObjectNotFound) {
+		return false, nil
 	}
 
-	return true, err
+	return err == nil, err
 }
 
 // DeleteObject deletes a single object from the S3 bucket.
-// It automatically removes the bucket prefix from the objectKey if present.
-func (w *S3Client) DeleteObject(objectKey string) error {
-	// Remove the "s3://" prefix if present
-	objectKey = strings.TrimPrefix(objectKey, "s3://")
-
-	// Remove the bucket name from the beginning of the objectKey if present
-	bucketPrefix := w.Bucket + "/"
-	objectKey = strings.TrimPrefix(objectKey, bucketPrefix)
-
-	_, err := w.Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-		Bucket: aws.String(w.Bucket),
-		Key:    aws.String(objectKey),
+// objectKey can also be an "s3://bucket/key" uri or an s3 arn, then the object is deleted from its bucket.
+func (w *S3Client) DeleteObject(objectKey string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	bucket := opt.bucket
+
+	if strings.HasPrefix(objectKey, "s3://") || strings.HasPrefix(objectKey, "arn:") {
+		uri, err := ParseS3URI(objectKey)
+		if err != nil {
+			return err
+		}
+
+		bucket, objectKey = uri.Bucket, uri.Key
+	}
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+
+		return err
 	})
 	if err != nil {
 		return fmt.Errorf("failed to delete object %s: %w", objectKey, err)
@@ -368,36 +489,45 @@ func (w *S3Client) DeleteObject(objectKey string) error {
 
 // UploadLargeObject uses an upload manager to upload data to an object in a bucket.
 // The upload manager breaks large data into parts and uploads the parts concurrently.
-func (w *S3Client) UploadLargeObject(bucketName string, objectKey string, largeObject []byte) error {
+func (w *S3Client) UploadLargeObject(bucketName string, objectKey string, largeObject []byte, opts ...S3OptionFunc) error {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
 	var (
 		partMiBs int64 = 10
 		kilo     int64 = 1024
 	)
 
-	largeBuffer := bytes.NewReader(largeObject)
-	uploader := manager.NewUploader(w.Client, func(u *manager.Uploader) {
+	uploader := manager.NewUploader(w.api(), opt.uploaderOptions, func(u *manager.Uploader) {
 		u.PartSize = partMiBs * kilo * kilo
 	})
 
-	if _, err := uploader.Upload(context.TODO(), &s3.PutObjectInput{
-		Bucket:          aws.String(bucketName),
-		Key:             aws.String(objectKey),
-		Body:            largeBuffer,
-		ContentEncoding: aws.String("gzip"),
-	}); err != nil {
-		log.Printf("Couldn't upload large object to %v:%v. Here's why: %v\n",
-			bucketName, objectKey, err)
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		input := &s3.PutObjectInput{
+			Bucket:          aws.String(bucketName),
+			Key:             aws.String(objectKey),
+			Body:            newProgressReader(bytes.NewReader(largeObject), int64(len(largeObject)), opt.progress),
+			ContentEncoding: aws.String("gzip"),
+		}
+		applyUploadOptions(input, opt)
+
+		_, err := uploader.Upload(ctx, input)
+
+		return err
+	})
+	if err != nil {
+		w.log().Error("cannot upload large object", "bucket", bucketName, "key", objectKey, "error", err)
 		return err
 	}
 
 	return nil
 }
 
-// UploadRawData uploads and save raw data to s3 object key(no encoding:gzip supported).
+// MustUploadRawData is UploadRawData, it panics on error.
 func (w *S3Client) MustUploadRawData(objectKey string, raw []byte, opts ...S3OptionFunc) {
 	err := w.UploadRawData(objectKey, raw, opts...)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot upload raw data")
+		panic(fmt.Errorf("cannot upload raw data to %s: %w", objectKey, err))
 	}
 }
 
@@ -405,49 +535,102 @@ func (w *S3Client) PutObject(objectKey string, raw []byte, opts ...S3OptionFunc)
 	return w.UploadRawData(objectKey, raw, opts...)
 }
 
+// UploadRawData uploads raw to objectKey, raw is compressed before upload when WithCompression is set.
 func (w *S3Client) UploadRawData(objectKey string, raw []byte, opts ...S3OptionFunc) error {
 	opt := &S3Options{bucket: w.Bucket, withGz: false}
 	bindS3Options(opt, opts...)
 
+	objectKey = opt.buildKey(objectKey)
+
 	if opt.withGz {
 		if fsutil.Suffix(objectKey) != _dotgz {
 			objectKey += _dotgz
 		}
 	}
 
-	ul := manager.NewUploader(w.Client)
+	ul := manager.NewUploader(w.api(), opt.uploaderOptions)
 
-	_, err := ul.Upload(context.TODO(), &s3.PutObjectInput{
-		Bucket: aws.String(opt.bucket),
-		Key:    aws.String(objectKey),
-		Body:   bytes.NewReader(raw),
-	})
+	return w.do(opt, 0, func(ctx context.Context) error {
+		body := newProgressReader(bytes.NewReader(raw), int64(len(raw)), opt.progress)
 
-	return err
+		key, body, codec, err := compressBody(objectKey, body, opt.compression)
+		if err != nil {
+			return err
+		}
+
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(key),
+			Body:   body,
+		}
+		applyUploadOptions(input, opt)
+		applyContentEncoding(input, codec)
+
+		_, err = ul.Upload(ctx, input)
+
+		return err
+	})
 }
 
-func (w *S3Client) UploadRawDataToGz(raw string, objectKey string) error {
-	if fsutil.Suffix(objectKey) != _dotgz {
-		return ErrGzSuffixRequired
+// applyUploadOptions sets content-type, metadata, tags and storage class of input from opt.
+// Content-Type is detected from the object key extension (ignoring .gz) when not given.
+func applyUploadOptions(input *s3.PutObjectInput, opt *S3Options) {
+	contentType := opt.contentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(strings.TrimSuffix(aws.ToString(input.Key), _dotgz)))
+	}
+
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
 	}
 
-	minLen := 12
-	name := "/tmp/" + randSeq(minLen) + "_.txt"
+	if len(opt.metadata) != 0 {
+		input.Metadata = opt.metadata
+	}
 
-	fsutil.MustSave(name, raw)
-	// defer os.Remove(name)
+	if len(opt.tags) != 0 {
+		tags := url.Values{}
+		for k, v := range opt.tags {
+			tags.Set(k, v)
+		}
 
-	_, err := w.UploadWithAutoGzipped(name, objectKey)
+		input.Tagging = aws.String(tags.Encode())
+	}
 
-	return err
+	if opt.storageClass != "" {
+		input.StorageClass = opt.storageClass
+	}
 }
 
-var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+// applyContentEncoding sets Content-Encoding of input to the name of codec, if any.
+func applyContentEncoding(input *s3.PutObjectInput, codec Codec) {
+	if codec == nil || codec.Name() == CompressionNone {
+		return
+	}
 
+	input.ContentEncoding = aws.String(codec.Name())
+}
+
+// UploadRawDataToGz gzips raw in memory and uploads it to objectKey, which must end with .gz.
+func (w *S3Client) UploadRawDataToGz(raw string, objectKey string, opts ...S3OptionFunc) error {
+	if fsutil.Suffix(objectKey) != _dotgz {
+		return ErrGzSuffixRequired
+	}
+
+	return w.UploadRawData(objectKey, []byte(raw), append(opts, WithCompression(CompressionGzip))...)
+}
+
+var letters = []byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+// randSeq returns n random letters read from crypto/rand.
 func randSeq(n int) string {
-	b := make([]rune, n)
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[int(b[i])%len(letters)]
 	}
 
 	return string(b)
@@ -461,7 +644,7 @@ func randSeq(n int) string {
 //	@return []string: list s3 files found
 //	@return error
 func (w *S3Client) ListObjects(prefix string, opts ...S3OptionFunc) ([]string, error) {
-	opt := &S3Options{}
+	opt := &S3Options{bucket: w.Bucket}
 	bindS3Options(opt, opts...)
 
 	var found []string
@@ -474,7 +657,7 @@ func (w *S3Client) ListObjects(prefix string, opts ...S3OptionFunc) ([]string, e
 
 	for {
 		input := &s3.ListObjectsV2Input{
-			Bucket: aws.String(w.Bucket),
+			Bucket: aws.String(opt.bucket),
 			Prefix: aws.String(prefix),
 			// MaxKeys: aws.Int32(maxKeysIn),
 			// pagination
@@ -486,7 +669,14 @@ func (w *S3Client) ListObjects(prefix string, opts ...S3OptionFunc) ([]string, e
 			input.MaxKeys = &maxKeysIn
 		}
 
-		resp, err := w.Client.ListObjectsV2(context.TODO(), input)
+		var resp *s3.ListObjectsV2Output
+
+		err := w.do(opt, 0, func(ctx context.Context) error {
+			var err error
+			resp, err = w.api().ListObjectsV2(ctx, input, opt.clientOptions()...)
+
+			return err
+		})
 		if err != nil {
 			return found, err
 		}
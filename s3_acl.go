@@ -0,0 +1,100 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// PutObjectAcl sets the canned acl of objectKey, e.g. types.ObjectCannedACLPublicRead.
+// Buckets with object ownership "bucket owner enforced" reject acls with AccessControlListNotSupported.
+func (w *S3Client) PutObjectAcl(objectKey string, acl types.ObjectCannedACL, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().PutObjectAcl(ctx, &s3.PutObjectAclInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+			ACL:    acl,
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set acl %s of %s: %w", acl, objectKey, err)
+	}
+
+	return nil
+}
+
+// GetObjectAcl returns the owner and grants of objectKey.
+func (w *S3Client) GetObjectAcl(objectKey string, opts ...S3OptionFunc) (*s3.GetObjectAclOutput, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var output *s3.GetObjectAclOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		output, err = w.api().GetObjectAcl(ctx, &s3.GetObjectAclInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+
+	return output, err
+}
+
+// MakePublicRead grants everyone read access to objectKey and returns its public url.
+//
+// Usage:
+//
+//	link, err := w.MakePublicRead("assets/logo.png")
+func (w *S3Client) MakePublicRead(objectKey string, opts ...S3OptionFunc) (string, error) {
+	if err := w.PutObjectAcl(objectKey, types.ObjectCannedACLPublicRead, opts...); err != nil {
+		return "", err
+	}
+
+	return w.GetPublicURL(objectKey, opts...), nil
+}
+
+// GetPublicURL returns the https url of objectKey, the object is only reachable if it is public.
+// Virtual-hosted addressing is used unless the client uses path style or the bucket name contains dots,
+// with a custom endpoint (e.g. minio) the url is built on that endpoint.
+func (w *S3Client) GetPublicURL(objectKey string, opts ...S3OptionFunc) string {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	options := w.api().Options()
+	key := escapeKey(objectKey)
+
+	if endpoint := aws.ToString(options.BaseEndpoint); endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err == nil && u.Host != "" {
+			if options.UsePathStyle {
+				return strings.TrimSuffix(u.String(), "/") + "/" + opt.bucket + "/" + key
+			}
+
+			return u.Scheme + "://" + opt.bucket + "." + u.Host + "/" + key
+		}
+	}
+
+	region := options.Region
+	if region == "" {
+		region = _usEast1
+	}
+
+	if options.UsePathStyle || strings.Contains(opt.bucket, ".") {
+		return fmt.Sprintf("https://s3.%s.amazonaws.com/%s/%s", region, opt.bucket, key)
+	}
+
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", opt.bucket, region, key)
+}
@@ -0,0 +1,255 @@
+package xaws
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+var ErrUnsafeArchivePath = errors.New("archive entry escapes destination")
+
+// UploadDirAsTarGz streams a tar.gz of localDir to objectKey, no temp file is written.
+// Entries are relative to localDir, Content-Type is application/gzip unless WithContentType is set.
+// It accepts the same options as UploadStream except WithCompression.
+//
+// Usage:
+//
+//	out, err := w.UploadDirAsTarGz("./output", "runs/2024-06-01.tar.gz")
+//	err = w.DownloadAndExtractTarGz("runs/2024-06-01.tar.gz", "./restored")
+func (w *S3Client) UploadDirAsTarGz(localDir, objectKey string, opts ...S3OptionFunc) (*manager.UploadOutput, error) {
+	opts = append([]S3OptionFunc{WithContentType("application/gzip")}, opts...)
+	opts = append(opts, WithCompression(""))
+
+	sw := w.NewWriter(objectKey, opts...)
+
+	if err := writeTarGz(sw, localDir); err != nil {
+		_ = sw.Abort(err)
+		return nil, err
+	}
+
+	if err := sw.Close(); err != nil {
+		return nil, err
+	}
+
+	return sw.Output(), nil
+}
+
+// DownloadAndExtractTarGz streams objectKey and extracts it into destDir, which is created if needed.
+// Existing files are overwritten, entries escaping destDir fail with ErrUnsafeArchivePath.
+func (w *S3Client) DownloadAndExtractTarGz(objectKey, destDir string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	return w.do(opt, 0, func(ctx context.Context) error {
+		result, err := w.api().GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+		if err != nil {
+			return err
+		}
+		defer result.Body.Close()
+
+		total := int64(-1)
+		if result.ContentLength != nil {
+			total = *result.ContentLength
+		}
+
+		return extractTarGz(newProgressReader(result.Body, total, opt.progress), destDir)
+	})
+}
+
+// writeTarGz writes the regular files, directories and symlinks under dir as a tar.gz to dst.
+func writeTarGz(dst io.Writer, dir string) error {
+	gw := gzip.NewWriter(dst)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var link string
+
+		switch {
+		case info.Mode().IsRegular(), info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		default:
+			// sockets, devices and pipes are skipped.
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// extractTarGz extracts the tar.gz read from src into destDir.
+func extractTarGz(src io.Reader, destDir string) error {
+	gr, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("%w: %s", ErrUnsafeArchivePath, hdr.Name)
+		}
+
+		if err := checkNoSymlinkDirs(destDir, name); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrUnsafeArchivePath, hdr.Name, err)
+		}
+
+		target := filepath.Join(destDir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil { //nolint:mnd
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractTarFile(tr, target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if !filepath.IsLocal(filepath.Join(filepath.Dir(name), hdr.Linkname)) {
+				return fmt.Errorf("%w: %s -> %s", ErrUnsafeArchivePath, hdr.Name, hdr.Linkname)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { //nolint:mnd
+				return err
+			}
+
+			_ = os.Remove(target)
+
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkNoSymlinkDirs fails if a directory of name under destDir is a symlink, so entries can't be written
+// through a link extracted earlier, e.g. "a -> .." then "a/x".
+func checkNoSymlinkDirs(destDir, name string) error {
+	dir := destDir
+
+	for _, part := range strings.Split(filepath.Dir(name), string(filepath.Separator)) {
+		if part == "." {
+			continue
+		}
+
+		dir = filepath.Join(dir, part)
+
+		info, err := os.Lstat(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", dir)
+		}
+	}
+
+	return nil
+}
+
+func extractTarFile(r io.Reader, target string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { //nolint:mnd
+		return err
+	}
+
+	// an existing symlink is replaced instead of written through.
+	if info, err := os.Lstat(target); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,81 @@
+package xaws
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ArchiveSuite struct {
+	suite.Suite
+}
+
+func TestArchive(t *testing.T) {
+	suite.Run(t, new(ArchiveSuite))
+}
+
+func (s *ArchiveSuite) Test_01_roundTrip() {
+	src := s.T().TempDir()
+	s.Nil(os.MkdirAll(filepath.Join(src, "a", "b"), 0o755))
+	s.Nil(os.WriteFile(filepath.Join(src, "top.txt"), []byte("top"), 0o644))
+	s.Nil(os.WriteFile(filepath.Join(src, "a", "b", "deep.json"), []byte(`{"k":1}`), 0o600))
+	s.Nil(os.Symlink("top.txt", filepath.Join(src, "link.txt")))
+
+	var buf bytes.Buffer
+	s.Nil(writeTarGz(&buf, src))
+
+	dst := filepath.Join(s.T().TempDir(), "out")
+	s.Nil(extractTarGz(&buf, dst))
+
+	raw, err := os.ReadFile(filepath.Join(dst, "a", "b", "deep.json"))
+	s.Nil(err)
+	s.Equal(`{"k":1}`, string(raw))
+
+	info, err := os.Stat(filepath.Join(dst, "a", "b", "deep.json"))
+	s.Nil(err)
+	s.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	raw, err = os.ReadFile(filepath.Join(dst, "link.txt"))
+	s.Nil(err)
+	s.Equal("top", string(raw))
+}
+
+func (s *ArchiveSuite) Test_02_unsafePath() {
+	for _, hdrs := range [][]*tar.Header{
+		{{Name: "../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{{Name: "/etc/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}},
+		{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../etc/passwd"}},
+		// each link is local on its own, together they point out of the destination.
+		{
+			{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			{Name: "a/b/c", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			{Name: "a/b/c/x", Typeflag: tar.TypeReg, Mode: 0o644},
+		},
+	} {
+		var buf bytes.Buffer
+
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+
+		for _, hdr := range hdrs {
+			s.Nil(tw.WriteHeader(hdr))
+		}
+
+		s.Nil(tw.Close())
+		s.Nil(gw.Close())
+
+		parent := s.T().TempDir()
+		dst := filepath.Join(parent, "out")
+
+		err := extractTarGz(&buf, dst)
+		s.ErrorIs(err, ErrUnsafeArchivePath, hdrs[len(hdrs)-1].Name)
+
+		_, err = os.Lstat(filepath.Join(parent, "x"))
+		s.ErrorIs(err, os.ErrNotExist)
+	}
+}
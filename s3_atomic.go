@@ -0,0 +1,59 @@
+package xaws
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	_atomicTmpSuffix = ".xaws-tmp-"
+)
+
+// UploadAtomic uploads raw to a temporary key, and only after S3 validated its Content-MD5
+// it copies the temporary object to objectKey and deletes the temporary one,
+// so readers of objectKey never observe a partially written object.
+//
+// Options are the same as UploadRawData except compression, WithBucket sets the destination bucket.
+func (w *S3Client) UploadAtomic(objectKey string, raw []byte, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	objectKey = opt.buildKey(objectKey)
+
+	minLen := 12
+	tmpKey := objectKey + _atomicTmpSuffix + randSeq(minLen)
+
+	sum := md5.Sum(raw) //nolint:gosec
+
+	// S3 rejects the upload with BadDigest when content doesn't match Content-MD5.
+	err := w.do(opt, w.Timeout, func(ctx context.Context) error {
+		input := &s3.PutObjectInput{
+			Bucket:     aws.String(w.Bucket),
+			Key:        aws.String(tmpKey),
+			Body:       bytes.NewReader(raw),
+			ContentMD5: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+		}
+		applyUploadOptions(input, opt)
+
+		_, err := w.api().PutObject(ctx, input, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload temporary object %s: %w", tmpKey, err)
+	}
+
+	defer func() {
+		if err := w.DeleteObject(tmpKey); err != nil {
+			w.log().Warn("cannot delete temporary object", "key", tmpKey, "error", err)
+		}
+	}()
+
+	return w.copyObject(w.Bucket, tmpKey, objectKey, opt)
+}
@@ -0,0 +1,211 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	_usEast1 = "us-east-1"
+)
+
+// CreateBucket creates bucket in the region of the client and waits until it exists.
+func (w *S3Client) CreateBucket(bucket string, opts ...S3OptionFunc) error {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	input := &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	}
+
+	// us-east-1 is the default location and must not be set explicitly.
+	if region := w.api().Options().Region; region != "" && region != _usEast1 {
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		}
+	}
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().CreateBucket(ctx, input, opt.clientOptions()...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", bucket, err)
+	}
+
+	longTo := 1
+	waiter := s3.NewBucketExistsWaiter(w.api())
+
+	return waiter.Wait(
+		context.Background(),
+		&s3.HeadBucketInput{Bucket: aws.String(bucket)},
+		time.Duration(longTo)*time.Minute,
+		func(o *s3.BucketExistsWaiterOptions) {
+			o.ClientOptions = append(o.ClientOptions, opt.clientOptions()...)
+		},
+	)
+}
+
+// DeleteBucket deletes bucket, the bucket must be empty.
+func (w *S3Client) DeleteBucket(bucket string, opts ...S3OptionFunc) error {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().DeleteBucket(ctx, &s3.DeleteBucketInput{
+			Bucket: aws.String(bucket),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete bucket %s: %w", bucket, err)
+	}
+
+	return nil
+}
+
+// BucketExists checks if bucket exists and is accessible.
+func (w *S3Client) BucketExists(bucket string, opts ...S3OptionFunc) (bool, error) {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().HeadBucket(ctx, &s3.HeadBucketInput{
+			Bucket: aws.String(bucket),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		var apiError smithy.APIError
+		if errors.As(err, &apiError) {
+			switch apiError.(type) {
+			case *types.NotFound:
+				return false, nil
+			default:
+				return false, err
+			}
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LifecycleRule is a simplified lifecycle rule applied to objects under Prefix.
+//
+// Usage:
+//
+//	rule := NewLifecycleRule("logs", "logs/").
+//		TransitionAfter(30, types.TransitionStorageClassStandardIa).
+//		TransitionAfter(90, types.TransitionStorageClassGlacier).
+//		ExpireAfter(365)
+//	err := w.PutBucketLifecycle(bucket, rule)
+type LifecycleRule struct {
+	ID     string
+	Prefix string
+
+	// ExpireAfterDays deletes objects N days after creation, 0 means never.
+	ExpireAfterDays int
+	Transitions     []LifecycleTransition
+}
+
+type LifecycleTransition struct {
+	Days         int
+	StorageClass types.TransitionStorageClass
+}
+
+func NewLifecycleRule(id, prefix string) *LifecycleRule {
+	return &LifecycleRule{ID: id, Prefix: prefix}
+}
+
+// ExpireAfter expires objects n days after creation.
+func (r *LifecycleRule) ExpireAfter(days int) *LifecycleRule {
+	r.ExpireAfterDays = days
+	return r
+}
+
+// TransitionAfter moves objects to storage class n days after creation.
+func (r *LifecycleRule) TransitionAfter(days int, class types.TransitionStorageClass) *LifecycleRule {
+	r.Transitions = append(r.Transitions, LifecycleTransition{Days: days, StorageClass: class})
+	return r
+}
+
+func (r *LifecycleRule) toS3Rule() types.LifecycleRule {
+	rule := types.LifecycleRule{
+		ID:     aws.String(r.ID),
+		Status: types.ExpirationStatusEnabled,
+		Filter: &types.LifecycleRuleFilterMemberPrefix{Value: r.Prefix},
+	}
+
+	if r.ExpireAfterDays > 0 {
+		rule.Expiration = &types.LifecycleExpiration{Days: aws.Int32(int32(r.ExpireAfterDays))}
+	}
+
+	for _, t := range r.Transitions {
+		rule.Transitions = append(rule.Transitions, types.Transition{
+			Days:         aws.Int32(int32(t.Days)),
+			StorageClass: t.StorageClass,
+		})
+	}
+
+	return rule
+}
+
+// PutBucketLifecycle replaces the lifecycle configuration of bucket with rules.
+func (w *S3Client) PutBucketLifecycle(bucket string, rules ...*LifecycleRule) error {
+	return w.PutBucketLifecycleWithOptions(bucket, rules)
+}
+
+// PutBucketLifecycleWithOptions is PutBucketLifecycle with options, e.g. WithTimeout and WithRetry.
+func (w *S3Client) PutBucketLifecycleWithOptions(bucket string, rules []*LifecycleRule, opts ...S3OptionFunc) error {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	s3Rules := make([]types.LifecycleRule, 0, len(rules))
+	for _, r := range rules {
+		s3Rules = append(s3Rules, r.toS3Rule())
+	}
+
+	return w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucket),
+			LifecycleConfiguration: &types.BucketLifecycleConfiguration{
+				Rules: s3Rules,
+			},
+		}, opt.clientOptions()...)
+
+		return err
+	})
+}
+
+// GetBucketLifecycle returns the lifecycle rules of bucket.
+func (w *S3Client) GetBucketLifecycle(bucket string, opts ...S3OptionFunc) ([]types.LifecycleRule, error) {
+	opt := &S3Options{}
+	bindS3Options(opt, opts...)
+
+	var output *s3.GetBucketLifecycleConfigurationOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		output, err = w.api().GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucket),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Rules, nil
+}
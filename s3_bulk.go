@@ -0,0 +1,140 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const _defaultHeadConcurrency = 32
+
+// DownloadMany fetches the content of keys with at most concurrency parallel requests.
+//
+// Each key is retried by the sdk retryer, see WithRetry, keys that still fail are left out of the result map
+// and reported together in the returned error (errors.Join of per-key errors).
+//
+// Usage:
+//
+//	contents, err := w.DownloadMany(keys, 32)
+//	if err != nil {
+//		log.Warn().Err(err).Int("ok", len(contents)).Msg("some keys failed")
+//	}
+func (w *S3Client) DownloadMany(keys []string, concurrency int, opts ...S3OptionFunc) (map[string][]byte, error) {
+	var mu sync.Mutex
+
+	found := make(map[string][]byte, len(keys))
+
+	err := w.DownloadManyFunc(keys, concurrency, func(key string, content []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		found[key] = content
+
+		return nil
+	}, opts...)
+
+	return found, err
+}
+
+// DownloadManyFunc is the callback form of DownloadMany, fn is called concurrently
+// for each downloaded key, so it must be safe for concurrent use.
+//
+// Errors returned by fn are collected along with download errors.
+func (w *S3Client) DownloadManyFunc(keys []string, concurrency int, fn func(key string, content []byte) error, opts ...S3OptionFunc) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	sem := make(chan struct{}, concurrency)
+
+	for _, key := range keys {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			content, err := w.GetObjectContent(key, opts...)
+			if err == nil {
+				err = fn(key, content)
+			}
+
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				mu.Unlock()
+			}
+		}(key)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// HasObjects checks the existence of keys with HeadObject calls, at most 32 in parallel
+// unless changed by WithConcurrency.
+//
+// Each key is retried by the sdk retryer, see WithRetry, keys that still fail are left out of the result map
+// and reported together in the returned error.
+//
+// Usage:
+//
+//	exists, err := w.HasObjects(candidates)
+//	for _, key := range candidates {
+//		if !exists[key] {
+//			...
+//		}
+//	}
+func (w *S3Client) HasObjects(keys []string, opts ...S3OptionFunc) (map[string]bool, error) {
+	opt := &S3Options{concurrency: _defaultHeadConcurrency}
+	bindS3Options(opt, opts...)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	found := make(map[string]bool, len(keys))
+	sem := make(chan struct{}, max(opt.concurrency, 1))
+
+	for _, key := range keys {
+		wg.Add(1)
+
+		sem <- struct{}{}
+
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			has, err := w.HasObject(key, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+
+			found[key] = has
+		}(key)
+	}
+
+	wg.Wait()
+
+	return found, errors.Join(errs...)
+}
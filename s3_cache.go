@@ -0,0 +1,121 @@
+package xaws
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gookit/goutil/fsutil"
+)
+
+const (
+	_cacheDir = ".xaws-cache"
+)
+
+// GetObjectIfChanged issues a conditional GET, content is only downloaded when
+// the ETag of object differs from etag (and it's modified after WithIfModifiedSince if set).
+//
+// Returns:
+//   - content: object content, nil when notModified.
+//   - newEtag: the current ETag of the object, equals etag when notModified.
+//   - notModified: true if server responded 304 Not Modified.
+//
+// Usage:
+//
+//	content, etag, notModified, err := w.GetObjectIfChanged(key, lastEtag)
+func (w *S3Client) GetObjectIfChanged(objectKey, etag string, opts ...S3OptionFunc) ([]byte, string, bool, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(opt.bucket),
+		Key:    aws.String(objectKey),
+	}
+
+	if etag != "" {
+		input.IfNoneMatch = aws.String(etag)
+	}
+
+	if !opt.ifModifiedSince.IsZero() {
+		input.IfModifiedSince = aws.Time(opt.ifModifiedSince)
+	}
+
+	var (
+		content []byte
+		newEtag string
+	)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		result, err := w.api().GetObject(ctx, input, opt.clientOptions()...)
+		if err != nil {
+			return err
+		}
+		defer result.Body.Close()
+
+		newEtag = aws.ToString(result.ETag)
+		content, err = io.ReadAll(result.Body)
+
+		return err
+	})
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotModified {
+			return nil, etag, true, nil
+		}
+
+		return nil, "", false, err
+	}
+
+	return content, newEtag, false, nil
+}
+
+// GetObjectCached returns the content of objectKey from a local cache under SaveTo,
+// the object is only downloaded again when its ETag changed.
+func (w *S3Client) GetObjectCached(objectKey string, opts ...S3OptionFunc) ([]byte, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	sum := sha1.Sum([]byte(opt.bucket + "/" + objectKey)) //nolint:gosec
+	dst := fsutil.JoinPaths(w.SaveTo, _cacheDir, hex.EncodeToString(sum[:]))
+	etagFile := dst + ".etag"
+
+	var etag string
+
+	if fsutil.FileExists(dst) && fsutil.FileExists(etagFile) {
+		raw, err := os.ReadFile(etagFile)
+		if err == nil {
+			etag = string(raw)
+		}
+	}
+
+	content, newEtag, notModified, err := w.GetObjectIfChanged(objectKey, etag, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if notModified {
+		return os.ReadFile(dst)
+	}
+
+	if err := fsutil.MkParentDir(dst); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(dst, content, 0o644); err != nil { //nolint:mnd
+		w.log().Warn("cannot write object to cache", "key", objectKey, "error", err)
+		return content, nil
+	}
+
+	if err := os.WriteFile(etagFile, []byte(newEtag), 0o644); err != nil { //nolint:mnd
+		w.log().Warn("cannot write etag to cache", "key", objectKey, "error", err)
+	}
+
+	return content, nil
+}
@@ -0,0 +1,229 @@
+package xaws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CompressionNone   = "none"
+	CompressionGzip   = "gzip"
+	CompressionZstd   = "zstd"
+	CompressionBrotli = "br"
+)
+
+var (
+	ErrUnknownCompression = errors.New("unknown compression")
+	ErrDecompressFailed   = errors.New("decompression failed")
+)
+
+var (
+	_gzipMagic = []byte{0x1f, 0x8b}
+	_zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// Codec compresses and decompresses object content.
+type Codec interface {
+	// Name is the value passed to WithCompression, and also used as Content-Encoding.
+	Name() string
+	// Ext is the object key suffix, e.g. ".gz", empty means no suffix.
+	Ext() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{}
+)
+
+func init() {
+	RegisterCodec(noneCodec{})
+	RegisterCodec(gzipCodec{})
+	RegisterCodec(zstdCodec{})
+	RegisterCodec(brotliCodec{})
+}
+
+// RegisterCodec adds or replaces a codec, so it can be used by WithCompression(c.Name()).
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs[c.Name()] = c
+}
+
+// GetCodec returns the codec registered with name.
+func GetCodec(name string) (Codec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, name)
+	}
+
+	return c, nil
+}
+
+// compressTo streams r compressed by codec into a pipe and returns its read end.
+func compressTo(codec Codec, r io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+
+	go func() {
+		cw, err := codec.NewWriter(writer)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		if _, err := io.Copy(cw, r); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		if err := cw.Close(); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+
+		writer.Close()
+	}()
+
+	return reader
+}
+
+// compressBody wraps body with the codec named compression and adds the codec suffix to objectKey,
+// body is returned as is when compression is empty.
+func compressBody(objectKey string, body io.Reader, compression string) (string, io.Reader, Codec, error) {
+	if compression == "" {
+		return objectKey, body, nil, nil
+	}
+
+	codec, err := GetCodec(compression)
+	if err != nil {
+		return objectKey, nil, nil, err
+	}
+
+	if !strings.HasSuffix(objectKey, codec.Ext()) {
+		objectKey += codec.Ext()
+	}
+
+	return objectKey, compressTo(codec, body), codec, nil
+}
+
+// sniffCompression returns the compression of content from its magic bytes, empty if unknown.
+func sniffCompression(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, _gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(content, _zstdMagic):
+		return CompressionZstd
+	default:
+		return ""
+	}
+}
+
+// detectCompression returns the compression of an object, certain is true when it's known
+// from magic bytes or a Content-Encoding without magic bytes (e.g. br), which can be trusted,
+// Content-Type is only a hint. gzip or zstd claimed by metadata without magic bytes are ignored.
+func detectCompression(content []byte, contentEncoding, contentType string) (string, bool) {
+	if name := sniffCompression(content); name != "" {
+		return name, true
+	}
+
+	enc := strings.ToLower(strings.TrimSpace(contentEncoding))
+	if enc != "" && enc != CompressionGzip && enc != CompressionZstd && enc != CompressionNone && enc != "identity" {
+		if _, err := GetCodec(enc); err == nil {
+			return enc, true
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "application/x-brotli", "application/brotli":
+		return CompressionBrotli, false
+	default:
+		return "", false
+	}
+}
+
+// decompress decodes content with codec.
+func decompress(codec Codec, content []byte) ([]byte, error) {
+	reader, err := codec.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+type noneCodec struct{}
+
+func (noneCodec) Name() string { return CompressionNone }
+func (noneCodec) Ext() string  { return "" }
+
+func (noneCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (noneCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return CompressionGzip }
+func (gzipCodec) Ext() string  { return _dotgz }
+
+func (gzipCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) Name() string { return CompressionZstd }
+func (zstdCodec) Ext() string  { return ".zst" }
+
+func (zstdCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.IOReadCloser(), nil
+}
+
+type brotliCodec struct{}
+
+func (brotliCodec) Name() string { return CompressionBrotli }
+func (brotliCodec) Ext() string  { return ".br" }
+
+func (brotliCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return brotli.NewWriter(w), nil
+}
+
+func (brotliCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
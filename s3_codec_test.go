@@ -0,0 +1,55 @@
+package xaws
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CodecSuite struct {
+	suite.Suite
+}
+
+func TestCodec(t *testing.T) {
+	suite.Run(t, new(CodecSuite))
+}
+
+func (s *CodecSuite) compress(name string, raw []byte) []byte {
+	codec, err := GetCodec(name)
+	s.Nil(err)
+
+	var buf bytes.Buffer
+
+	cw, err := codec.NewWriter(&buf)
+	s.Nil(err)
+	_, err = cw.Write(raw)
+	s.Nil(err)
+	s.Nil(cw.Close())
+
+	return buf.Bytes()
+}
+
+func (s *CodecSuite) Test_01_detect() {
+	raw := []byte(`{"k":"v"}`)
+
+	name, certain := detectCompression(s.compress(CompressionGzip, raw), "", "application/json")
+	s.Equal(CompressionGzip, name)
+	s.True(certain)
+
+	name, certain = detectCompression(s.compress(CompressionZstd, raw), "", "")
+	s.Equal(CompressionZstd, name)
+	s.True(certain)
+
+	name, certain = detectCompression(s.compress(CompressionBrotli, raw), "br", "")
+	s.Equal(CompressionBrotli, name)
+	s.True(certain)
+
+	// metadata says gzip, but content isn't.
+	name, _ = detectCompression(raw, "gzip", "application/gzip")
+	s.Equal("", name)
+
+	name, certain = detectCompression(raw, "", "application/x-brotli")
+	s.Equal(CompressionBrotli, name)
+	s.False(certain)
+}
@@ -0,0 +1,224 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// _maxSingleCopySize is the largest object S3 can copy in a single CopyObject call (5 GiB).
+	_maxSingleCopySize int64 = 5 * 1024 * 1024 * 1024
+	// _copyPartSize is the part size used by multipart copy.
+	_copyPartSize int64 = 512 * 1024 * 1024
+)
+
+// CopyObject copies srcKey to dstKey on the server side, no data is downloaded.
+//
+// The source object is always read from w.Bucket, the destination bucket defaults
+// to w.Bucket and can be changed by WithBucket for cross-bucket copies.
+// WithStorageClass sets the storage class of the copy.
+// Objects larger than 5GB are copied with multipart copy, which keeps the content headers,
+// user metadata and tags of the source like a single CopyObject.
+//
+// Usage:
+//
+//	err := w.CopyObject("raw/a.json", "archive/a.json")
+//	err := w.CopyObject("raw/a.json", "raw/a.json", WithBucket("backup-bucket"))
+func (w *S3Client) CopyObject(srcKey, dstKey string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	return w.copyObject(w.Bucket, srcKey, dstKey, opt)
+}
+
+// copyObject copies srcBucket/srcKey to dstKey of opt.bucket.
+func (w *S3Client) copyObject(srcBucket, srcKey, dstKey string, opt *S3Options) error {
+	head, err := w.headSource(srcBucket, srcKey, opt)
+	if err != nil {
+		return err
+	}
+
+	return w.copyObjectOf(srcBucket, srcKey, dstKey, head, opt)
+}
+
+// headSource returns the head of the copy source srcBucket/srcKey.
+func (w *S3Client) headSource(srcBucket, srcKey string, opt *S3Options) (*s3.HeadObjectOutput, error) {
+	var head *s3.HeadObjectOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		head, err = w.api().HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(srcBucket),
+			Key:    aws.String(srcKey),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot head source object %s: %w", srcKey, err)
+	}
+
+	return head, nil
+}
+
+// copyObjectOf copies srcBucket/srcKey, whose head is head, to dstKey of opt.bucket.
+func (w *S3Client) copyObjectOf(srcBucket, srcKey, dstKey string, head *s3.HeadObjectOutput, opt *S3Options) error {
+	size := aws.ToInt64(head.ContentLength)
+	if size > _maxSingleCopySize {
+		return w.multipartCopy(srcBucket, srcKey, dstKey, head, opt)
+	}
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:       aws.String(opt.bucket),
+			Key:          aws.String(dstKey),
+			CopySource:   aws.String(copySource(srcBucket, srcKey)),
+			StorageClass: opt.storageClass,
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy object %s to %s: %w", srcKey, dstKey, err)
+	}
+
+	return nil
+}
+
+// MoveObject copies srcKey to dstKey and deletes srcKey once the copy succeeded.
+// It accepts the same options as CopyObject.
+func (w *S3Client) MoveObject(srcKey, dstKey string, opts ...S3OptionFunc) error {
+	if err := w.CopyObject(srcKey, dstKey, opts...); err != nil {
+		return err
+	}
+
+	return w.DeleteObject(srcKey)
+}
+
+// multipartCopy copies srcBucket/srcKey of head to dstKey of opt.bucket in parts,
+// each call gets the per-call timeout of opt.
+//
+// Unlike CopyObject, CreateMultipartUpload doesn't copy anything from the source,
+// so the content headers and metadata are taken from head, and the tags are read from the source.
+func (w *S3Client) multipartCopy(srcBucket, srcKey, dstKey string, head *s3.HeadObjectOutput, opt *S3Options) error {
+	dstBucket := opt.bucket
+	size := aws.ToInt64(head.ContentLength)
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(dstBucket),
+		Key:                aws.String(dstKey),
+		StorageClass:       opt.storageClass,
+		ContentType:        head.ContentType,
+		ContentEncoding:    head.ContentEncoding,
+		ContentLanguage:    head.ContentLanguage,
+		CacheControl:       head.CacheControl,
+		ContentDisposition: head.ContentDisposition,
+		Metadata:           head.Metadata,
+	}
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		tagging, err := w.api().GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+			Bucket: aws.String(srcBucket),
+			Key:    aws.String(srcKey),
+		}, opt.clientOptions()...)
+		if err != nil {
+			return err
+		}
+
+		if len(tagging.TagSet) != 0 {
+			tags := url.Values{}
+			for _, tag := range tagging.TagSet {
+				tags.Set(aws.ToString(tag.Key), aws.ToString(tag.Value))
+			}
+
+			input.Tagging = aws.String(tags.Encode())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot get tags of source object %s: %w", srcKey, err)
+	}
+
+	var created *s3.CreateMultipartUploadOutput
+
+	err = w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		created, err = w.api().CreateMultipartUpload(ctx, input, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create multipart copy for %s: %w", dstKey, err)
+	}
+
+	var parts []types.CompletedPart
+
+	for start, num := int64(0), int32(1); start < size; start, num = start+_copyPartSize, num+1 {
+		end := min(start+_copyPartSize, size) - 1
+
+		var out *s3.UploadPartCopyOutput
+
+		err := w.do(opt, 0, func(ctx context.Context) error {
+			var err error
+			out, err = w.api().UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+				Bucket:          aws.String(dstBucket),
+				Key:             aws.String(dstKey),
+				UploadId:        created.UploadId,
+				PartNumber:      aws.Int32(num),
+				CopySource:      aws.String(copySource(srcBucket, srcKey)),
+				CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+			}, opt.clientOptions()...)
+
+			return err
+		})
+		if err != nil {
+			_ = w.AbortMultipartUpload(dstKey, aws.ToString(created.UploadId), WithBucket(dstBucket))
+			return fmt.Errorf("failed to copy part %d of %s: %w", num, srcKey, err)
+		}
+
+		parts = append(parts, types.CompletedPart{
+			ETag:       out.CopyPartResult.ETag,
+			PartNumber: aws.Int32(num),
+		})
+	}
+
+	err = w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(dstBucket),
+			Key:             aws.String(dstKey),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		_ = w.AbortMultipartUpload(dstKey, aws.ToString(created.UploadId), WithBucket(dstBucket))
+		return fmt.Errorf("cannot complete multipart copy for %s: %w", dstKey, err)
+	}
+
+	return nil
+}
+
+// copySource builds the url-encoded "bucket/key" value required by CopySource.
+func copySource(bucket, key string) string {
+	return bucket + "/" + escapeKey(key)
+}
+
+// escapeKey url-encodes each segment of key, "/" are kept,
+// "+" is escaped too since S3 may decode it as a space.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, seg := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(seg), "+", "%2B")
+	}
+
+	return strings.Join(segments, "/")
+}
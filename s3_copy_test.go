@@ -0,0 +1,136 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// copySdk serves the head of one source object and records the copy calls,
+// other methods of S3SdkClient are not implemented.
+type copySdk struct {
+	S3SdkClient
+
+	head *s3.HeadObjectOutput
+	tags []types.Tag
+
+	copies  []*s3.CopyObjectInput
+	creates []*s3.CreateMultipartUploadInput
+	parts   []*s3.UploadPartCopyInput
+	done    []*s3.CompleteMultipartUploadInput
+}
+
+func (c *copySdk) HeadObject(_ context.Context, _ *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	return c.head, nil
+}
+
+func (c *copySdk) GetObjectTagging(_ context.Context, _ *s3.GetObjectTaggingInput, _ ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error) {
+	return &s3.GetObjectTaggingOutput{TagSet: c.tags}, nil
+}
+
+func (c *copySdk) CopyObject(_ context.Context, in *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	c.copies = append(c.copies, in)
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (c *copySdk) CreateMultipartUpload(_ context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	c.creates = append(c.creates, in)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+}
+
+func (c *copySdk) UploadPartCopy(_ context.Context, in *s3.UploadPartCopyInput, _ ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error) {
+	c.parts = append(c.parts, in)
+	return &s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: aws.String("etag")}}, nil
+}
+
+func (c *copySdk) CompleteMultipartUpload(_ context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	c.done = append(c.done, in)
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+type CopySuite struct {
+	suite.Suite
+}
+
+func TestCopy(t *testing.T) {
+	suite.Run(t, new(CopySuite))
+}
+
+func (s *CopySuite) Test_01_multipartCopyKeepsMetadata() {
+	sdk := &copySdk{
+		head: &s3.HeadObjectOutput{
+			ContentLength:      aws.Int64(_maxSingleCopySize + 1),
+			ContentType:        aws.String("application/json"),
+			ContentEncoding:    aws.String("gzip"),
+			CacheControl:       aws.String("max-age=60"),
+			ContentDisposition: aws.String("attachment"),
+			Metadata:           map[string]string{"owner": "etl"},
+		},
+		tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("data")}},
+	}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	s.Nil(w.CopyObject("raw/big.json.gz", "archive/big.json.gz", WithStorageClass(types.StorageClassStandardIa)))
+
+	s.Empty(sdk.copies, "objects over 5GB are copied in parts")
+	s.Require().Len(sdk.creates, 1)
+
+	create := sdk.creates[0]
+	s.Equal("archive/big.json.gz", aws.ToString(create.Key))
+	s.Equal(types.StorageClassStandardIa, create.StorageClass)
+	s.Equal("application/json", aws.ToString(create.ContentType))
+	s.Equal("gzip", aws.ToString(create.ContentEncoding))
+	s.Equal("max-age=60", aws.ToString(create.CacheControl))
+	s.Equal("attachment", aws.ToString(create.ContentDisposition))
+	s.Equal(map[string]string{"owner": "etl"}, create.Metadata)
+	s.Equal("team=data", aws.ToString(create.Tagging))
+
+	// 5GB + 1 byte in parts of 512MB.
+	s.Len(sdk.parts, 11)
+	s.Equal("bytes=5368709120-5368709120", aws.ToString(sdk.parts[10].CopySourceRange))
+	s.Require().Len(sdk.done, 1)
+	s.Len(sdk.done[0].MultipartUpload.Parts, 11)
+}
+
+func (s *CopySuite) Test_02_singleCopy() {
+	sdk := &copySdk{head: &s3.HeadObjectOutput{ContentLength: aws.Int64(10)}}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	s.Nil(w.CopyObject("raw/a b.json", "archive/a.json"))
+	s.Empty(sdk.creates)
+	s.Require().Len(sdk.copies, 1)
+	s.Equal("bucket/raw/a%20b.json", aws.ToString(sdk.copies[0].CopySource))
+}
+
+func (s *CopySuite) Test_03_changeStorageClass() {
+	sdk := &copySdk{head: &s3.HeadObjectOutput{ContentLength: aws.Int64(10)}}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	// STANDARD objects have no storage class in their head.
+	s.Nil(w.ChangeStorageClass("a.json", types.StorageClassStandard))
+	s.Empty(sdk.copies)
+
+	sdk.head.StorageClass = types.StorageClassGlacierIr
+	s.Nil(w.ChangeStorageClass("a.json", types.StorageClassGlacierIr))
+	s.Empty(sdk.copies)
+
+	s.Nil(w.ChangeStorageClass("a.json", types.StorageClassStandardIa))
+	s.Require().Len(sdk.copies, 1)
+	s.Equal(types.StorageClassStandardIa, sdk.copies[0].StorageClass)
+
+	// a large object keeps its metadata when moved.
+	sdk.head = &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(_maxSingleCopySize + 1),
+		ContentType:   aws.String("application/json"),
+		Metadata:      map[string]string{"owner": "etl"},
+	}
+	s.Nil(w.ChangeStorageClass("big.json", types.StorageClassGlacierIr))
+	s.Require().Len(sdk.creates, 1)
+	s.Equal(types.StorageClassGlacierIr, sdk.creates[0].StorageClass)
+	s.Equal("application/json", aws.ToString(sdk.creates[0].ContentType))
+	s.Equal(map[string]string{"owner": "etl"}, sdk.creates[0].Metadata)
+}
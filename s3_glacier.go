@@ -0,0 +1,190 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+const _restorePollInterval = time.Minute
+
+var ErrRestoreNotRequested = errors.New("restore not requested")
+
+var (
+	_restoreOngoingRe = regexp.MustCompile(`ongoing-request="(\w+)"`)
+	_restoreExpiryRe  = regexp.MustCompile(`expiry-date="([^"]+)"`)
+)
+
+// RestoreStatus is the archive state of an object.
+type RestoreStatus struct {
+	StorageClass types.StorageClass
+	// Requested is true once RestoreObject has been called, until the restored copy expires.
+	Requested bool
+	// Ongoing is true while the restore is in progress.
+	Ongoing bool
+	// ExpiresAt is when the restored copy is removed, zero while ongoing.
+	ExpiresAt time.Time
+}
+
+// Archived reports whether the object is in an archive class and must be restored before it can be read.
+func (s *RestoreStatus) Archived() bool {
+	return s.StorageClass == types.StorageClassGlacier || s.StorageClass == types.StorageClassDeepArchive
+}
+
+// Restored reports whether a temporary copy of an archived object is readable.
+func (s *RestoreStatus) Restored() bool {
+	return s.Requested && !s.Ongoing
+}
+
+// RestoreObject requests a temporary copy of an archived object, readable for days once restored.
+// tier is one of types.TierStandard, types.TierBulk or types.TierExpedited (not for Deep Archive),
+// a restore already in progress is not an error.
+//
+// Usage:
+//
+//	err := w.RestoreObject("archive/2023.jsonl.gz", 7, types.TierBulk)
+//	status, err := w.WaitForRestore("archive/2023.jsonl.gz", 48*time.Hour)
+func (w *S3Client) RestoreObject(objectKey string, days int32, tier types.Tier, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().RestoreObject(ctx, &s3.RestoreObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+			RestoreRequest: &types.RestoreRequest{
+				Days: aws.Int32(days),
+				GlacierJobParameters: &types.GlacierJobParameters{
+					Tier: tier,
+				},
+			},
+		}, opt.clientOptions()...)
+
+		return err
+	})
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress" {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot restore object %s: %w", objectKey, err)
+	}
+
+	return nil
+}
+
+// GetRestoreStatus returns the storage class and restore state of objectKey.
+func (w *S3Client) GetRestoreStatus(objectKey string, opts ...S3OptionFunc) (*RestoreStatus, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var head *s3.HeadObjectOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		head, err = w.api().HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRestoreStatus(head.StorageClass, aws.ToString(head.Restore)), nil
+}
+
+// parseRestoreStatus parses the x-amz-restore header,
+// e.g. `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`.
+func parseRestoreStatus(class types.StorageClass, header string) *RestoreStatus {
+	status := &RestoreStatus{StorageClass: class}
+
+	m := _restoreOngoingRe.FindStringSubmatch(header)
+	if m == nil {
+		return status
+	}
+
+	status.Requested = true
+	status.Ongoing = m[1] == "true"
+
+	if m := _restoreExpiryRe.FindStringSubmatch(header); m != nil {
+		if t, err := http.ParseTime(m[1]); err == nil {
+			status.ExpiresAt = t
+		}
+	}
+
+	return status
+}
+
+// WaitForRestore polls objectKey every minute until its restore finished,
+// returns ErrRestoreNotRequested if the object is archived and RestoreObject was not called.
+// Objects which are not archived are returned immediately.
+func (w *S3Client) WaitForRestore(objectKey string, timeout time.Duration, opts ...S3OptionFunc) (*RestoreStatus, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := w.GetRestoreStatus(objectKey, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if !status.Archived() || status.Restored() {
+			return status, nil
+		}
+
+		if !status.Requested {
+			return status, fmt.Errorf("%w: %s", ErrRestoreNotRequested, objectKey)
+		}
+
+		if time.Now().After(deadline) {
+			return status, fmt.Errorf("object %s not restored in %s", objectKey, timeout)
+		}
+
+		w.log().Debug("waiting for restore", "key", objectKey, "class", status.StorageClass)
+		time.Sleep(_restorePollInterval)
+	}
+}
+
+// ChangeStorageClass moves objectKey to class by copying it onto itself, metadata and tags are kept.
+// An object already in class is not copied, S3 rejects such a copy.
+// An archived object must be restored before it can be moved out of Glacier.
+//
+// Usage:
+//
+//	err := w.ChangeStorageClass("archive/2023.jsonl.gz", types.StorageClassStandard)
+func (w *S3Client) ChangeStorageClass(objectKey string, class types.StorageClass, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	opt.storageClass = class
+
+	head, err := w.headSource(opt.bucket, objectKey, opt)
+	if err != nil {
+		return err
+	}
+
+	// HeadObject omits the storage class of STANDARD objects.
+	current := head.StorageClass
+	if current == "" {
+		current = types.StorageClassStandard
+	}
+
+	if current == class {
+		w.log().Debug("storage class unchanged", "key", objectKey, "class", class)
+		return nil
+	}
+
+	return w.copyObjectOf(opt.bucket, objectKey, objectKey, head, opt)
+}
@@ -0,0 +1,126 @@
+package xaws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PartitionBy is the granularity of the Hive-style date partition added by KeyBuilder.
+type PartitionBy int
+
+const (
+	PartitionNone PartitionBy = iota
+	PartitionByYear
+	PartitionByMonth
+	PartitionByDay
+	PartitionByHour
+)
+
+// KeyBuilder lays out object keys as prefix/partition/hash/name, e.g.
+// "raw/year=2025/month=06/day=01/3f2a/item.json", the same name and time always give the same key.
+//
+// Usage:
+//
+//	kb := NewKeyBuilder("raw").Partition(PartitionByDay).HashPrefix(4)
+//	key := kb.Build("item 1.json") // raw/year=2025/month=06/day=01/<hash>/item_1.json
+//	err := w.PutObject("item 1.json", raw, WithKeyTemplate(kb))
+type KeyBuilder struct {
+	prefix    string
+	partition PartitionBy
+	hashLen   int
+	now       func() time.Time
+}
+
+func NewKeyBuilder(prefix string) *KeyBuilder {
+	return &KeyBuilder{prefix: prefix}
+}
+
+// Partition adds year=/month=/day=/hour= folders of the build time in UTC down to granularity p.
+func (b *KeyBuilder) Partition(p PartitionBy) *KeyBuilder {
+	b.partition = p
+	return b
+}
+
+// HashPrefix adds a folder of the first n hex chars of the sha256 of name,
+// to spread keys over S3 partitions, n is capped at 64.
+func (b *KeyBuilder) HashPrefix(n int) *KeyBuilder {
+	b.hashLen = min(n, sha256.Size*2) //nolint:mnd
+	return b
+}
+
+// Clock sets the time source of partitions, time.Now by default.
+func (b *KeyBuilder) Clock(now func() time.Time) *KeyBuilder {
+	b.now = now
+	return b
+}
+
+// Build returns the key of name at the current time.
+func (b *KeyBuilder) Build(name string) string {
+	now := time.Now
+	if b.now != nil {
+		now = b.now
+	}
+
+	return b.BuildAt(name, now())
+}
+
+// BuildAt returns the key of name partitioned by t.
+func (b *KeyBuilder) BuildAt(name string, t time.Time) string {
+	var parts []string
+
+	if p := SanitizeKey(b.prefix); p != "" {
+		parts = append(parts, p)
+	}
+
+	parts = append(parts, datePartition(b.partition, t.UTC())...)
+
+	name = SanitizeKey(name)
+
+	if b.hashLen > 0 {
+		sum := sha256.Sum256([]byte(name))
+		parts = append(parts, hex.EncodeToString(sum[:])[:b.hashLen])
+	}
+
+	return strings.Join(append(parts, name), "/")
+}
+
+func datePartition(p PartitionBy, t time.Time) []string {
+	all := []string{
+		fmt.Sprintf("year=%04d", t.Year()),
+		fmt.Sprintf("month=%02d", t.Month()),
+		fmt.Sprintf("day=%02d", t.Day()),
+		fmt.Sprintf("hour=%02d", t.Hour()),
+	}
+
+	return all[:min(int(p), len(all))]
+}
+
+// SanitizeKey replaces characters outside the S3 safe set (letters, digits and !-_.*'()) with "_",
+// "/" is kept as separator but empty, "." and ".." segments are removed.
+func SanitizeKey(key string) string {
+	var segments []string
+
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			continue
+		}
+
+		segments = append(segments, strings.Map(safeKeyRune, seg))
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func safeKeyRune(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return r
+	case strings.ContainsRune("!-_.*'()", r):
+		return r
+	default:
+		return '_'
+	}
+}
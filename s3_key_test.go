@@ -0,0 +1,38 @@
+package xaws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KeySuite struct {
+	suite.Suite
+}
+
+func TestKey(t *testing.T) {
+	suite.Run(t, new(KeySuite))
+}
+
+func (s *KeySuite) Test_01_sanitize() {
+	s.Equal("a/b_c/d.json", SanitizeKey("/a//b c/./d.json"))
+	s.Equal("x/y", SanitizeKey("../x/../y"))
+	s.Equal("r_sum__(1)_.txt", SanitizeKey("résumé (1)?.txt"))
+}
+
+func (s *KeySuite) Test_02_build() {
+	at := time.Date(2025, 6, 1, 13, 4, 5, 0, time.FixedZone("CST", 8*3600))
+
+	kb := NewKeyBuilder("raw").Partition(PartitionByDay)
+	s.Equal("raw/year=2025/month=06/day=01/item.json", kb.BuildAt("item.json", at))
+
+	kb.Partition(PartitionByHour)
+	s.Equal("raw/year=2025/month=06/day=01/hour=05/item.json", kb.BuildAt("item.json", at))
+
+	kb = NewKeyBuilder("").HashPrefix(4).Clock(func() time.Time { return at })
+	key := kb.Build("item 1.json")
+	s.Len(key, len("abcd/item_1.json"))
+	s.Equal(key, kb.Build("item 1.json"))
+	s.NotEqual(key[:4], kb.Build("item 2.json")[:4])
+}
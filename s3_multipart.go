@@ -0,0 +1,218 @@
+package xaws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CreateMultipartUpload starts a multipart upload of objectKey and returns its upload id.
+// The upload options (WithContentType, WithMetadata, ...) and WithBucket are honored.
+//
+// Usage:
+//
+//	id, _ := w.CreateMultipartUpload(key)
+//	p1, _ := w.UploadPart(key, id, 1, part1)
+//	p2, _ := w.UploadPart(key, id, 2, part2)
+//	err := w.CompleteMultipartUpload(key, id, []types.CompletedPart{p1, p2})
+func (w *S3Client) CreateMultipartUpload(objectKey string, opts ...S3OptionFunc) (string, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	put := &s3.PutObjectInput{Key: aws.String(objectKey)}
+	applyUploadOptions(put, opt)
+
+	var output *s3.CreateMultipartUploadOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		output, err = w.api().CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:       aws.String(opt.bucket),
+			Key:          aws.String(objectKey),
+			ContentType:  put.ContentType,
+			Metadata:     put.Metadata,
+			Tagging:      put.Tagging,
+			StorageClass: put.StorageClass,
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot create multipart upload for %s: %w", objectKey, err)
+	}
+
+	return aws.ToString(output.UploadId), nil
+}
+
+// UploadPart uploads part number partNumber (1-10000) of a multipart upload,
+// all parts except the last one must be at least 5MB.
+//
+// r is buffered in memory if it is not an io.ReadSeeker.
+func (w *S3Client) UploadPart(objectKey, uploadID string, partNumber int32, r io.Reader, opts ...S3OptionFunc) (types.CompletedPart, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	body, ok := r.(io.ReadSeeker)
+	if !ok {
+		raw, err := io.ReadAll(r)
+		if err != nil {
+			return types.CompletedPart{}, err
+		}
+
+		body = bytes.NewReader(raw)
+	}
+
+	start, err := body.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return types.CompletedPart{}, err
+	}
+
+	var output *s3.UploadPartOutput
+
+	err = w.do(opt, 0, func(ctx context.Context) error {
+		if _, err := body.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+
+		var err error
+		output, err = w.api().UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(opt.bucket),
+			Key:        aws.String(objectKey),
+			UploadId:   aws.String(uploadID),
+			PartNumber: aws.Int32(partNumber),
+			Body:       body,
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return types.CompletedPart{}, fmt.Errorf("failed to upload part %d of %s: %w", partNumber, objectKey, err)
+	}
+
+	return types.CompletedPart{ETag: output.ETag, PartNumber: aws.Int32(partNumber)}, nil
+}
+
+// CompleteMultipartUpload assembles parts into the final object, parts can be in any order.
+func (w *S3Client) CompleteMultipartUpload(objectKey, uploadID string, parts []types.CompletedPart, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	sorted := append([]types.CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return aws.ToInt32(sorted[i].PartNumber) < aws.ToInt32(sorted[j].PartNumber)
+	})
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(opt.bucket),
+			Key:             aws.String(objectKey),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: sorted},
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot complete multipart upload for %s: %w", objectKey, err)
+	}
+
+	return nil
+}
+
+// AbortMultipartUpload aborts a multipart upload and frees its uploaded parts.
+func (w *S3Client) AbortMultipartUpload(objectKey, uploadID string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(opt.bucket),
+			Key:      aws.String(objectKey),
+			UploadId: aws.String(uploadID),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot abort multipart upload %s of %s: %w", uploadID, objectKey, err)
+	}
+
+	return nil
+}
+
+// ListIncompleteUploads lists multipart uploads with prefix that are neither completed nor aborted.
+func (w *S3Client) ListIncompleteUploads(prefix string, opts ...S3OptionFunc) ([]types.MultipartUpload, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var (
+		uploads []types.MultipartUpload
+
+		keyMarker      *string
+		uploadIDMarker *string
+	)
+
+	for {
+		var resp *s3.ListMultipartUploadsOutput
+
+		err := w.do(opt, 0, func(ctx context.Context) error {
+			var err error
+			resp, err = w.api().ListMultipartUploads(ctx, &s3.ListMultipartUploadsInput{
+				Bucket:         aws.String(opt.bucket),
+				Prefix:         aws.String(prefix),
+				KeyMarker:      keyMarker,
+				UploadIdMarker: uploadIDMarker,
+			}, opt.clientOptions()...)
+
+			return err
+		})
+		if err != nil {
+			return uploads, err
+		}
+
+		uploads = append(uploads, resp.Uploads...)
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+
+		keyMarker, uploadIDMarker = resp.NextKeyMarker, resp.NextUploadIdMarker
+	}
+
+	return uploads, nil
+}
+
+// AbortStaleUploads aborts incomplete multipart uploads initiated more than olderThan ago,
+// and returns how many were aborted.
+func (w *S3Client) AbortStaleUploads(olderThan time.Duration, opts ...S3OptionFunc) (int, error) {
+	uploads, err := w.ListIncompleteUploads("", opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	aborted := 0
+	deadline := time.Now().Add(-olderThan)
+
+	for _, up := range uploads {
+		if up.Initiated == nil || up.Initiated.After(deadline) {
+			continue
+		}
+
+		if err := w.AbortMultipartUpload(aws.ToString(up.Key), aws.ToString(up.UploadId), opts...); err != nil {
+			return aborted, err
+		}
+
+		w.log().Debug("aborted stale upload", "key", aws.ToString(up.Key), "initiated", *up.Initiated)
+
+		aborted++
+	}
+
+	return aborted, nil
+}
@@ -1,8 +1,18 @@
 package xaws
 
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
 type S3Options struct {
 	saveTo  string
 	timeout int
+	logger  Logger
 
 	folderLevel int
 	savedName   string
@@ -14,6 +24,31 @@ type S3Options struct {
 
 	withEmptyFile bool
 	maxKeys       int
+
+	contentType  string
+	metadata     map[string]string
+	tags         map[string]string
+	storageClass types.StorageClass
+
+	progress ProgressFunc
+
+	compression string
+
+	ifModifiedSince time.Time
+
+	contentLength int64
+
+	retryAttempts uint
+	retryBackoff  time.Duration
+
+	requestPayer bool
+	accelerate   bool
+
+	keyBuilder *KeyBuilder
+
+	concurrency int
+
+	clientOpts []ClientOptFunc
 }
 
 type S3OptionFunc func(o *S3Options)
@@ -24,6 +59,46 @@ func bindS3Options(opt *S3Options, opts ...S3OptionFunc) {
 	}
 }
 
+// clientOptions returns the per-operation s3 client options of WithRequestPayer, WithTransferAcceleration and WithRetry.
+func (o *S3Options) clientOptions() []func(*s3.Options) {
+	var fns []func(*s3.Options)
+
+	if o.retryAttempts > 0 {
+		policy := RetryPolicy{MaxAttempts: int(o.retryAttempts), BaseDelay: o.retryBackoff, Retryable: IsRetryableError}
+		fns = append(fns, func(so *s3.Options) {
+			so.Retryer = policy.NewRetryer()
+		})
+	}
+
+	if o.requestPayer {
+		fns = append(fns, func(so *s3.Options) {
+			so.APIOptions = append(so.APIOptions, smithyhttp.SetHeaderValue("x-amz-request-payer", string(types.RequestPayerRequester)))
+		})
+	}
+
+	if o.accelerate {
+		fns = append(fns, func(so *s3.Options) {
+			so.UseAccelerate = true
+		})
+	}
+
+	return fns
+}
+
+// buildKey returns objectKey laid out by WithKeyTemplate, or as is when not set.
+func (o *S3Options) buildKey(objectKey string) string {
+	if o.keyBuilder == nil {
+		return objectKey
+	}
+
+	return o.keyBuilder.Build(objectKey)
+}
+
+// uploaderOptions applies clientOptions to the requests of an upload manager.
+func (o *S3Options) uploaderOptions(u *manager.Uploader) {
+	u.ClientOptions = append(u.ClientOptions, o.clientOptions()...)
+}
+
 func WithGz(b bool) S3OptionFunc {
 	return func(o *S3Options) {
 		o.withGz = b
@@ -42,6 +117,8 @@ func WithMaxKeys(n int) S3OptionFunc {
 	}
 }
 
+// WithTimeout sets the timeout in seconds, when passed to NewS3Wrapper it is the default
+// timeout of uploads, when passed to a method it is the timeout of each attempt of that call.
 func WithTimeout(n int) S3OptionFunc {
 	return func(o *S3Options) {
 		o.timeout = n
@@ -57,6 +134,7 @@ func WithSaveTo(s string) S3OptionFunc {
 	}
 }
 
+// WithBucket overrides the bucket of a single call, so one S3Client can serve multiple buckets.
 func WithBucket(s string) S3OptionFunc {
 	return func(o *S3Options) {
 		o.bucket = s
@@ -76,8 +154,127 @@ func WithSavedName(s string) S3OptionFunc {
 	}
 }
 
+// WithAutoUnGzip makes GetObject decompress content whose compression is detected
+// from magic bytes (gzip, zstd), Content-Encoding or Content-Type.
 func WithAutoUnGzip(autoUnGzip bool) S3OptionFunc {
 	return func(o *S3Options) {
 		o.autoUnGzip = autoUnGzip
 	}
 }
+
+// WithContentType sets Content-Type of uploaded object,
+// by default it is detected from the extension of object key.
+func WithContentType(s string) S3OptionFunc {
+	return func(o *S3Options) {
+		o.contentType = s
+	}
+}
+
+// WithMetadata sets user-defined metadata (x-amz-meta-*) of uploaded object.
+func WithMetadata(m map[string]string) S3OptionFunc {
+	return func(o *S3Options) {
+		o.metadata = m
+	}
+}
+
+// WithTags sets object tags of uploaded object.
+func WithTags(m map[string]string) S3OptionFunc {
+	return func(o *S3Options) {
+		o.tags = m
+	}
+}
+
+// WithStorageClass sets storage class of uploaded object, e.g. types.StorageClassStandardIa.
+func WithStorageClass(sc types.StorageClass) S3OptionFunc {
+	return func(o *S3Options) {
+		o.storageClass = sc
+	}
+}
+
+// WithProgress reports transferred bytes of uploads and downloads to fn.
+func WithProgress(fn ProgressFunc) S3OptionFunc {
+	return func(o *S3Options) {
+		o.progress = fn
+	}
+}
+
+// WithCompression sets the codec used to compress uploads and decompress GetObject content,
+// available: CompressionGzip, CompressionZstd, CompressionBrotli, CompressionNone
+// or any name added by RegisterCodec.
+func WithCompression(name string) S3OptionFunc {
+	return func(o *S3Options) {
+		o.compression = name
+	}
+}
+
+// WithIfModifiedSince only downloads object modified after t, used by GetObjectIfChanged.
+func WithIfModifiedSince(t time.Time) S3OptionFunc {
+	return func(o *S3Options) {
+		o.ifModifiedSince = t
+	}
+}
+
+// WithContentLength sets the size of a stream uploaded by UploadStream, if known.
+func WithContentLength(n int64) S3OptionFunc {
+	return func(o *S3Options) {
+		o.contentLength = n
+	}
+}
+
+// WithRetry makes the sdk retryer retry throttling and 5xx errors up to attempts times in total,
+// with exponential backoff starting from backoff, see RetryPolicy.
+// When passed to NewS3Wrapper it applies to every call of the wrapper.
+func WithRetry(attempts uint, backoff time.Duration) S3OptionFunc {
+	return func(o *S3Options) {
+		o.retryAttempts = attempts
+		o.retryBackoff = backoff
+	}
+}
+
+// WithRequestPayer sends x-amz-request-payer=requester, required to read requester-pays buckets,
+// the requester (the caller) is then charged for requests and data transfer.
+// When passed to NewS3Wrapper it applies to every call of the wrapper.
+func WithRequestPayer(b bool) S3OptionFunc {
+	return func(o *S3Options) {
+		o.requestPayer = b
+	}
+}
+
+// WithTransferAcceleration sends requests to the s3-accelerate endpoint,
+// acceleration must be enabled on the bucket, and bucket names with dots are not supported.
+// When passed to NewS3Wrapper it applies to every call of the wrapper.
+func WithTransferAcceleration(b bool) S3OptionFunc {
+	return func(o *S3Options) {
+		o.accelerate = b
+	}
+}
+
+// WithKeyTemplate lays out the object key of uploads with kb,
+// the key passed to the upload method is used as the name, see KeyBuilder.
+func WithKeyTemplate(kb *KeyBuilder) S3OptionFunc {
+	return func(o *S3Options) {
+		o.keyBuilder = kb
+	}
+}
+
+// WithConcurrency sets the number of parallel requests of HasObjects.
+func WithConcurrency(n int) S3OptionFunc {
+	return func(o *S3Options) {
+		o.concurrency = n
+	}
+}
+
+// WithLogger sets the logger of the wrapper, it only works with constructors.
+func WithLogger(l Logger) S3OptionFunc {
+	return func(o *S3Options) {
+		o.logger = l
+	}
+}
+
+// WithClientOpts applies the ClientOptFunc of other wrappers to the s3 client, e.g. WithTelemetry or WithRetryPolicy,
+// it only works with NewS3Wrapper and NewS3WrapperWithDefaultConfig.
+func WithClientOpts(opts ...ClientOptFunc) S3OptionFunc {
+	return func(o *S3Options) {
+		o.clientOpts = append(o.clientOpts, opts...)
+	}
+}
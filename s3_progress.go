@@ -0,0 +1,33 @@
+package xaws
+
+import "io"
+
+// ProgressFunc is called with the bytes transferred so far and the total size,
+// total is -1 when the size is unknown.
+type ProgressFunc func(transferred, total int64)
+
+// progressReader calls fn after each Read of the wrapped reader.
+type progressReader struct {
+	r           io.Reader
+	fn          ProgressFunc
+	total       int64
+	transferred int64
+}
+
+func newProgressReader(r io.Reader, total int64, fn ProgressFunc) io.Reader {
+	if fn == nil {
+		return r
+	}
+
+	return &progressReader{r: r, fn: fn, total: total}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		p.fn(p.transferred, p.total)
+	}
+
+	return n, err
+}
@@ -0,0 +1,77 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectInfo is the metadata of an object returned by StatObject.
+type ObjectInfo struct {
+	Key  string
+	Size int64
+	// ETag is unquoted, it is the md5 of content only for objects not uploaded in parts.
+	ETag            string
+	ContentType     string
+	ContentEncoding string
+	LastModified    time.Time
+	// Metadata is the user-defined metadata (x-amz-meta-*), keys are lower-cased by S3.
+	Metadata map[string]string
+	// StorageClass is empty for STANDARD objects.
+	StorageClass types.StorageClass
+	VersionID    string
+}
+
+// StatObject returns the metadata of objectKey without downloading it,
+// ErrObjectNotFound is returned if it doesn't exist.
+//
+// Usage:
+//
+//	info, err := w.StatObject("raw/a.json")
+//	if errors.Is(err, ErrObjectNotFound) {
+//		...
+//	}
+func (w *S3Client) StatObject(objectKey string, opts ...S3OptionFunc) (*ObjectInfo, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var head *s3.HeadObjectOutput
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		var err error
+		head, err = w.api().HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(opt.bucket),
+			Key:    aws.String(objectKey),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, objectKey)
+		}
+
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:             objectKey,
+		Size:            aws.ToInt64(head.ContentLength),
+		ETag:            strings.Trim(aws.ToString(head.ETag), `"`),
+		ContentType:     aws.ToString(head.ContentType),
+		ContentEncoding: aws.ToString(head.ContentEncoding),
+		LastModified:    aws.ToTime(head.LastModified),
+		Metadata:        head.Metadata,
+		StorageClass:    head.StorageClass,
+		VersionID:       aws.ToString(head.VersionId),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package xaws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// _sizeBucketBounds are the exclusive upper bounds of the PrefixStats histogram,
+// objects of 5GiB and larger fall in a last unbounded bucket.
+var _sizeBucketBounds = []int64{
+	1 << 10,   // 1KiB
+	1 << 20,   // 1MiB
+	16 << 20,  // 16MiB
+	128 << 20, // 128MiB
+	1 << 30,   // 1GiB
+	5 << 30,   // 5GiB
+}
+
+// SizeBucket is one bucket of the PrefixStats size histogram.
+type SizeBucket struct {
+	// Max is the exclusive upper bound in bytes, 0 for the last unbounded bucket.
+	Max   int64
+	Count int64
+	Bytes int64
+}
+
+// PrefixStats summarizes all objects under a prefix.
+type PrefixStats struct {
+	Prefix string
+	Count  int64
+	Bytes  int64
+	// Histogram counts objects by size, buckets are ordered by Max, empty ones included.
+	Histogram []SizeBucket
+
+	Oldest    time.Time
+	OldestKey string
+	Newest    time.Time
+	NewestKey string
+}
+
+func newPrefixStats(prefix string) *PrefixStats {
+	stats := &PrefixStats{Prefix: prefix}
+
+	for _, bound := range _sizeBucketBounds {
+		stats.Histogram = append(stats.Histogram, SizeBucket{Max: bound})
+	}
+
+	stats.Histogram = append(stats.Histogram, SizeBucket{})
+
+	return stats
+}
+
+func (s *PrefixStats) add(key string, size int64, modified time.Time) {
+	s.Count++
+	s.Bytes += size
+
+	i := len(s.Histogram) - 1
+	for j, b := range s.Histogram[:i] {
+		if size < b.Max {
+			i = j
+			break
+		}
+	}
+
+	s.Histogram[i].Count++
+	s.Histogram[i].Bytes += size
+
+	if s.OldestKey == "" || modified.Before(s.Oldest) {
+		s.Oldest, s.OldestKey = modified, key
+	}
+
+	if s.NewestKey == "" || modified.After(s.Newest) {
+		s.Newest, s.NewestKey = modified, key
+	}
+}
+
+// PrefixStats pages through all objects under prefix and returns their count, total size,
+// size histogram and the oldest/newest LastModified, empty objects are included.
+//
+// Usage:
+//
+//	stats, err := w.PrefixStats("raw/2024/")
+//	fmt.Println(stats.Count, stats.Bytes, stats.Newest)
+func (w *S3Client) PrefixStats(prefix string, opts ...S3OptionFunc) (*PrefixStats, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	stats := newPrefixStats(prefix)
+
+	var nextToken *string
+
+	for {
+		input := &s3.ListObjectsV2Input{
+			Bucket:            aws.String(opt.bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: nextToken,
+		}
+
+		var resp *s3.ListObjectsV2Output
+
+		err := w.do(opt, 0, func(ctx context.Context) error {
+			var err error
+			resp, err = w.api().ListObjectsV2(ctx, input, opt.clientOptions()...)
+
+			return err
+		})
+		if err != nil {
+			return stats, err
+		}
+
+		for _, item := range resp.Contents {
+			stats.add(aws.ToString(item.Key), aws.ToInt64(item.Size), aws.ToTime(item.LastModified))
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+
+		nextToken = resp.NextContinuationToken
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,149 @@
+package xaws
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// UploadStream uploads everything read from r to objectKey without holding it in memory,
+// only the parts being uploaded are buffered by the upload manager.
+//
+// Available Options:
+//   - WithCompression(name): compress r on the fly, the codec suffix is added to objectKey.
+//   - WithContentLength(n): the size of r if known, used to pick a part size that fits
+//     S3's 10000 parts limit and as total of WithProgress.
+//   - all upload options of UploadRawData.
+//
+// Usage:
+//
+//	f, _ := os.Open("huge.jsonl")
+//	defer f.Close()
+//	out, err := w.UploadStream("data/huge.jsonl", f, WithCompression(CompressionZstd))
+func (w *S3Client) UploadStream(objectKey string, r io.Reader, opts ...S3OptionFunc) (*manager.UploadOutput, error) {
+	opt := &S3Options{bucket: w.Bucket, contentLength: -1}
+	bindS3Options(opt, opts...)
+
+	objectKey = opt.buildKey(objectKey)
+
+	body := newProgressReader(r, opt.contentLength, opt.progress)
+
+	objectKey, body, codec, err := compressBody(objectKey, body, opt.compression)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(opt.bucket),
+		Key:    aws.String(objectKey),
+		Body:   body,
+	}
+	applyUploadOptions(input, opt)
+	applyContentEncoding(input, codec)
+
+	up := manager.NewUploader(w.api(), opt.uploaderOptions, func(u *manager.Uploader) {
+		if opt.contentLength > 0 {
+			u.PartSize = max(manager.DefaultUploadPartSize, opt.contentLength/int64(manager.MaxUploadParts)+1)
+		}
+	})
+
+	// a stream cannot be read twice, so only the timeout of WithTimeout is honored, not WithRetry.
+	ctx, cancelFn := callContext(opt, 0)
+	defer cancelFn()
+
+	return up.Upload(ctx, input)
+}
+
+// S3Writer is an io.WriteCloser whose written bytes are streamed to an S3 object,
+// the object is only complete after Close returned nil.
+//
+// It can be handed to any encoder writing to io.Writer, e.g. a parquet writer:
+//
+//	sw := w.NewWriter("data/rows.parquet")
+//	pw := parquet.NewGenericWriter[Row](sw)
+//	_, _ = pw.Write(rows)
+//	_ = pw.Close()
+//	err := sw.Close()
+type S3Writer struct {
+	pw   *io.PipeWriter
+	done chan struct{}
+
+	output *manager.UploadOutput
+	err    error
+}
+
+// NewWriter starts an UploadStream to objectKey fed by the returned writer,
+// it accepts the same options as UploadStream.
+func (w *S3Client) NewWriter(objectKey string, opts ...S3OptionFunc) *S3Writer {
+	pr, pw := io.Pipe()
+	sw := &S3Writer{pw: pw, done: make(chan struct{})}
+
+	go func() {
+		defer close(sw.done)
+
+		sw.output, sw.err = w.UploadStream(objectKey, pr, opts...)
+		// unblock writers if upload failed before reading everything.
+		pr.CloseWithError(sw.err)
+	}()
+
+	return sw
+}
+
+func (sw *S3Writer) Write(p []byte) (int, error) {
+	return sw.pw.Write(p)
+}
+
+// Close flushes the remaining data and waits for the upload to finish.
+func (sw *S3Writer) Close() error {
+	_ = sw.pw.Close()
+	<-sw.done
+
+	return sw.err
+}
+
+// Abort stops the upload, nothing is written to S3.
+func (sw *S3Writer) Abort(err error) error {
+	_ = sw.pw.CloseWithError(err)
+	<-sw.done
+
+	return sw.err
+}
+
+// Output returns the upload result, only available after Close returned nil.
+func (sw *S3Writer) Output() *manager.UploadOutput {
+	return sw.output
+}
+
+// JSONLWriter streams values as JSON lines into an S3 object, gzipped by default.
+// It is an io.WriteCloser like S3Writer, WriteRecord encodes a value as one line.
+//
+// Usage:
+//
+//	jw := w.NewJSONLWriter("data/items.jsonl") // written to data/items.jsonl.gz
+//	for _, item := range items {
+//		if err := jw.WriteRecord(item); err != nil {
+//			return err
+//		}
+//	}
+//	err := jw.Close()
+type JSONLWriter struct {
+	*S3Writer
+	enc *json.Encoder
+}
+
+// NewJSONLWriter starts an UploadStream to objectKey, gzipped unless changed by WithCompression,
+// e.g. WithCompression(CompressionNone) for plain lines.
+func (w *S3Client) NewJSONLWriter(objectKey string, opts ...S3OptionFunc) *JSONLWriter {
+	opts = append([]S3OptionFunc{WithContentType("application/x-ndjson"), WithCompression(CompressionGzip)}, opts...)
+	sw := w.NewWriter(objectKey, opts...)
+
+	return &JSONLWriter{S3Writer: sw, enc: json.NewEncoder(sw)}
+}
+
+// WriteRecord encodes v as a single JSON line.
+func (jw *JSONLWriter) WriteRecord(v any) error {
+	return jw.enc.Encode(v)
+}
@@ -1,7 +1,10 @@
 package xaws
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/joho/godotenv"
 	"github.com/k0kubun/pp/v3"
 	"github.com/stretchr/testify/suite"
@@ -353,3 +358,242 @@ func (s *S3Suite) TestDeleteObj() {
 	s.Nil(err)
 	pp.Println(len(objects))
 }
+
+func (s *S3Suite) TestCopyAndMoveObject() {
+	s.T().Parallel()
+	testPrefix := fmt.Sprintf("%scopy-test-%s/", s.testPrefix, s.T().Name())
+	src, copied, moved := testPrefix+"src.txt", testPrefix+"copied.txt", testPrefix+"moved.txt"
+	testContent := []byte("This is a test content for server side copy.")
+
+	err := s.wrapper.UploadRawData(src, testContent)
+	s.Require().NoError(err, "Failed to upload source object")
+
+	err = s.wrapper.CopyObject(src, copied)
+	s.Require().NoError(err, "Failed to copy object")
+
+	content, err := s.wrapper.GetObject(copied)
+	s.Require().NoError(err, "Failed to get copied object")
+	s.Equal(testContent, content, "Copied content doesn't match source content")
+
+	err = s.wrapper.MoveObject(copied, moved)
+	s.Require().NoError(err, "Failed to move object")
+
+	exists, err := s.wrapper.HasObject(copied)
+	s.Require().NoError(err)
+	s.False(exists, "Moved source object should not exist")
+
+	exists, err = s.wrapper.HasObject(moved)
+	s.Require().NoError(err)
+	s.True(exists, "Moved object should exist")
+}
+
+func (s *S3Suite) TestUploadWithMetadata() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%smetadata-test-%s.json", s.testPrefix, s.T().Name())
+
+	err := s.wrapper.UploadRawData(testObject, []byte(`{"a":1}`),
+		WithMetadata(map[string]string{"source": "xaws"}),
+		WithTags(map[string]string{"env": "test"}),
+	)
+	s.Require().NoError(err, "Failed to upload object with metadata")
+
+	head, err := s.wrapper.Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.testBucket),
+		Key:    aws.String(testObject),
+	})
+	s.Require().NoError(err, "Failed to head object")
+	s.Equal("application/json", aws.ToString(head.ContentType))
+	s.Equal("xaws", head.Metadata["source"])
+}
+
+func (s *S3Suite) TestBucketExists() {
+	s.T().Parallel()
+	exists, err := s.wrapper.BucketExists(s.testBucket)
+	s.Require().NoError(err, "Failed to check test bucket")
+	s.True(exists, "Test bucket should exist")
+
+	exists, err = s.wrapper.BucketExists(s.testBucket + "-not-exist-" + strings.ToLower(randSeq(8)))
+	s.Require().NoError(err, "Failed to check non-existent bucket")
+	s.False(exists, "Bucket should not exist")
+}
+
+func (s *S3Suite) TestProgress() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%sprogress-test-%s.txt", s.testPrefix, s.T().Name())
+	testContent := make([]byte, 1024*1024)
+
+	var uploaded, downloaded int64
+
+	err := s.wrapper.UploadRawData(testObject, testContent, WithProgress(func(transferred, _ int64) {
+		uploaded = transferred
+	}))
+	s.Require().NoError(err, "Failed to upload object")
+	s.Equal(int64(len(testContent)), uploaded)
+
+	_, err = s.wrapper.GetObject(testObject, WithProgress(func(transferred, total int64) {
+		downloaded = transferred
+		s.Equal(int64(len(testContent)), total)
+	}))
+	s.Require().NoError(err, "Failed to get object")
+	s.Equal(int64(len(testContent)), downloaded)
+}
+
+func (s *S3Suite) TestDownloadMany() {
+	s.T().Parallel()
+	testPrefix := fmt.Sprintf("%sdownload-many-test-%s/", s.testPrefix, s.T().Name())
+
+	var keys []string
+
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("%sobj-%d.txt", testPrefix, i)
+		err := s.wrapper.UploadRawData(key, []byte(key))
+		s.Require().NoError(err, "Failed to upload test object")
+
+		keys = append(keys, key)
+	}
+
+	missing := testPrefix + "missing.txt"
+
+	contents, err := s.wrapper.DownloadMany(append(keys, missing), 3)
+	s.Error(err, "Missing key should be reported")
+	s.Contains(err.Error(), missing)
+	s.Len(contents, len(keys))
+
+	for _, key := range keys {
+		s.Equal([]byte(key), contents[key])
+	}
+}
+
+func (s *S3Suite) TestCompression() {
+	s.T().Parallel()
+	testContent := []byte("This is a test content for compressed upload.")
+
+	for _, name := range []string{CompressionGzip, CompressionZstd, CompressionBrotli} {
+		testObject := fmt.Sprintf("%scompression-test-%s.txt", s.testPrefix, name)
+		codec, err := GetCodec(name)
+		s.Require().NoError(err)
+
+		err = s.wrapper.UploadRawData(testObject, testContent, WithCompression(name))
+		s.Require().NoError(err, "Failed to upload compressed object")
+
+		content, err := s.wrapper.GetObject(testObject+codec.Ext(), WithCompression(name))
+		s.Require().NoError(err, "Failed to get compressed object")
+		s.Equal(testContent, content, "Decompressed content should match original")
+	}
+}
+
+func (s *S3Suite) TestUploadAtomic() {
+	s.T().Parallel()
+	testPrefix := fmt.Sprintf("%satomic-test-%s/", s.testPrefix, s.T().Name())
+	testObject := testPrefix + "atomic.txt"
+	testContent := []byte("This is a test content for atomic upload.")
+
+	err := s.wrapper.UploadAtomic(testObject, testContent)
+	s.Require().NoError(err, "Failed to upload atomically")
+
+	content, err := s.wrapper.GetObject(testObject)
+	s.Require().NoError(err)
+	s.Equal(testContent, content)
+
+	objects, err := s.wrapper.ListObjects(testPrefix)
+	s.Require().NoError(err)
+	s.Equal([]string{testObject}, objects, "Temporary object should be deleted")
+}
+
+func (s *S3Suite) TestGetObjectIfChanged() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%sif-changed-test-%s.txt", s.testPrefix, s.T().Name())
+	testContent := []byte("This is a test content for conditional get.")
+
+	err := s.wrapper.UploadRawData(testObject, testContent)
+	s.Require().NoError(err)
+
+	content, etag, notModified, err := s.wrapper.GetObjectIfChanged(testObject, "")
+	s.Require().NoError(err)
+	s.False(notModified)
+	s.Equal(testContent, content)
+	s.NotEmpty(etag)
+
+	content, newEtag, notModified, err := s.wrapper.GetObjectIfChanged(testObject, etag)
+	s.Require().NoError(err)
+	s.True(notModified)
+	s.Nil(content)
+	s.Equal(etag, newEtag)
+
+	cached, err := s.wrapper.GetObjectCached(testObject)
+	s.Require().NoError(err)
+	s.Equal(testContent, cached)
+}
+
+func (s *S3Suite) TestMultipartUpload() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%smultipart-test-%s.txt", s.testPrefix, s.T().Name())
+	part1 := bytes.Repeat([]byte("a"), 5*1024*1024)
+	part2 := []byte("the last part")
+
+	id, err := s.wrapper.CreateMultipartUpload(testObject)
+	s.Require().NoError(err, "Failed to create multipart upload")
+
+	p2, err := s.wrapper.UploadPart(testObject, id, 2, bytes.NewReader(part2))
+	s.Require().NoError(err, "Failed to upload part 2")
+
+	p1, err := s.wrapper.UploadPart(testObject, id, 1, bytes.NewReader(part1))
+	s.Require().NoError(err, "Failed to upload part 1")
+
+	err = s.wrapper.CompleteMultipartUpload(testObject, id, []types.CompletedPart{p2, p1})
+	s.Require().NoError(err, "Failed to complete multipart upload")
+
+	content, err := s.wrapper.GetObject(testObject)
+	s.Require().NoError(err)
+	s.Equal(append(part1, part2...), content)
+
+	abortedID, err := s.wrapper.CreateMultipartUpload(testObject + ".aborted")
+	s.Require().NoError(err)
+
+	uploads, err := s.wrapper.ListIncompleteUploads(testObject)
+	s.Require().NoError(err)
+	s.Len(uploads, 1)
+	s.Equal(abortedID, aws.ToString(uploads[0].UploadId))
+
+	err = s.wrapper.AbortMultipartUpload(testObject+".aborted", abortedID)
+	s.Require().NoError(err)
+}
+
+func (s *S3Suite) TestUploadStream() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%sstream-test-%s.txt", s.testPrefix, s.T().Name())
+	testContent := []byte(strings.Repeat("This is a test content for stream upload.\n", 1024))
+
+	_, err := s.wrapper.UploadStream(testObject, bytes.NewBuffer(testContent), WithCompression(CompressionGzip))
+	s.Require().NoError(err, "Failed to upload stream")
+
+	content, err := s.wrapper.GetObject(testObject+_dotgz, WithAutoUnGzip(true))
+	s.Require().NoError(err)
+	s.Equal(testContent, content)
+}
+
+func (s *S3Suite) TestJSONLWriter() {
+	s.T().Parallel()
+	testObject := fmt.Sprintf("%sjsonl-test-%s.jsonl", s.testPrefix, s.T().Name())
+
+	jw := s.wrapper.NewJSONLWriter(testObject)
+
+	var _ io.WriteCloser = jw
+
+	for i := 0; i < 3; i++ {
+		s.Require().NoError(jw.WriteRecord(map[string]int{"i": i}))
+	}
+	s.Require().NoError(jw.Close(), "Failed to finish jsonl upload")
+
+	content, err := s.wrapper.GetObject(testObject+_dotgz, WithAutoUnGzip(true))
+	s.Require().NoError(err)
+	s.Equal("{\"i\":0}\n{\"i\":1}\n{\"i\":2}\n", string(content))
+
+	plain := s.wrapper.NewJSONLWriter(testObject, WithCompression(CompressionNone))
+	s.Require().NoError(plain.WriteRecord(map[string]int{"i": 0}))
+	s.Require().NoError(plain.Close())
+
+	content, err = s.wrapper.GetObject(testObject)
+	s.Require().NoError(err)
+	s.Equal("{\"i\":0}\n", string(content))
+}
@@ -0,0 +1,147 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// SetBucketVersioning enables or suspends versioning of w.Bucket, or the bucket set by WithBucket.
+func (w *S3Client) SetBucketVersioning(enabled bool, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	status := types.BucketVersioningStatusSuspended
+	if enabled {
+		status = types.BucketVersioningStatusEnabled
+	}
+
+	return w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+			Bucket: aws.String(opt.bucket),
+			VersioningConfiguration: &types.VersioningConfiguration{
+				Status: status,
+			},
+		}, opt.clientOptions()...)
+
+		return err
+	})
+}
+
+// ListObjectVersions lists all versions and delete markers of objects with prefix,
+// WithTimeout applies to each page.
+func (w *S3Client) ListObjectVersions(prefix string, opts ...S3OptionFunc) ([]types.ObjectVersion, []types.DeleteMarkerEntry, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var (
+		versions []types.ObjectVersion
+		markers  []types.DeleteMarkerEntry
+
+		keyMarker       *string
+		versionIDMarker *string
+	)
+
+	for {
+		var resp *s3.ListObjectVersionsOutput
+
+		err := w.do(opt, 0, func(ctx context.Context) error {
+			var err error
+			resp, err = w.api().ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+				Bucket:          aws.String(opt.bucket),
+				Prefix:          aws.String(prefix),
+				KeyMarker:       keyMarker,
+				VersionIdMarker: versionIDMarker,
+			}, opt.clientOptions()...)
+
+			return err
+		})
+		if err != nil {
+			return versions, markers, err
+		}
+
+		versions = append(versions, resp.Versions...)
+		markers = append(markers, resp.DeleteMarkers...)
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+
+		keyMarker, versionIDMarker = resp.NextKeyMarker, resp.NextVersionIdMarker
+	}
+
+	return versions, markers, nil
+}
+
+// GetObjectVersion returns the content of a specific version of objectKey.
+func (w *S3Client) GetObjectVersion(objectKey, versionID string, opts ...S3OptionFunc) ([]byte, error) {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	var content []byte
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		result, err := w.api().GetObject(ctx, &s3.GetObjectInput{
+			Bucket:    aws.String(opt.bucket),
+			Key:       aws.String(objectKey),
+			VersionId: aws.String(versionID),
+		}, opt.clientOptions()...)
+		if err != nil {
+			return err
+		}
+		defer result.Body.Close()
+
+		content, err = io.ReadAll(result.Body)
+
+		return err
+	})
+
+	return content, err
+}
+
+// RestoreObjectVersion makes versionID the latest version of objectKey by copying it onto itself.
+func (w *S3Client) RestoreObjectVersion(objectKey, versionID string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(opt.bucket),
+			Key:        aws.String(objectKey),
+			CopySource: aws.String(copySource(opt.bucket, objectKey) + "?versionId=" + url.QueryEscape(versionID)),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore %s to version %s: %w", objectKey, versionID, err)
+	}
+
+	return nil
+}
+
+// DeleteObjectVersion permanently deletes a version of objectKey, this cannot be undone.
+func (w *S3Client) DeleteObjectVersion(objectKey, versionID string, opts ...S3OptionFunc) error {
+	opt := &S3Options{bucket: w.Bucket}
+	bindS3Options(opt, opts...)
+
+	err := w.do(opt, 0, func(ctx context.Context) error {
+		_, err := w.api().DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    aws.String(opt.bucket),
+			Key:       aws.String(objectKey),
+			VersionId: aws.String(versionID),
+		}, opt.clientOptions()...)
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete version %s of %s: %w", versionID, objectKey, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package xaws
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// versionSdk serves two pages of versions and records the versioned calls,
+// other methods of S3SdkClient are not implemented.
+type versionSdk struct {
+	S3SdkClient
+
+	copies  []*s3.CopyObjectInput
+	deletes []*s3.DeleteObjectInput
+	gets    []*s3.GetObjectInput
+	markers []*string
+}
+
+func (c *versionSdk) CopyObject(_ context.Context, in *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	c.copies = append(c.copies, in)
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (c *versionSdk) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	c.deletes = append(c.deletes, in)
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (c *versionSdk) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	c.gets = append(c.gets, in)
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader("v1 content"))}, nil
+}
+
+func (c *versionSdk) ListObjectVersions(_ context.Context, in *s3.ListObjectVersionsInput, _ ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	c.markers = append(c.markers, in.KeyMarker)
+
+	if in.KeyMarker == nil {
+		return &s3.ListObjectVersionsOutput{
+			Versions:            []types.ObjectVersion{{Key: aws.String("a"), VersionId: aws.String("1")}},
+			IsTruncated:         aws.Bool(true),
+			NextKeyMarker:       aws.String("a"),
+			NextVersionIdMarker: aws.String("1"),
+		}, nil
+	}
+
+	return &s3.ListObjectVersionsOutput{
+		Versions:      []types.ObjectVersion{{Key: aws.String("b"), VersionId: aws.String("2")}},
+		DeleteMarkers: []types.DeleteMarkerEntry{{Key: aws.String("c"), VersionId: aws.String("3")}},
+	}, nil
+}
+
+type VersionSuite struct {
+	suite.Suite
+}
+
+func TestVersion(t *testing.T) {
+	suite.Run(t, new(VersionSuite))
+}
+
+func (s *VersionSuite) Test_01_restoreEscapesVersionID() {
+	sdk := &versionSdk{}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	s.Nil(w.RestoreObjectVersion("dir/a b.json", "3/L4kqtJl+cnSC=x"))
+	s.Len(sdk.copies, 1)
+	s.Equal("bucket/dir/a%20b.json?versionId=3%2FL4kqtJl%2BcnSC%3Dx", aws.ToString(sdk.copies[0].CopySource))
+	s.Equal("dir/a b.json", aws.ToString(sdk.copies[0].Key))
+
+	s.Nil(w.RestoreObjectVersion("a.json", "v1", WithBucket("other")))
+	s.Equal("other/a.json?versionId=v1", aws.ToString(sdk.copies[1].CopySource))
+	s.Equal("other", aws.ToString(sdk.copies[1].Bucket))
+}
+
+func (s *VersionSuite) Test_02_getAndDelete() {
+	sdk := &versionSdk{}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	content, err := w.GetObjectVersion("a.json", "v+1")
+	s.Nil(err)
+	s.Equal("v1 content", string(content))
+	s.Equal("v+1", aws.ToString(sdk.gets[0].VersionId))
+
+	s.Nil(w.DeleteObjectVersion("a.json", "v+1"))
+	s.Equal("v+1", aws.ToString(sdk.deletes[0].VersionId))
+	s.Equal("bucket", aws.ToString(sdk.deletes[0].Bucket))
+}
+
+func (s *VersionSuite) Test_03_listPages() {
+	sdk := &versionSdk{}
+	w := NewS3WrapperWithClient("bucket", sdk)
+
+	versions, markers, err := w.ListObjectVersions("")
+	s.Nil(err)
+	s.Len(versions, 2)
+	s.Len(markers, 1)
+	s.Equal([]*string{nil, aws.String("a")}, sdk.markers)
+}
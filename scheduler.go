@@ -2,12 +2,13 @@ package xaws
 
 import (
 	"context"
+	"errors"
+	"reflect"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
-	"github.com/rs/zerolog/log"
 )
 
 // https://docs.aws.amazon.com/eventbridge/latest/userguide/eb-run-lambda-schedule.html
@@ -16,10 +17,17 @@ type SchedulerWrapper struct {
 	client *scheduler.Client
 
 	GroupName string
+
+	logger Logger
 }
 
-func NewSchedulerWrapper(groupName string) (*SchedulerWrapper, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// NewSchedulerWrapper creates a wrapper of schedule group groupName, "default" if empty,
+// the client doesn't retry unless WithRetryPolicy is set.
+func NewSchedulerWrapper(groupName string, opts ...ClientOptFunc) (*SchedulerWrapper, error) {
+	opt := ClientOpts{retryPolicy: &NoRetryPolicy}
+	bindClientOpts(&opt, opts...)
+
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -29,55 +37,260 @@ func NewSchedulerWrapper(groupName string) (*SchedulerWrapper, error) {
 	}
 
 	return &SchedulerWrapper{
-		client: scheduler.NewFromConfig(cfg, func(o *scheduler.Options) {
-			o.RetryMaxAttempts = 0
-		}),
+		client:    scheduler.NewFromConfig(opt.apply(cfg)),
 		GroupName: groupName,
+		logger:    opt.logger,
 	}, nil
 }
 
-// ListSchedulers list.
-func (w *SchedulerWrapper) ListSchedulers(name string) (*scheduler.ListSchedulesOutput, error) {
-	output, err := w.client.ListSchedules(context.TODO(), &scheduler.ListSchedulesInput{
-		NamePrefix: aws.String(name),
-		GroupName:  aws.String(w.GroupName),
-	})
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *SchedulerWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
 
-	return output, err
+func (w *SchedulerWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// ScheduleSummary is a brief of a schedule returned by ListSchedulers.
+type ScheduleSummary struct {
+	Name      string
+	GroupName string
+	Arn       string
+	State     types.ScheduleState
+	TargetArn string
+
+	CreationDate         time.Time
+	LastModificationDate time.Time
+}
+
+// ListSchedulers lists all schedules in group whose name starts with name, empty name lists all schedules of group.
+func (w *SchedulerWrapper) ListSchedulers(name string) ([]ScheduleSummary, error) {
+	input := &scheduler.ListSchedulesInput{
+		GroupName: aws.String(w.GroupName),
+	}
+
+	if name != "" {
+		input.NamePrefix = aws.String(name)
+	}
+
+	return w.listSchedules(input)
 }
 
-// Upsert create or update a scheduler.
-func (w *SchedulerWrapper) Upsert(name string, schedule, targetArn, roleArn, jsonStr string) error {
-	output, err := w.ListSchedulers(name)
+func (w *SchedulerWrapper) listSchedules(input *scheduler.ListSchedulesInput) ([]ScheduleSummary, error) {
+	var schedules []ScheduleSummary
+
+	paginator := scheduler.NewListSchedulesPaginator(w.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return schedules, err
+		}
+
+		for _, s := range page.Schedules {
+			summary := ScheduleSummary{
+				Name:                 aws.ToString(s.Name),
+				GroupName:            aws.ToString(s.GroupName),
+				Arn:                  aws.ToString(s.Arn),
+				State:                s.State,
+				CreationDate:         aws.ToTime(s.CreationDate),
+				LastModificationDate: aws.ToTime(s.LastModificationDate),
+			}
+
+			if s.Target != nil {
+				summary.TargetArn = aws.ToString(s.Target.Arn)
+			}
+
+			schedules = append(schedules, summary)
+		}
+	}
+
+	return schedules, nil
+}
+
+// ListAllSchedulers lists all schedules in group.
+func (w *SchedulerWrapper) ListAllSchedulers() ([]ScheduleSummary, error) {
+	return w.ListSchedulers("")
+}
+
+// FindSchedulesByTarget returns the schedules of all groups whose target is arn, e.g. a lambda function or a queue,
+// schedules of a version or alias of function arn are returned too.
+//
+// Usage:
+//
+//	// before decommissioning a function, find its dangling triggers.
+//	schedules, err := w.FindSchedulesByTarget("arn:aws:lambda:us-east-1:123456789012:function:report")
+func (w *SchedulerWrapper) FindSchedulesByTarget(arn string) ([]ScheduleSummary, error) {
+	all, err := w.listSchedules(&scheduler.ListSchedulesInput{})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var found []ScheduleSummary
+
+	for _, s := range all {
+		if targetArnMatches(s.TargetArn, arn) {
+			found = append(found, s)
+		}
+	}
+
+	return found, nil
+}
+
+// Upsert create or update a scheduler, and returns whether a change was made.
+func (w *SchedulerWrapper) Upsert(name string, schedule, targetArn, roleArn, jsonStr string) (bool, error) {
+	return w.UpsertWithSpec(name, ScheduleExpr(schedule), targetArn, roleArn, jsonStr)
+}
+
+// UpsertWithSpec create or update a scheduler with spec, and returns whether a change was made.
+func (w *SchedulerWrapper) UpsertWithSpec(name string, spec ScheduleSpec, targetArn, roleArn, jsonStr string) (bool, error) {
+	return w.UpsertWithTarget(name, spec, NewTarget(targetArn, roleArn, jsonStr))
+}
+
+// UpsertWithTarget create or update a scheduler invoking target as spec, and returns whether a change was made.
+//
+// The update is skipped when the existing schedule is enabled and has the same spec and target,
+// so LastModificationDate is not touched.
+func (w *SchedulerWrapper) UpsertWithTarget(name string, spec ScheduleSpec, target *ScheduleTarget) (bool, error) {
+	existing, err := w.GetSchedule(name)
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if !errors.As(err, &notFound) {
+			return false, err
+		}
+
+		existing = nil
 	}
 
 	msg := "created"
 
-	if len(output.Schedules) == 0 {
-		err = w.Create(name, schedule, targetArn, roleArn, jsonStr)
-	} else {
+	switch {
+	case existing == nil:
+		err = w.CreateWithTarget(name, spec, target)
+	case sameSchedule(existing, spec, target):
+		w.log().Debug("unchanged", "name", name, "target", target.Arn(), "schedule", spec.Expression)
+		return false, nil
+	default:
 		msg = "updated"
-		err = w.Update(name, schedule, targetArn, roleArn, jsonStr)
+		err = w.UpdateWithTarget(name, spec, target)
 	}
 
-	log.Info().Str("name", name).Str("target", targetArn).Str("schedule", schedule).Msg(msg)
+	if err != nil {
+		return false, err
+	}
 
-	return err
+	w.log().Info(msg, "name", name, "target", target.Arn(), "schedule", spec.Expression)
+
+	return true, nil
+}
+
+// sameSchedule tells if existing is enabled and matches spec and target,
+// only the target fields ScheduleTarget can set are compared.
+// Dates are compared in seconds, the precision EventBridge Scheduler keeps.
+func sameSchedule(existing *scheduler.GetScheduleOutput, spec ScheduleSpec, target *ScheduleTarget) bool {
+	if existing.State != types.ScheduleStateEnabled ||
+		aws.ToString(existing.ScheduleExpression) != spec.Expression ||
+		aws.ToString(existing.ScheduleExpressionTimezone) != aws.ToString(spec.timezone()) ||
+		!sameSecond(aws.ToTime(existing.StartDate), spec.StartDate) ||
+		!sameSecond(aws.ToTime(existing.EndDate), spec.EndDate) ||
+		(existing.ActionAfterCompletion == types.ActionAfterCompletionDelete) != spec.DeleteOnCompletion {
+		return false
+	}
+
+	return sameTarget(existing.Target, target.target)
+}
+
+func sameTarget(existing, want *types.Target) bool {
+	if existing == nil {
+		return false
+	}
+
+	if aws.ToString(existing.Arn) != aws.ToString(want.Arn) ||
+		aws.ToString(existing.RoleArn) != aws.ToString(want.RoleArn) ||
+		aws.ToString(existing.Input) != aws.ToString(want.Input) {
+		return false
+	}
+
+	if want.RetryPolicy != nil {
+		if existing.RetryPolicy == nil ||
+			aws.ToInt32(existing.RetryPolicy.MaximumRetryAttempts) != aws.ToInt32(want.RetryPolicy.MaximumRetryAttempts) {
+			return false
+		}
+
+		if want.RetryPolicy.MaximumEventAgeInSeconds != nil &&
+			aws.ToInt32(existing.RetryPolicy.MaximumEventAgeInSeconds) != aws.ToInt32(want.RetryPolicy.MaximumEventAgeInSeconds) {
+			return false
+		}
+	}
+
+	if (existing.DeadLetterConfig == nil) != (want.DeadLetterConfig == nil) ||
+		want.DeadLetterConfig != nil && aws.ToString(existing.DeadLetterConfig.Arn) != aws.ToString(want.DeadLetterConfig.Arn) {
+		return false
+	}
+
+	if (existing.SqsParameters == nil) != (want.SqsParameters == nil) ||
+		want.SqsParameters != nil && aws.ToString(existing.SqsParameters.MessageGroupId) != aws.ToString(want.SqsParameters.MessageGroupId) {
+		return false
+	}
+
+	return sameEcsParameters(existing.EcsParameters, want.EcsParameters)
+}
+
+func sameSecond(a, b time.Time) bool {
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+// sameEcsParameters compares the fields set in want, the service fills the others
+// with defaults (e.g. TaskCount 1), which would never match the zero values of want.
+func sameEcsParameters(existing, want *types.EcsParameters) bool {
+	if want == nil {
+		return true
+	}
+
+	if existing == nil {
+		return false
+	}
+
+	have, set := reflect.ValueOf(existing).Elem(), reflect.ValueOf(want).Elem()
+
+	for i := 0; i < set.NumField(); i++ {
+		if !set.Type().Field(i).IsExported() || set.Field(i).IsZero() {
+			continue
+		}
+
+		if !reflect.DeepEqual(have.Field(i).Interface(), set.Field(i).Interface()) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Create create a scheduler.
 func (w *SchedulerWrapper) Create(name, schedule, targetArn, roleArn, jsonStr string) error {
+	return w.CreateWithSpec(name, ScheduleExpr(schedule), targetArn, roleArn, jsonStr)
+}
+
+// CreateWithSpec create a scheduler run as spec.
+func (w *SchedulerWrapper) CreateWithSpec(name string, spec ScheduleSpec, targetArn, roleArn, jsonStr string) error {
+	return w.CreateWithTarget(name, spec, NewTarget(targetArn, roleArn, jsonStr))
+}
+
+// CreateWithTarget create a scheduler invoking target as spec.
+func (w *SchedulerWrapper) CreateWithTarget(name string, spec ScheduleSpec, target *ScheduleTarget) error {
 	_, err := w.client.CreateSchedule(context.TODO(), &scheduler.CreateScheduleInput{
 		FlexibleTimeWindow: &types.FlexibleTimeWindow{
 			Mode: types.FlexibleTimeWindowModeOff,
 		},
-		Name:               aws.String(name),
-		ScheduleExpression: aws.String(schedule),
-		State:              types.ScheduleStateEnabled,
-		Target:             newTarget(targetArn, roleArn, jsonStr),
-		GroupName:          aws.String(w.GroupName),
+		Name:                       aws.String(name),
+		ScheduleExpression:         aws.String(spec.Expression),
+		ScheduleExpressionTimezone: spec.timezone(),
+		StartDate:                  spec.startDate(),
+		EndDate:                    spec.endDate(),
+		ActionAfterCompletion:      spec.actionAfterCompletion(),
+		State:                      types.ScheduleStateEnabled,
+		Target:                     target.target,
+		GroupName:                  aws.String(w.GroupName),
 	})
 
 	return err
@@ -85,46 +298,84 @@ func (w *SchedulerWrapper) Create(name, schedule, targetArn, roleArn, jsonStr st
 
 // Update updates a scheduler.
 func (w *SchedulerWrapper) Update(name string, schedule, targetArn, roleArn, jsonStr string) error {
-	_, err := w.client.UpdateSchedule(context.TODO(), &scheduler.UpdateScheduleInput{
-		FlexibleTimeWindow: &types.FlexibleTimeWindow{
-			Mode: types.FlexibleTimeWindowModeOff,
-		},
-		Name:               aws.String(name),
-		ScheduleExpression: aws.String(schedule),
-		State:              types.ScheduleStateEnabled,
-		Target:             newTarget(targetArn, roleArn, jsonStr),
-		GroupName:          aws.String(w.GroupName),
+	return w.UpdateWithSpec(name, ScheduleExpr(schedule), targetArn, roleArn, jsonStr)
+}
+
+// UpdateWithSpec updates a scheduler to run as spec.
+func (w *SchedulerWrapper) UpdateWithSpec(name string, spec ScheduleSpec, targetArn, roleArn, jsonStr string) error {
+	return w.UpdateWithTarget(name, spec, NewTarget(targetArn, roleArn, jsonStr))
+}
+
+// UpdateWithTarget updates a scheduler to invoke target as spec.
+func (w *SchedulerWrapper) UpdateWithTarget(name string, spec ScheduleSpec, target *ScheduleTarget) error {
+	return w.update(name, spec, types.ScheduleStateEnabled, target)
+}
+
+// GetSchedule gets the full definition of schedule name.
+func (w *SchedulerWrapper) GetSchedule(name string) (*scheduler.GetScheduleOutput, error) {
+	return w.client.GetSchedule(context.TODO(), &scheduler.GetScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String(w.GroupName),
+	})
+}
+
+// Enable enables schedule name, the rest of its definition is kept.
+func (w *SchedulerWrapper) Enable(name string) error {
+	return w.setState(name, types.ScheduleStateEnabled)
+}
+
+// Disable disables schedule name, the rest of its definition is kept.
+func (w *SchedulerWrapper) Disable(name string) error {
+	return w.setState(name, types.ScheduleStateDisabled)
+}
+
+func (w *SchedulerWrapper) setState(name string, state types.ScheduleState) error {
+	sched, err := w.GetSchedule(name)
+	if err != nil {
+		return err
+	}
+
+	if sched.State == state {
+		return nil
+	}
+
+	_, err = w.client.UpdateSchedule(context.TODO(), &scheduler.UpdateScheduleInput{
+		Name:                       sched.Name,
+		GroupName:                  sched.GroupName,
+		Description:                sched.Description,
+		FlexibleTimeWindow:         sched.FlexibleTimeWindow,
+		KmsKeyArn:                  sched.KmsKeyArn,
+		ScheduleExpression:         sched.ScheduleExpression,
+		ScheduleExpressionTimezone: sched.ScheduleExpressionTimezone,
+		StartDate:                  sched.StartDate,
+		EndDate:                    sched.EndDate,
+		ActionAfterCompletion:      sched.ActionAfterCompletion,
+		State:                      state,
+		Target:                     sched.Target,
 	})
 
 	return err
 }
 
-func (w *SchedulerWrapper) Disable(name string, schedule, targetArn, roleArn, jsonStr string) error {
+func (w *SchedulerWrapper) update(name string, spec ScheduleSpec, state types.ScheduleState, target *ScheduleTarget) error {
 	_, err := w.client.UpdateSchedule(context.TODO(), &scheduler.UpdateScheduleInput{
 		FlexibleTimeWindow: &types.FlexibleTimeWindow{
 			Mode: types.FlexibleTimeWindowModeOff,
 		},
-		Name:               aws.String(name),
-		ScheduleExpression: aws.String(schedule),
-		State:              types.ScheduleStateDisabled,
-		Target:             newTarget(targetArn, roleArn, jsonStr),
-		GroupName:          aws.String(w.GroupName),
+		Name:                       aws.String(name),
+		ScheduleExpression:         aws.String(spec.Expression),
+		ScheduleExpressionTimezone: spec.timezone(),
+		StartDate:                  spec.startDate(),
+		EndDate:                    spec.endDate(),
+		ActionAfterCompletion:      spec.actionAfterCompletion(),
+		State:                      state,
+		Target:                     target.target,
+		GroupName:                  aws.String(w.GroupName),
 	})
 
 	return err
 }
 
-func newTarget(targetArn, roleArn, jsonStr string) *types.Target {
-	return &types.Target{
-		Arn:     aws.String(targetArn),
-		RoleArn: aws.String(roleArn),
-		Input:   aws.String(jsonStr),
-		RetryPolicy: &types.RetryPolicy{
-			MaximumRetryAttempts: aws.Int32(0),
-		},
-	}
-}
-
 // DeleteSchedule delete a schedule.
 func (w *SchedulerWrapper) DeleteSchedule(name string) (*scheduler.DeleteScheduleOutput, error) {
 	output, err := w.client.DeleteSchedule(context.TODO(), &scheduler.DeleteScheduleInput{
@@ -134,3 +385,43 @@ func (w *SchedulerWrapper) DeleteSchedule(name string) (*scheduler.DeleteSchedul
 
 	return output, err
 }
+
+// CreateScheduleGroup creates a schedule group.
+func (w *SchedulerWrapper) CreateScheduleGroup(name string) error {
+	_, err := w.client.CreateScheduleGroup(context.TODO(), &scheduler.CreateScheduleGroupInput{
+		Name: aws.String(name),
+	})
+
+	return err
+}
+
+// DeleteScheduleGroup deletes a schedule group and all schedules in it.
+func (w *SchedulerWrapper) DeleteScheduleGroup(name string) error {
+	_, err := w.client.DeleteScheduleGroup(context.TODO(), &scheduler.DeleteScheduleGroupInput{
+		Name: aws.String(name),
+	})
+
+	return err
+}
+
+// ListScheduleGroups lists all schedule groups whose name starts with prefix, empty prefix lists all groups.
+func (w *SchedulerWrapper) ListScheduleGroups(prefix string) ([]types.ScheduleGroupSummary, error) {
+	input := &scheduler.ListScheduleGroupsInput{}
+	if prefix != "" {
+		input.NamePrefix = aws.String(prefix)
+	}
+
+	var groups []types.ScheduleGroupSummary
+
+	paginator := scheduler.NewListScheduleGroupsPaginator(w.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return groups, err
+		}
+
+		groups = append(groups, page.ScheduleGroups...)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,185 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+const (
+	// _getMetricStatisticsMaxPoints is the max datapoints of a GetMetricStatistics call.
+	_getMetricStatisticsMaxPoints = 1440
+	// _scheduleHealthGrace is how late an invocation may show up in metrics after a fire time.
+	_scheduleHealthGrace = 5 * time.Minute
+	// _scheduleHealthMaxRuns caps the expected runs of a health check.
+	_scheduleHealthMaxRuns = 10000
+)
+
+var ErrUnsupportedTarget = errors.New("unsupported target")
+
+// invocationMetric returns the CloudWatch metric counting invocations of targetArn.
+func invocationMetric(targetArn string) (namespace, metric string, dimensions map[string]string, err error) {
+	a, err := ParseARN(targetArn)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	switch a.Service {
+	case "lambda":
+		// versions and aliases are counted by the function.
+		name, _, _ := strings.Cut(a.ResourceName(), ":")
+		return "AWS/Lambda", "Invocations", map[string]string{"FunctionName": name}, nil
+	case "sqs":
+		return "AWS/SQS", "NumberOfMessagesSent", map[string]string{"QueueName": a.Resource}, nil
+	case "sns":
+		return "AWS/SNS", "NumberOfMessagesPublished", map[string]string{"TopicName": a.Resource}, nil
+	case "states":
+		return "AWS/States", "ExecutionsStarted", map[string]string{"StateMachineArn": targetArn}, nil
+	}
+
+	return "", "", nil, fmt.Errorf("%w: %s", ErrUnsupportedTarget, targetArn)
+}
+
+// metricPeriod returns the smallest multiple of a minute covering start to end within one GetMetricStatistics call.
+func metricPeriod(start, end time.Time) time.Duration {
+	minutes := int64(end.Sub(start)/time.Minute) + 1
+	return time.Duration((minutes+_getMetricStatisticsMaxPoints-1)/_getMetricStatisticsMaxPoints) * time.Minute
+}
+
+// TargetInvocations returns the start of each metric period between start and end in which
+// targetArn was invoked, sorted. Lambda functions, SQS queues, SNS topics and state machines are supported.
+//
+// Usage:
+//
+//	ts, err := w.TargetInvocations("arn:aws:lambda:us-east-1:123456789012:function:report", time.Now().Add(-time.Hour), time.Now())
+func (w *CloudWatchWrapper) TargetInvocations(targetArn string, start, end time.Time) ([]time.Time, error) {
+	namespace, metric, dimensions, err := invocationMetric(targetArn)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := w.client.GetMetricStatistics(context.TODO(), &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metric),
+		Dimensions: toDimensions(dimensions),
+		StartTime:  aws.Time(start),
+		EndTime:    aws.Time(end),
+		Period:     aws.Int32(int32(metricPeriod(start, end) / time.Second)),
+		Statistics: []cwtypes.Statistic{cwtypes.StatisticSum},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get %s/%s of %s: %w", namespace, metric, targetArn, err)
+	}
+
+	var invocations []time.Time
+
+	for _, p := range out.Datapoints {
+		if aws.ToFloat64(p.Sum) > 0 {
+			invocations = append(invocations, aws.ToTime(p.Timestamp))
+		}
+	}
+
+	sort.Slice(invocations, func(i, j int) bool { return invocations[i].Before(invocations[j]) })
+
+	return invocations, nil
+}
+
+// ScheduleHealth is the result of checking the expected runs of a schedule against its target's invocations.
+type ScheduleHealth struct {
+	Name      string
+	State     types.ScheduleState
+	TargetArn string
+	// Expected are the fire times in the checked window.
+	Expected []time.Time
+	// Invocations are the start of each metric period the target was invoked in.
+	Invocations []time.Time
+	// Missed are the fire times without a target invocation.
+	Missed []time.Time
+}
+
+// Healthy reports whether every expected run invoked the target.
+func (h *ScheduleHealth) Healthy() bool {
+	return len(h.Missed) == 0
+}
+
+// CheckHealth checks schedule name ran as expected in the last lookback, by matching its fire times
+// with the invocations of its target in cw, see CloudWatchWrapper.TargetInvocations.
+//
+// Fire times in the last few minutes are not checked, as metrics of the target may not be there yet.
+// Invocations of the target from other sources are counted too.
+//
+// Usage:
+//
+//	h, err := w.CheckHealth("nightly-report", cw, 24*time.Hour)
+//	if err == nil && !h.Healthy() {
+//		log.Printf("missed runs: %v", h.Missed)
+//	}
+func (w *SchedulerWrapper) CheckHealth(name string, cw *CloudWatchWrapper, lookback time.Duration) (*ScheduleHealth, error) {
+	sched, err := w.GetSchedule(name)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := ScheduleSpec{
+		Expression: aws.ToString(sched.ScheduleExpression),
+		Timezone:   aws.ToString(sched.ScheduleExpressionTimezone),
+		StartDate:  aws.ToTime(sched.StartDate),
+		EndDate:    aws.ToTime(sched.EndDate),
+	}
+
+	// rate schedules count from their creation unless they have a start date.
+	if spec.StartDate.IsZero() && strings.HasPrefix(spec.Expression, "rate(") {
+		spec.StartDate = aws.ToTime(sched.CreationDate)
+	}
+
+	end := time.Now()
+	start := end.Add(-lookback)
+	period := metricPeriod(start, end)
+
+	tolerance := _scheduleHealthGrace + period
+	if sched.FlexibleTimeWindow != nil && sched.FlexibleTimeWindow.Mode == types.FlexibleTimeWindowModeFlexible {
+		tolerance += time.Duration(aws.ToInt32(sched.FlexibleTimeWindow.MaximumWindowInMinutes)) * time.Minute
+	}
+
+	expected, err := spec.runsBetween(start, end.Add(-tolerance), _scheduleHealthMaxRuns)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &ScheduleHealth{
+		Name:      name,
+		State:     sched.State,
+		TargetArn: aws.ToString(sched.Target.Arn),
+		Expected:  expected,
+	}
+
+	if h.Invocations, err = cw.TargetInvocations(h.TargetArn, start.Add(-period), end); err != nil {
+		return nil, err
+	}
+
+	h.Missed = missedRuns(h.Expected, h.Invocations, period, tolerance)
+
+	return h, nil
+}
+
+// missedRuns returns the sorted fire times of expected without an invocation in [t-period, t+tolerance].
+func missedRuns(expected, invocations []time.Time, period, tolerance time.Duration) []time.Time {
+	var missed []time.Time
+
+	for _, t := range expected {
+		i := sort.Search(len(invocations), func(i int) bool { return !invocations[i].Before(t.Add(-period)) })
+		if i == len(invocations) || invocations[i].After(t.Add(tolerance)) {
+			missed = append(missed, t)
+		}
+	}
+
+	return missed
+}
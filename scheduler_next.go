@@ -0,0 +1,473 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	_cronMinYear = 1970
+	_cronMaxYear = 2199
+)
+
+var ErrInvalidScheduleExpr = errors.New("invalid schedule expression")
+
+var (
+	_cronMonthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	// days of week are numbered from 1 (SUN) to 7 (SAT).
+	_cronDayNames = map[string]int{"SUN": 1, "MON": 2, "TUE": 3, "WED": 4, "THU": 5, "FRI": 6, "SAT": 7}
+)
+
+// scheduleIterator returns the first fire time strictly after a time, false if there is none.
+type scheduleIterator func(after time.Time) (time.Time, bool)
+
+// NextRuns returns the next n fire times of s after after, fewer if the schedule ends,
+// in the timezone of s (UTC by default). StartDate and EndDate are honored.
+//
+// Rate schedules run every interval from StartDate, or from after if StartDate is zero, like a schedule created at after.
+// Cron expressions are evaluated like EventBridge: 6 fields, "?" in day-of-month or day-of-week,
+// and L, W and # are supported.
+//
+// Usage:
+//
+//	runs, err := ScheduleCron("0 8 ? * MON-FRI *").InTimezone("Asia/Shanghai").NextRuns(time.Now(), 5)
+func (s ScheduleSpec) NextRuns(after time.Time, n int) ([]time.Time, error) {
+	next, err := s.iterator()
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []time.Time
+
+	for len(runs) < n {
+		t, ok := next(after)
+		if !ok {
+			break
+		}
+
+		runs = append(runs, t)
+		after = t
+	}
+
+	return runs, nil
+}
+
+// runsBetween returns the fire times of s in (start, end], at most limit of them.
+func (s ScheduleSpec) runsBetween(start, end time.Time, limit int) ([]time.Time, error) {
+	next, err := s.iterator()
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []time.Time
+
+	for t, ok := next(start); ok && !t.After(end) && len(runs) < limit; t, ok = next(t) {
+		runs = append(runs, t)
+	}
+
+	return runs, nil
+}
+
+// iterator returns the iterator of the expression of s, bounded by StartDate and EndDate.
+func (s ScheduleSpec) iterator() (scheduleIterator, error) {
+	loc := time.UTC
+
+	if s.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return nil, fmt.Errorf("%w: timezone %q: %w", ErrInvalidScheduleExpr, s.Timezone, err)
+		}
+	}
+
+	kind, body, ok := strings.Cut(strings.TrimSuffix(strings.TrimSpace(s.Expression), ")"), "(")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidScheduleExpr, s.Expression)
+	}
+
+	var (
+		next scheduleIterator
+		err  error
+	)
+
+	switch kind {
+	case "rate":
+		next, err = rateIterator(body, s.StartDate)
+	case "cron":
+		next, err = cronIterator(body, loc)
+	case "at":
+		next, err = atIterator(body, loc)
+	default:
+		err = fmt.Errorf("%w: unknown kind %q", ErrInvalidScheduleExpr, kind)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func(after time.Time) (time.Time, bool) {
+		if !s.StartDate.IsZero() && after.Before(s.StartDate) {
+			after = s.StartDate.Add(-time.Nanosecond)
+		}
+
+		t, ok := next(after)
+		if !ok || (!s.EndDate.IsZero() && t.After(s.EndDate)) {
+			return time.Time{}, false
+		}
+
+		return t.In(loc), true
+	}, nil
+}
+
+func rateIterator(body string, anchor time.Time) (scheduleIterator, error) {
+	value, unit, _ := strings.Cut(strings.TrimSpace(body), " ")
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("%w: rate value %q", ErrInvalidScheduleExpr, value)
+	}
+
+	var interval time.Duration
+
+	switch strings.TrimSuffix(unit, "s") {
+	case "minute":
+		interval = time.Minute
+	case "hour":
+		interval = time.Hour
+	case "day":
+		interval = 24 * time.Hour
+	default:
+		return nil, fmt.Errorf("%w: rate unit %q", ErrInvalidScheduleExpr, unit)
+	}
+
+	interval *= time.Duration(n)
+
+	return func(after time.Time) (time.Time, bool) {
+		if anchor.IsZero() {
+			return after.Add(interval), true
+		}
+
+		if after.Before(anchor) {
+			return anchor, true
+		}
+
+		return anchor.Add((after.Sub(anchor)/interval + 1) * interval), true
+	}, nil
+}
+
+func atIterator(body string, loc *time.Location) (scheduleIterator, error) {
+	at, err := time.ParseInLocation(_atLayout, strings.TrimSpace(body), loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidScheduleExpr, err)
+	}
+
+	return func(after time.Time) (time.Time, bool) {
+		return at, at.After(after)
+	}, nil
+}
+
+// cronSchedule is a parsed EventBridge cron expression, sets are indexed by value.
+type cronSchedule struct {
+	minutes, hours, months, years []bool
+
+	dom cronDaySpec
+	dow cronDaySpec
+}
+
+// cronDaySpec matches days of month or week, set is used unless a special value is set.
+type cronDaySpec struct {
+	any bool
+	set []bool
+
+	last    bool // L: last day of month, or nL: last day n of week in the month
+	weekday int  // nW: nearest weekday to day n, -1 for LW
+	nth     int  // n#k: k-th day n of week in the month
+	day     int  // the n of nL and n#k
+}
+
+func cronIterator(body string, loc *time.Location) (scheduleIterator, error) {
+	c, err := parseCron(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(after time.Time) (time.Time, bool) {
+		return c.next(after, loc)
+	}, nil
+}
+
+func parseCron(body string) (*cronSchedule, error) {
+	fields := strings.Fields(body)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("%w: cron needs 6 fields, got %d", ErrInvalidScheduleExpr, len(fields))
+	}
+
+	if (fields[2] == "?") == (fields[4] == "?") {
+		return nil, fmt.Errorf("%w: one of day-of-month and day-of-week must be ?", ErrInvalidScheduleExpr)
+	}
+
+	c := &cronSchedule{}
+
+	var err error
+
+	if c.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+
+	if c.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+
+	if c.dom, err = parseCronDom(fields[2]); err != nil {
+		return nil, err
+	}
+
+	if c.months, err = parseCronField(fields[3], 1, 12, _cronMonthNames); err != nil {
+		return nil, err
+	}
+
+	if c.dow, err = parseCronDow(fields[4]); err != nil {
+		return nil, err
+	}
+
+	if c.years, err = parseCronField(fields[5], _cronMinYear, _cronMaxYear, nil); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// parseCronField parses a list of "*", "v", "a-b" with an optional "/step", values are numbers or names.
+func parseCronField(field string, lo, hi int, names map[string]int) ([]bool, error) {
+	set := make([]bool, hi+1)
+
+	value := func(s string) (int, error) {
+		if v, ok := names[strings.ToUpper(s)]; ok {
+			return v, nil
+		}
+
+		v, err := strconv.Atoi(s)
+		if err != nil || v < lo || v > hi {
+			return 0, fmt.Errorf("%w: %q not in %d-%d", ErrInvalidScheduleExpr, s, lo, hi)
+		}
+
+		return v, nil
+	}
+
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return nil, fmt.Errorf("%w: step %q", ErrInvalidScheduleExpr, stepStr)
+			}
+		}
+
+		start, end := lo, hi
+
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+
+			var err error
+			if start, err = value(a); err != nil {
+				return nil, err
+			}
+
+			end = start
+
+			switch {
+			case isRange:
+				if end, err = value(b); err != nil {
+					return nil, err
+				}
+			case hasStep:
+				end = hi
+			}
+		}
+
+		if start > end {
+			return nil, fmt.Errorf("%w: range %q", ErrInvalidScheduleExpr, rng)
+		}
+
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+
+	return set, nil
+}
+
+func parseCronDom(field string) (cronDaySpec, error) {
+	switch {
+	case field == "?":
+		return cronDaySpec{any: true}, nil
+	case field == "L":
+		return cronDaySpec{last: true}, nil
+	case field == "LW":
+		return cronDaySpec{weekday: -1}, nil
+	case strings.HasSuffix(field, "W"):
+		day, err := strconv.Atoi(strings.TrimSuffix(field, "W"))
+		if err != nil || day < 1 || day > 31 {
+			return cronDaySpec{}, fmt.Errorf("%w: day-of-month %q", ErrInvalidScheduleExpr, field)
+		}
+
+		return cronDaySpec{weekday: day}, nil
+	}
+
+	set, err := parseCronField(field, 1, 31, nil)
+
+	return cronDaySpec{set: set}, err
+}
+
+func parseCronDow(field string) (cronDaySpec, error) {
+	day := func(s string) (int, error) {
+		set, err := parseCronField(s, 1, 7, _cronDayNames)
+		if err != nil {
+			return 0, err
+		}
+
+		for v, ok := range set {
+			if ok {
+				return v, nil
+			}
+		}
+
+		return 0, fmt.Errorf("%w: day-of-week %q", ErrInvalidScheduleExpr, s)
+	}
+
+	switch {
+	case field == "?":
+		return cronDaySpec{any: true}, nil
+	case field == "L":
+		return cronDaySpec{set: []bool{7: true}}, nil
+	case strings.HasSuffix(field, "L"):
+		d, err := day(strings.TrimSuffix(field, "L"))
+		return cronDaySpec{last: true, day: d}, err
+	case strings.Contains(field, "#"):
+		ds, ks, _ := strings.Cut(field, "#")
+
+		k, err := strconv.Atoi(ks)
+		if err != nil || k < 1 || k > 5 {
+			return cronDaySpec{}, fmt.Errorf("%w: day-of-week %q", ErrInvalidScheduleExpr, field)
+		}
+
+		d, err := day(ds)
+
+		return cronDaySpec{nth: k, day: d}, err
+	}
+
+	set, err := parseCronField(field, 1, 7, _cronDayNames)
+
+	return cronDaySpec{set: set}, err
+}
+
+// matchDom reports whether day d of a month of daysIn days matches spec.
+func (spec cronDaySpec) matchDom(date time.Time, daysIn int) bool {
+	d := date.Day()
+
+	switch {
+	case spec.any:
+		return true
+	case spec.last:
+		return d == daysIn
+	case spec.weekday != 0:
+		target := spec.weekday
+		if target < 0 || target > daysIn {
+			target = daysIn
+		}
+
+		wd := time.Date(date.Year(), date.Month(), target, 0, 0, 0, 0, time.UTC).Weekday()
+
+		switch {
+		case wd == time.Saturday && (target == 1 || spec.weekday < 0):
+			target += 2
+			if spec.weekday < 0 {
+				target -= 3
+			}
+		case wd == time.Saturday:
+			target--
+		case wd == time.Sunday && (target == daysIn || spec.weekday < 0):
+			target -= 2
+		case wd == time.Sunday:
+			target++
+		}
+
+		return d == target
+	default:
+		return spec.set[d]
+	}
+}
+
+// matchDow reports whether date, in a month of daysIn days, matches spec.
+func (spec cronDaySpec) matchDow(date time.Time, daysIn int) bool {
+	d, wd := date.Day(), int(date.Weekday())+1
+
+	switch {
+	case spec.any:
+		return true
+	case spec.last:
+		return wd == spec.day && d+7 > daysIn
+	case spec.nth > 0:
+		return wd == spec.day && (d-1)/7+1 == spec.nth
+	default:
+		return spec.set[wd]
+	}
+}
+
+// next returns the first fire time of c strictly after after, evaluated in loc.
+func (c *cronSchedule) next(after time.Time, loc *time.Location) (time.Time, bool) {
+	after = after.In(loc)
+	y, m, d := after.Date()
+
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Year() <= _cronMaxYear; {
+		if day.Year() < _cronMinYear || !c.years[day.Year()] || !c.months[day.Month()] {
+			// first day of next month.
+			day = time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		daysIn := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+		if c.dom.matchDom(day, daysIn) && c.dow.matchDow(day, daysIn) {
+			if t, ok := c.firstTimeOfDay(day, after, loc); ok {
+				return t, true
+			}
+		}
+
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	return time.Time{}, false
+}
+
+// firstTimeOfDay returns the first hour and minute of c on day strictly after after,
+// times skipped by a daylight saving change are skipped.
+func (c *cronSchedule) firstTimeOfDay(day, after time.Time, loc *time.Location) (time.Time, bool) {
+	for h, okH := range c.hours {
+		if !okH {
+			continue
+		}
+
+		for mi, okM := range c.minutes {
+			if !okM {
+				continue
+			}
+
+			t := time.Date(day.Year(), day.Month(), day.Day(), h, mi, 0, 0, loc)
+			if t.Hour() != h || t.Minute() != mi || !t.After(after) {
+				continue
+			}
+
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+package xaws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ScheduleNextSuite struct {
+	suite.Suite
+}
+
+func TestScheduleNext(t *testing.T) {
+	suite.Run(t, new(ScheduleNextSuite))
+}
+
+func (s *ScheduleNextSuite) runs(spec ScheduleSpec, after string, n int) []string {
+	t, err := time.Parse(time.RFC3339, after)
+	s.Require().Nil(err)
+
+	runs, err := spec.NextRuns(t, n)
+	s.Require().Nil(err)
+
+	got := make([]string, 0, len(runs))
+	for _, r := range runs {
+		got = append(got, r.Format(time.RFC3339))
+	}
+
+	return got
+}
+
+func (s *ScheduleNextSuite) Test_01_rate() {
+	s.Equal([]string{"2024-01-01T00:10:30Z", "2024-01-01T00:20:30Z"},
+		s.runs(ScheduleRate("10 minutes"), "2024-01-01T00:00:30Z", 2))
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.Equal([]string{"2024-01-01T02:00:00Z", "2024-01-01T04:00:00Z"},
+		s.runs(ScheduleRate("2 hours").Between(start, time.Time{}), "2024-01-01T01:30:00Z", 2))
+	s.Equal([]string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"},
+		s.runs(ScheduleRate("1 day").Between(start, start.Add(36*time.Hour)), "2023-12-30T00:00:00Z", 3))
+}
+
+func (s *ScheduleNextSuite) Test_02_cron() {
+	tests := []struct {
+		expr  string
+		after string
+		want  []string
+	}{
+		{"0 8 ? * MON-FRI *", "2024-03-08T09:00:00Z", []string{"2024-03-11T08:00:00Z", "2024-03-12T08:00:00Z"}},
+		{"*/15 * * * ? *", "2024-03-08T09:07:00Z", []string{"2024-03-08T09:15:00Z", "2024-03-08T09:30:00Z"}},
+		{"0 0 L * ? *", "2024-01-31T00:00:00Z", []string{"2024-02-29T00:00:00Z", "2024-03-31T00:00:00Z"}},
+		// 2024-06-01 is a Saturday, 2024-09-01 a Sunday.
+		{"0 0 1W 6,9 ? *", "2024-01-01T00:00:00Z", []string{"2024-06-03T00:00:00Z", "2024-09-02T00:00:00Z"}},
+		{"0 0 15W 6 ? *", "2024-01-01T00:00:00Z", []string{"2024-06-14T00:00:00Z"}},
+		{"0 0 LW 3 ? 2024", "2024-01-01T00:00:00Z", []string{"2024-03-29T00:00:00Z"}},
+		{"30 12 ? * 6L *", "2024-01-01T00:00:00Z", []string{"2024-01-26T12:30:00Z", "2024-02-23T12:30:00Z"}},
+		{"0 10 ? * TUE#2 *", "2024-01-01T00:00:00Z", []string{"2024-01-09T10:00:00Z", "2024-02-13T10:00:00Z"}},
+		{"0 0 29 FEB ? 2024-2032", "2024-03-01T00:00:00Z", []string{"2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z"}},
+		{"0 0 1 1 ? 2020", "2024-01-01T00:00:00Z", nil},
+	}
+
+	for _, tt := range tests {
+		got := s.runs(ScheduleCron(tt.expr), tt.after, 3)
+		if tt.want == nil {
+			s.Empty(got, tt.expr)
+			continue
+		}
+
+		s.Equal(tt.want, got[:min(len(tt.want), len(got))], tt.expr)
+	}
+}
+
+func (s *ScheduleNextSuite) Test_03_timezone() {
+	// 02:30 doesn't exist on 2024-03-10 in New York.
+	got := s.runs(ScheduleCron("30 2 * 3 ? 2024").InTimezone("America/New_York"), "2024-03-09T12:00:00Z", 2)
+	s.Equal([]string{"2024-03-11T02:30:00-04:00", "2024-03-12T02:30:00-04:00"}, got)
+
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	s.Equal([]string{"2024-05-01T09:00:00Z"}, s.runs(ScheduleAt(at), "2024-04-30T00:00:00Z", 2))
+	s.Empty(s.runs(ScheduleAt(at), "2024-05-01T09:00:00Z", 2))
+}
+
+func (s *ScheduleNextSuite) Test_04_invalid() {
+	for _, expr := range []string{
+		"every(5 minutes)",
+		"rate(0 minutes)",
+		"rate(5 weeks)",
+		"cron(0 8 * * *)",
+		"cron(0 8 * * MON *)",
+		"cron(0 24 * * ? *)",
+		"cron(0 8 ? * MON#6 *)",
+		"cron(0 8 ? FOO * *)",
+	} {
+		_, err := ScheduleExpr(expr).NextRuns(time.Now(), 1)
+		s.ErrorIs(err, ErrInvalidScheduleExpr, expr)
+	}
+
+	_, err := ScheduleRate("5 minutes").InTimezone("Mars/Olympus").NextRuns(time.Now(), 1)
+	s.ErrorIs(err, ErrInvalidScheduleExpr)
+}
+
+func (s *ScheduleNextSuite) Test_05_missedRuns() {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected := []time.Time{base, base.Add(time.Hour), base.Add(2 * time.Hour)}
+	invocations := []time.Time{base, base.Add(2*time.Hour + 3*time.Minute)}
+
+	missed := missedRuns(expected, invocations, time.Minute, 5*time.Minute)
+	s.Equal([]time.Time{base.Add(time.Hour)}, missed)
+
+	s.Equal(time.Minute, metricPeriod(base, base.Add(24*time.Hour-time.Minute)))
+	s.Equal(2*time.Minute, metricPeriod(base, base.Add(24*time.Hour)))
+
+	_, _, dims, err := invocationMetric("arn:aws:lambda:us-east-1:123456789012:function:report:live")
+	s.Nil(err)
+	s.Equal(map[string]string{"FunctionName": "report"}, dims)
+
+	_, _, _, err = invocationMetric("arn:aws:ecs:us-east-1:123456789012:cluster/main")
+	s.ErrorIs(err, ErrUnsupportedTarget)
+}
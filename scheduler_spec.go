@@ -0,0 +1,129 @@
+package xaws
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+const _atLayout = "2006-01-02T15:04:05"
+
+// ScheduleSpec describes when a schedule runs.
+//
+// Usage:
+//
+//	spec := ScheduleCron("0 8 * * ? *").InTimezone("Asia/Shanghai")
+//	spec := ScheduleRate("5 minutes").Between(start, end)
+//	spec := ScheduleAt(time.Now().Add(time.Hour)).DeleteAfterCompletion()
+type ScheduleSpec struct {
+	// Expression is the raw schedule expression, e.g. "rate(5 minutes)", "cron(0 8 * * ? *)" or "at(2024-01-02T15:04:05)".
+	Expression string
+	// Timezone is an IANA timezone name for cron and at expressions, UTC is used when empty.
+	Timezone string
+	// StartDate and EndDate bound recurring schedules, zero value means no bound.
+	StartDate time.Time
+	EndDate   time.Time
+	// DeleteOnCompletion deletes a one-time schedule after it runs.
+	DeleteOnCompletion bool
+}
+
+// ScheduleExpr creates a spec from a raw schedule expression.
+func ScheduleExpr(expression string) ScheduleSpec {
+	return ScheduleSpec{Expression: expression}
+}
+
+// ScheduleRate creates a rate spec, e.g. ScheduleRate("5 minutes").
+func ScheduleRate(value string) ScheduleSpec {
+	return ScheduleSpec{Expression: fmt.Sprintf("rate(%s)", value)}
+}
+
+// ScheduleCron creates a cron spec, e.g. ScheduleCron("0 8 * * ? *").
+func ScheduleCron(fields string) ScheduleSpec {
+	return ScheduleSpec{Expression: fmt.Sprintf("cron(%s)", fields)}
+}
+
+// ScheduleAt creates a one-time spec run at t, the location of t is used as timezone,
+// t is converted to UTC if its location is not an IANA timezone, e.g. time.Local or a time.FixedZone.
+func ScheduleAt(t time.Time) ScheduleSpec {
+	if !isIANALocation(t) {
+		t = t.UTC()
+	}
+
+	return ScheduleSpec{
+		Expression: fmt.Sprintf("at(%s)", t.Format(_atLayout)),
+		Timezone:   t.Location().String(),
+	}
+}
+
+// isIANALocation tells if the location name of t is an IANA timezone with the offset of t,
+// a time.FixedZone may be named like one (e.g. "EST") with another offset.
+func isIANALocation(t time.Time) bool {
+	loc := t.Location()
+	if loc == time.Local {
+		return false
+	}
+
+	iana, err := time.LoadLocation(loc.String())
+	if err != nil {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, want := t.In(iana).Zone()
+
+	return offset == want
+}
+
+// InTimezone sets the timezone the expression is evaluated in, e.g. "America/New_York".
+func (s ScheduleSpec) InTimezone(tz string) ScheduleSpec {
+	s.Timezone = tz
+	return s
+}
+
+// Between sets the start and end date of spec, zero value leaves the bound open.
+func (s ScheduleSpec) Between(start, end time.Time) ScheduleSpec {
+	s.StartDate = start
+	s.EndDate = end
+
+	return s
+}
+
+// DeleteAfterCompletion deletes a one-time schedule once it has run.
+func (s ScheduleSpec) DeleteAfterCompletion() ScheduleSpec {
+	s.DeleteOnCompletion = true
+	return s
+}
+
+func (s ScheduleSpec) timezone() *string {
+	if s.Timezone == "" {
+		return nil
+	}
+
+	return aws.String(s.Timezone)
+}
+
+func (s ScheduleSpec) startDate() *time.Time {
+	if s.StartDate.IsZero() {
+		return nil
+	}
+
+	return aws.Time(s.StartDate)
+}
+
+func (s ScheduleSpec) endDate() *time.Time {
+	if s.EndDate.IsZero() {
+		return nil
+	}
+
+	return aws.Time(s.EndDate)
+}
+
+func (s ScheduleSpec) actionAfterCompletion() types.ActionAfterCompletion {
+	if s.DeleteOnCompletion {
+		return types.ActionAfterCompletionDelete
+	}
+
+	return ""
+}
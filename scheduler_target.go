@@ -0,0 +1,98 @@
+package xaws
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+// ScheduleTarget builds the target a schedule invokes.
+//
+// Usage:
+//
+//	target := NewSqsTarget(queueArn, roleArn, `{"job":"sync"}`, "sync-group").
+//		WithRetry(3, time.Hour).
+//		WithDLQ(dlqArn)
+//	err := w.CreateWithTarget("nightly-sync", ScheduleCron("0 2 * * ? *"), target)
+type ScheduleTarget struct {
+	target *types.Target
+}
+
+// NewTarget creates a target invoked with input, it suits Lambda and other templated targets.
+// Retries are disabled by default, use WithRetry to enable them.
+func NewTarget(targetArn, roleArn, input string) *ScheduleTarget {
+	return &ScheduleTarget{target: newTarget(targetArn, roleArn, input)}
+}
+
+// NewLambdaTarget creates a target invoking function with input as event.
+func NewLambdaTarget(functionArn, roleArn, input string) *ScheduleTarget {
+	return NewTarget(functionArn, roleArn, input)
+}
+
+// NewSqsTarget creates a target sending input to queue, messageGroupID is required by FIFO queues
+// and should be empty for standard queues.
+func NewSqsTarget(queueArn, roleArn, input, messageGroupID string) *ScheduleTarget {
+	t := NewTarget(queueArn, roleArn, input)
+
+	if messageGroupID != "" {
+		t.target.SqsParameters = &types.SqsParameters{
+			MessageGroupId: aws.String(messageGroupID),
+		}
+	}
+
+	return t
+}
+
+// NewStepFunctionsTarget creates a target starting an execution of state machine with input.
+func NewStepFunctionsTarget(stateMachineArn, roleArn, input string) *ScheduleTarget {
+	return NewTarget(stateMachineArn, roleArn, input)
+}
+
+// NewEcsTarget creates a target running task definition of params on cluster,
+// input is passed as task overrides.
+func NewEcsTarget(clusterArn, roleArn string, params types.EcsParameters, input string) *ScheduleTarget {
+	t := NewTarget(clusterArn, roleArn, input)
+	t.target.EcsParameters = &params
+
+	return t
+}
+
+// WithRetry retries a failed invocation up to maxAttempts (0-185) times,
+// within maxEventAge (1m-24h) since the scheduled time, zero maxEventAge keeps the default.
+func (t *ScheduleTarget) WithRetry(maxAttempts int32, maxEventAge time.Duration) *ScheduleTarget {
+	t.target.RetryPolicy = &types.RetryPolicy{
+		MaximumRetryAttempts: aws.Int32(maxAttempts),
+	}
+
+	if maxEventAge > 0 {
+		t.target.RetryPolicy.MaximumEventAgeInSeconds = aws.Int32(int32(maxEventAge.Seconds()))
+	}
+
+	return t
+}
+
+// WithDLQ sends events failed after all retries to the sqs queue dlqArn.
+func (t *ScheduleTarget) WithDLQ(dlqArn string) *ScheduleTarget {
+	t.target.DeadLetterConfig = &types.DeadLetterConfig{
+		Arn: aws.String(dlqArn),
+	}
+
+	return t
+}
+
+// Arn returns the arn of target.
+func (t *ScheduleTarget) Arn() string {
+	return aws.ToString(t.target.Arn)
+}
+
+func newTarget(targetArn, roleArn, jsonStr string) *types.Target {
+	return &types.Target{
+		Arn:     aws.String(targetArn),
+		RoleArn: aws.String(roleArn),
+		Input:   aws.String(jsonStr),
+		RetryPolicy: &types.RetryPolicy{
+			MaximumRetryAttempts: aws.Int32(0),
+		},
+	}
+}
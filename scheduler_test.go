@@ -0,0 +1,98 @@
+package xaws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerSuite struct {
+	suite.Suite
+}
+
+func TestScheduler(t *testing.T) {
+	suite.Run(t, new(SchedulerSuite))
+}
+
+func (s *SchedulerSuite) Test_01_sameScheduleDates() {
+	start := time.Date(2024, 1, 1, 8, 0, 0, 123456789, time.UTC)
+	spec := ScheduleRate("1 hour").Between(start, time.Time{})
+	target := NewTarget("arn:aws:lambda:us-east-1:123:function:f", "role", "{}")
+
+	existing := &scheduler.GetScheduleOutput{
+		State:              types.ScheduleStateEnabled,
+		ScheduleExpression: aws.String(spec.Expression),
+		StartDate:          aws.Time(start.Truncate(time.Second)),
+		Target: &types.Target{
+			Arn:     aws.String("arn:aws:lambda:us-east-1:123:function:f"),
+			RoleArn: aws.String("role"),
+			Input:   aws.String("{}"),
+		},
+	}
+
+	s.True(sameSchedule(existing, spec, target), "the service keeps dates in seconds")
+
+	existing.StartDate = aws.Time(start.Add(time.Second))
+	s.False(sameSchedule(existing, spec, target))
+}
+
+func (s *SchedulerSuite) Test_02_sameScheduleEcsDefaults() {
+	spec := ScheduleCron("0 8 * * ? *")
+	target := NewEcsTarget("cluster", "role", types.EcsParameters{
+		TaskDefinitionArn: aws.String("task:1"),
+	}, "{}")
+
+	existing := &scheduler.GetScheduleOutput{
+		State:              types.ScheduleStateEnabled,
+		ScheduleExpression: aws.String(spec.Expression),
+		Target: &types.Target{
+			Arn:     aws.String("cluster"),
+			RoleArn: aws.String("role"),
+			Input:   aws.String("{}"),
+			EcsParameters: &types.EcsParameters{
+				TaskDefinitionArn: aws.String("task:1"),
+				TaskCount:         aws.Int32(1),
+				LaunchType:        types.LaunchTypeFargate,
+			},
+		},
+	}
+
+	s.True(sameSchedule(existing, spec, target), "fields filled by the service are ignored")
+
+	existing.Target.EcsParameters.TaskDefinitionArn = aws.String("task:2")
+	s.False(sameSchedule(existing, spec, target))
+}
+
+func (s *SchedulerSuite) Test_03_scheduleAtTimezone() {
+	ny, err := time.LoadLocation("America/New_York")
+	s.Require().Nil(err)
+
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, ny)
+	spec := ScheduleAt(at)
+	s.Equal("at(2024-05-01T09:00:00)", spec.Expression)
+	s.Equal("America/New_York", spec.Timezone)
+
+	tests := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{time.FixedZone("UTC+8", 8*3600), "at(2024-05-01T01:00:00)"},
+		// named like an IANA zone, with another offset.
+		{time.FixedZone("EST", 8*3600), "at(2024-05-01T01:00:00)"},
+		{time.Local, at.UTC().Format("at(2006-01-02T15:04:05)")},
+	}
+
+	for _, tt := range tests {
+		spec := ScheduleAt(time.Date(2024, 5, 1, 9, 0, 0, 0, tt.loc))
+		if tt.loc == time.Local {
+			spec = ScheduleAt(at.In(time.Local))
+		}
+
+		s.Equal(tt.want, spec.Expression, tt.loc.String())
+		s.Equal("UTC", spec.Timezone, tt.loc.String())
+	}
+}
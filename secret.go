@@ -1,18 +1,22 @@
 package xaws
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
 
+var ErrEmptySecret = errors.New("secret has no value")
+
 type Auth struct {
 	AwsAccessKeyID     string `json:"aws_access_key_id"`
 	AwsSecretAccessKey string `json:"aws_secret_access_key"`
+	// Region is optional, the region of default config is used if empty.
+	Region string `json:"region,omitempty"`
+	// RoleArn is optional, the role is assumed with ak/sk if set.
+	RoleArn string `json:"role_arn,omitempty"`
 }
 
 func SecretToAuth(raw string) (*Auth, error) {
@@ -26,8 +30,8 @@ func SecretToAuth(raw string) (*Auth, error) {
 	return auth, nil
 }
 
-func MustGetSecretWithDefault(secretName string) string {
-	str, err := GetSecretWithDefault(secretName)
+func MustGetSecretWithDefault(secretName string, opts ...SecretOptFunc) string {
+	str, err := GetSecretWithDefault(secretName, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -35,17 +39,17 @@ func MustGetSecretWithDefault(secretName string) string {
 	return str
 }
 
-func GetSecretWithDefault(secretName string) (string, error) {
-	config, err := config.LoadDefaultConfig(context.TODO())
+func GetSecretWithDefault(secretName string, opts ...SecretOptFunc) (string, error) {
+	config, err := loadDefaultConfig()
 	if err != nil {
 		return "", fmt.Errorf("cannot load config: %w", err)
 	}
 
-	return getSecret(config, secretName)
+	return getSecret(config, secretName, opts...)
 }
 
-func MustGetSecret(ak, sk, secretName string) string {
-	str, err := GetSecretByAkSk(ak, sk, secretName)
+func MustGetSecret(ak, sk, secretName string, opts ...SecretOptFunc) string {
+	str, err := GetSecretByAkSk(ak, sk, secretName, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -53,30 +57,49 @@ func MustGetSecret(ak, sk, secretName string) string {
 	return str
 }
 
-func GetSecretByAkSk(ak, sk, secretName string) (string, error) {
+func GetSecretByAkSk(ak, sk, secretName string, opts ...SecretOptFunc) (string, error) {
 	config, err := NewAwsConfig(ak, sk, "")
 	if err != nil {
 		return "", fmt.Errorf("cannot load config: %w", err)
 	}
 
-	return getSecret(config, secretName)
+	return getSecret(config, secretName, opts...)
 }
 
-func getSecret(config aws.Config, secretName string) (string, error) {
-	// Create Secrets Manager client
-	svc := secretsmanager.NewFromConfig(config)
+// GetSecretJSON loads secret with default config, and unmarshals its JSON value into out.
+func GetSecretJSON(secretName string, out any, opts ...SecretOptFunc) error {
+	raw, err := GetSecretBinary(secretName, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("cannot unmarshal secret %s: %w", secretName, err)
+	}
+
+	return nil
+}
 
-	input := &secretsmanager.GetSecretValueInput{
-		SecretId:     aws.String(secretName),
-		VersionStage: aws.String("AWSCURRENT"), // VersionStage defaults to AWSCURRENT if unspecified
+// GetSecretBinary loads secret with default config, and returns SecretBinary of the secret,
+// or SecretString as bytes if it is a string secret.
+func GetSecretBinary(secretName string, opts ...SecretOptFunc) ([]byte, error) {
+	config, err := loadDefaultConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load config: %w", err)
 	}
 
-	result, err := svc.GetSecretValue(context.TODO(), input)
+	return getSecretBytes(config, secretName, opts...)
+}
+
+func getSecret(config aws.Config, secretName string, opts ...SecretOptFunc) (string, error) {
+	raw, err := getSecretBytes(config, secretName, opts...)
 	if err != nil {
-		// For a list of exceptions thrown, see
-		// https://docs.aws.amazon.com/secretsmanager/latest/apireference/API_GetSecretValue.html
-		return "", fmt.Errorf("cannot get secret: %w", err)
+		return "", err
 	}
 
-	return *result.SecretString, nil
+	return string(raw), nil
+}
+
+func getSecretBytes(config aws.Config, secretName string, opts ...SecretOptFunc) ([]byte, error) {
+	return NewSecretsWrapper(config).GetSecretBinary(secretName, opts...)
 }
@@ -0,0 +1,216 @@
+package xaws
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	_defaultSecretTTL = 5 * time.Minute
+	// _secretRetryDivisor sets the first retry delay of a failed refresh to ttl / 10,
+	// it doubles on each failure up to ttl.
+	_secretRetryDivisor = 10
+)
+
+// SecretCache caches secret values in process, it is safe for concurrent use.
+//
+// A value older than ttl is still returned while it is refreshed in the background,
+// so only the first Get of a secret waits for Secrets Manager, concurrent first Gets share one fetch.
+// A failed refresh is retried with backoff, starting at ttl/10, so a throttled or unavailable
+// Secrets Manager isn't called on every Get.
+//
+// Usage:
+//
+//	cache := NewSecretCache(cfg, 10*time.Minute)
+//	raw, err := cache.Get("prod/db")
+type SecretCache struct {
+	ttl   time.Duration
+	fetch func(secretName string) (string, error)
+
+	mu      sync.RWMutex
+	entries map[string]*secretEntry
+	// loading holds the fetch in flight of each secret not cached yet.
+	loading map[string]*secretLoad
+
+	logger Logger
+}
+
+type secretEntry struct {
+	value      string
+	fetchedAt  time.Time
+	refreshing bool
+
+	// failures counts the refreshes failed in a row, no refresh starts before retryAt.
+	failures int
+	retryAt  time.Time
+}
+
+type secretLoad struct {
+	done  chan struct{}
+	value string
+	err   error
+}
+
+// NewSecretCache creates a cache loading secrets with cfg, ttl <= 0 uses 5 minutes.
+func NewSecretCache(cfg aws.Config, ttl time.Duration, opts ...ClientOptFunc) *SecretCache {
+	if ttl <= 0 {
+		ttl = _defaultSecretTTL
+	}
+
+	opt := newClientOpts(opts...)
+	cfg = opt.apply(cfg)
+
+	return &SecretCache{
+		ttl: ttl,
+		fetch: func(secretName string) (string, error) {
+			return getSecret(cfg, secretName)
+		},
+		entries: make(map[string]*secretEntry),
+		loading: make(map[string]*secretLoad),
+		logger:  opt.logger,
+	}
+}
+
+// NewSecretCacheWithDefault creates a cache loading secrets with the default config.
+func NewSecretCacheWithDefault(ttl time.Duration, opts ...ClientOptFunc) (*SecretCache, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load config: %w", err)
+	}
+
+	return NewSecretCache(cfg, ttl, opts...), nil
+}
+
+// Get returns the cached value of secretName, it is loaded synchronously on first use,
+// and refreshed in the background once older than ttl.
+func (c *SecretCache) Get(secretName string) (string, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[secretName]
+
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		defer c.mu.RUnlock()
+		return entry.value, nil
+	}
+
+	c.mu.RUnlock()
+
+	if !ok {
+		return c.load(secretName)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !entry.refreshing && !time.Now().Before(entry.retryAt) {
+		entry.refreshing = true
+
+		go c.refreshInBackground(secretName)
+	}
+
+	return entry.value, nil
+}
+
+// MustGet is Get, but panics on error.
+func (c *SecretCache) MustGet(secretName string) string {
+	value, err := c.Get(secretName)
+	panicIfErr(err)
+
+	return value
+}
+
+// Refresh loads secretName now and caches it.
+func (c *SecretCache) Refresh(secretName string) (string, error) {
+	value, err := c.fetch(secretName)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[secretName] = &secretEntry{value: value, fetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// load fetches secretName on a cache miss, callers missing the same secret at the same time wait for one fetch.
+func (c *SecretCache) load(secretName string) (string, error) {
+	c.mu.Lock()
+
+	if entry, ok := c.entries[secretName]; ok {
+		c.mu.Unlock()
+		return entry.value, nil
+	}
+
+	if l, ok := c.loading[secretName]; ok {
+		c.mu.Unlock()
+		<-l.done
+
+		return l.value, l.err
+	}
+
+	l := &secretLoad{done: make(chan struct{})}
+	c.loading[secretName] = l
+	c.mu.Unlock()
+
+	l.value, l.err = c.Refresh(secretName)
+
+	c.mu.Lock()
+	delete(c.loading, secretName)
+	c.mu.Unlock()
+
+	close(l.done)
+
+	return l.value, l.err
+}
+
+// Invalidate drops the cached value of secretName, the next Get loads it again.
+func (c *SecretCache) Invalidate(secretName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, secretName)
+}
+
+// InvalidateAll drops all cached values.
+func (c *SecretCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*secretEntry)
+}
+
+func (c *SecretCache) refreshInBackground(secretName string) {
+	if _, err := c.Refresh(secretName); err != nil {
+		c.log().Warn("cannot refresh secret, keep the stale value", "secret", secretName, "error", err)
+
+		c.mu.Lock()
+		if entry, ok := c.entries[secretName]; ok {
+			entry.refreshing = false
+			entry.failures++
+			entry.retryAt = time.Now().Add(c.retryDelay(entry.failures))
+		}
+		c.mu.Unlock()
+	}
+}
+
+// retryDelay returns the delay before the next refresh after failures refreshes failed in a row.
+func (c *SecretCache) retryDelay(failures int) time.Duration {
+	delay := c.ttl / _secretRetryDivisor
+	for i := 1; i < failures && delay < c.ttl; i++ {
+		delay *= 2
+	}
+
+	return min(delay, c.ttl)
+}
+
+// SetLogger sets the logger of cache, nil falls back to the default logger.
+func (c *SecretCache) SetLogger(l Logger) {
+	c.logger = l
+}
+
+func (c *SecretCache) log() Logger {
+	return orDefaultLogger(c.logger)
+}
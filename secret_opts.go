@@ -0,0 +1,34 @@
+package xaws
+
+const (
+	SecretStageCurrent  = "AWSCURRENT"
+	SecretStagePrevious = "AWSPREVIOUS"
+	SecretStagePending  = "AWSPENDING"
+)
+
+type SecretOpts struct {
+	versionID    string
+	versionStage string
+}
+
+type SecretOptFunc func(o *SecretOpts)
+
+func bindSecretOpts(opt *SecretOpts, opts ...SecretOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithVersionID gets the secret version with id, it takes precedence over WithVersionStage.
+func WithVersionID(id string) SecretOptFunc {
+	return func(o *SecretOpts) {
+		o.versionID = id
+	}
+}
+
+// WithVersionStage gets the secret version with staging label, e.g. SecretStagePrevious.
+func WithVersionStage(stage string) SecretOptFunc {
+	return func(o *SecretOpts) {
+		o.versionStage = stage
+	}
+}
@@ -1,9 +1,15 @@
 package xaws
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/k0kubun/pp/v3"
 	"github.com/stretchr/testify/suite"
 )
@@ -32,3 +38,131 @@ func (s *SecretSuite) Test_01() {
 
 	pp.Println(auth)
 }
+
+func (s *SecretSuite) Test_02_cache() {
+	var calls atomic.Int32
+
+	cache := NewSecretCache(aws.Config{}, time.Hour)
+	cache.fetch = func(secretName string) (string, error) {
+		calls.Add(1)
+		return secretName + "-value", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		v, err := cache.Get("a")
+		s.Nil(err)
+		s.Equal("a-value", v)
+	}
+
+	s.Equal(int32(1), calls.Load())
+
+	cache.Invalidate("a")
+
+	_, err := cache.Get("a")
+	s.Nil(err)
+	s.Equal(int32(2), calls.Load())
+}
+
+func (s *SecretSuite) Test_03_configWithSecret() {
+	cfg, err := newConfigWithSecret(`{"aws_access_key_id":"ak","aws_secret_access_key":"sk","region":"eu-west-1"}`)
+	s.Nil(err)
+	s.Equal("eu-west-1", cfg.Region)
+
+	creds, err := cfg.Credentials.Retrieve(context.TODO())
+	s.Nil(err)
+	s.Equal("ak", creds.AccessKeyID)
+
+	cfg, err = newConfigWithSecret(`{"aws_access_key_id":"ak","aws_secret_access_key":"sk","region":"eu-west-1","role_arn":"arn:aws:iam::123456789012:role/r"}`)
+	s.Nil(err)
+	s.Equal("eu-west-1", cfg.Region)
+
+	_, err = newConfigWithSecret(`not json`)
+	s.NotNil(err)
+}
+
+func (s *SecretSuite) Test_04_cacheColdMissFetchesOnce() {
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+
+	cache := NewSecretCache(aws.Config{}, time.Hour)
+	cache.fetch = func(secretName string) (string, error) {
+		calls.Add(1)
+		<-release
+
+		return secretName + "-value", nil
+	}
+
+	var wg sync.WaitGroup
+
+	values := make([]string, 10)
+
+	for i := range values {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			v, err := cache.Get("a")
+			s.Nil(err)
+
+			values[i] = v
+		}(i)
+	}
+
+	// let the callers reach the fetch in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	s.Equal(int32(1), calls.Load())
+
+	for _, v := range values {
+		s.Equal("a-value", v)
+	}
+}
+
+func (s *SecretSuite) Test_05_cacheRefreshBackoff() {
+	var calls atomic.Int32
+
+	cache := NewSecretCache(aws.Config{}, time.Second)
+	cache.fetch = func(string) (string, error) {
+		calls.Add(1)
+		return "", errors.New("ThrottlingException")
+	}
+	cache.entries["a"] = &secretEntry{value: "stale", fetchedAt: time.Now().Add(-2 * time.Second)}
+
+	refreshed := func() bool {
+		cache.mu.RLock()
+		defer cache.mu.RUnlock()
+
+		return !cache.entries["a"].refreshing
+	}
+
+	for i := 0; i < 20; i++ {
+		v, err := cache.Get("a")
+		s.Nil(err)
+		s.Equal("stale", v)
+	}
+
+	s.Eventually(refreshed, time.Second, 5*time.Millisecond)
+	s.Equal(int32(1), calls.Load())
+
+	// within the backoff window of ttl/10, no refresh is started.
+	for i := 0; i < 20; i++ {
+		_, _ = cache.Get("a")
+	}
+
+	s.Equal(int32(1), calls.Load())
+
+	cache.mu.Lock()
+	s.Equal(1, cache.entries["a"].failures)
+	cache.entries["a"].retryAt = time.Now().Add(-time.Millisecond)
+	cache.mu.Unlock()
+
+	_, _ = cache.Get("a")
+	s.Eventually(refreshed, time.Second, 5*time.Millisecond)
+	s.Equal(int32(2), calls.Load())
+	s.Equal(200*time.Millisecond, cache.retryDelay(2))
+	s.Equal(time.Second, cache.retryDelay(10))
+}
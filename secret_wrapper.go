@@ -0,0 +1,115 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// SecretsWrapper works on Secrets Manager with an injected config,
+// e.g. an assumed-role config, its methods mirror the package-level secret functions.
+type SecretsWrapper struct {
+	client *secretsmanager.Client
+	cfg    aws.Config
+
+	logger Logger
+}
+
+func NewSecretsWrapper(cfg aws.Config, opts ...ClientOptFunc) *SecretsWrapper {
+	opt := newClientOpts(opts...)
+	cfg = opt.apply(cfg)
+
+	return &SecretsWrapper{
+		client: secretsmanager.NewFromConfig(cfg),
+		cfg:    cfg,
+		logger: opt.logger,
+	}
+}
+
+func NewSecretsWrapperWithDefaultConfig(opts ...ClientOptFunc) (*SecretsWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load config: %w", err)
+	}
+
+	return NewSecretsWrapper(cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *SecretsWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+// GetSecret returns SecretString of secret, or SecretBinary as string if it is a binary secret.
+func (w *SecretsWrapper) GetSecret(secretName string, opts ...SecretOptFunc) (string, error) {
+	raw, err := w.GetSecretBinary(secretName, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+func (w *SecretsWrapper) MustGetSecret(secretName string, opts ...SecretOptFunc) string {
+	str, err := w.GetSecret(secretName, opts...)
+	panicIfErr(err)
+
+	return str
+}
+
+// GetSecretJSON unmarshals the JSON value of secret into out.
+func (w *SecretsWrapper) GetSecretJSON(secretName string, out any, opts ...SecretOptFunc) error {
+	raw, err := w.GetSecretBinary(secretName, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("cannot unmarshal secret %s: %w", secretName, err)
+	}
+
+	return nil
+}
+
+// GetSecretBinary returns SecretBinary of secret, or SecretString as bytes if it is a string secret.
+func (w *SecretsWrapper) GetSecretBinary(secretName string, opts ...SecretOptFunc) ([]byte, error) {
+	opt := &SecretOpts{versionStage: SecretStageCurrent}
+	bindSecretOpts(opt, opts...)
+
+	input := &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretName),
+	}
+
+	if opt.versionID != "" {
+		input.VersionId = aws.String(opt.versionID)
+	} else {
+		input.VersionStage = aws.String(opt.versionStage)
+	}
+
+	result, err := w.client.GetSecretValue(context.TODO(), input)
+	if err != nil {
+		// For a list of exceptions thrown, see
+		// https://docs.aws.amazon.com/secretsmanager/latest/apireference/API_GetSecretValue.html
+		return nil, fmt.Errorf("cannot get secret: %w", err)
+	}
+
+	// the sdk has already decoded the base64 of SecretBinary.
+	if result.SecretString == nil {
+		if result.SecretBinary == nil {
+			return nil, fmt.Errorf("%w: %s", ErrEmptySecret, secretName)
+		}
+
+		return result.SecretBinary, nil
+	}
+
+	return []byte(*result.SecretString), nil
+}
+
+// NewCache creates a SecretCache sharing the config and logger of wrapper.
+func (w *SecretsWrapper) NewCache(ttl time.Duration) *SecretCache {
+	return NewSecretCache(w.cfg, ttl, WithWrapperLogger(w.logger))
+}
@@ -0,0 +1,207 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+const (
+	// _minRecoveryDays and _maxRecoveryDays bound the recovery window of DeleteSecret.
+	_minRecoveryDays = 7
+	_maxRecoveryDays = 30
+)
+
+// CreateSecret creates secret name with value as SecretString, and returns its arn.
+func (w *SecretsWrapper) CreateSecret(name, value, description string) (string, error) {
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(name),
+		SecretString: aws.String(value),
+	}
+
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	output, err := w.client.CreateSecret(context.TODO(), input)
+	if err != nil {
+		return "", fmt.Errorf("cannot create secret: %w", err)
+	}
+
+	return aws.ToString(output.ARN), nil
+}
+
+// PutSecretValue stores value as a new version of secret name, the new version becomes AWSCURRENT.
+// Returns the version id.
+func (w *SecretsWrapper) PutSecretValue(name, value string) (string, error) {
+	output, err := w.client.PutSecretValue(context.TODO(), &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(name),
+		SecretString: aws.String(value),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot put secret value: %w", err)
+	}
+
+	return aws.ToString(output.VersionId), nil
+}
+
+// UpdateSecret updates details of secret name with input, e.g. Description or KmsKeyId,
+// SecretId of input is always set to name.
+func (w *SecretsWrapper) UpdateSecret(name string, input *secretsmanager.UpdateSecretInput) error {
+	input.SecretId = aws.String(name)
+
+	if _, err := w.client.UpdateSecret(context.TODO(), input); err != nil {
+		return fmt.Errorf("cannot update secret: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSecret schedules secret name for deletion after recoveryDays (7-30),
+// 0 deletes it immediately without recovery.
+func (w *SecretsWrapper) DeleteSecret(name string, recoveryDays int) error {
+	input := &secretsmanager.DeleteSecretInput{
+		SecretId: aws.String(name),
+	}
+
+	switch {
+	case recoveryDays == 0:
+		input.ForceDeleteWithoutRecovery = aws.Bool(true)
+	case recoveryDays < _minRecoveryDays || recoveryDays > _maxRecoveryDays:
+		return fmt.Errorf("recovery window must be 0 or between %d and %d days, got %d", _minRecoveryDays, _maxRecoveryDays, recoveryDays)
+	default:
+		input.RecoveryWindowInDays = aws.Int64(int64(recoveryDays))
+	}
+
+	if _, err := w.client.DeleteSecret(context.TODO(), input); err != nil {
+		return fmt.Errorf("cannot delete secret: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecret starts an immediate rotation of secret name with its configured rotation function.
+func (w *SecretsWrapper) RotateSecret(name string) error {
+	if _, err := w.client.RotateSecret(context.TODO(), &secretsmanager.RotateSecretInput{
+		SecretId: aws.String(name),
+	}); err != nil {
+		return fmt.Errorf("cannot rotate secret: %w", err)
+	}
+
+	return nil
+}
+
+// TagSecret adds or overwrites tags of secret name.
+func (w *SecretsWrapper) TagSecret(name string, tags map[string]string) error {
+	secretTags := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		secretTags = append(secretTags, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	if _, err := w.client.TagResource(context.TODO(), &secretsmanager.TagResourceInput{
+		SecretId: aws.String(name),
+		Tags:     secretTags,
+	}); err != nil {
+		return fmt.Errorf("cannot tag secret: %w", err)
+	}
+
+	return nil
+}
+
+// ListSecretVersions lists all versions of secret name, including the ones without staging labels.
+func (w *SecretsWrapper) ListSecretVersions(name string) ([]types.SecretVersionsListEntry, error) {
+	var versions []types.SecretVersionsListEntry
+
+	paginator := secretsmanager.NewListSecretVersionIdsPaginator(w.client, &secretsmanager.ListSecretVersionIdsInput{
+		SecretId:          aws.String(name),
+		IncludeDeprecated: aws.Bool(true),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return versions, fmt.Errorf("cannot list secret versions: %w", err)
+		}
+
+		versions = append(versions, page.Versions...)
+	}
+
+	return versions, nil
+}
+
+// PromoteSecretVersion moves AWSCURRENT of secret name to versionID, the former current version
+// becomes AWSPREVIOUS, it is the way to roll back a rotation.
+func (w *SecretsWrapper) PromoteSecretVersion(name, versionID string) error {
+	versions, err := w.ListSecretVersions(name)
+	if err != nil {
+		return err
+	}
+
+	input := &secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:        aws.String(name),
+		VersionStage:    aws.String(SecretStageCurrent),
+		MoveToVersionId: aws.String(versionID),
+	}
+
+	for _, v := range versions {
+		if slices.Contains(v.VersionStages, SecretStageCurrent) {
+			if aws.ToString(v.VersionId) == versionID {
+				return nil
+			}
+
+			input.RemoveFromVersionId = v.VersionId
+
+			break
+		}
+	}
+
+	if _, err := w.client.UpdateSecretVersionStage(context.TODO(), input); err != nil {
+		return fmt.Errorf("cannot promote secret version: %w", err)
+	}
+
+	return nil
+}
+
+// CreateSecret creates secret name with config, see SecretsWrapper.CreateSecret.
+func CreateSecret(config aws.Config, name, value, description string) (string, error) {
+	return NewSecretsWrapper(config).CreateSecret(name, value, description)
+}
+
+// PutSecretValue stores a new version of secret name with config, see SecretsWrapper.PutSecretValue.
+func PutSecretValue(config aws.Config, name, value string) (string, error) {
+	return NewSecretsWrapper(config).PutSecretValue(name, value)
+}
+
+// UpdateSecret updates secret name with config, see SecretsWrapper.UpdateSecret.
+func UpdateSecret(config aws.Config, name string, input *secretsmanager.UpdateSecretInput) error {
+	return NewSecretsWrapper(config).UpdateSecret(name, input)
+}
+
+// DeleteSecret deletes secret name with config, see SecretsWrapper.DeleteSecret.
+func DeleteSecret(config aws.Config, name string, recoveryDays int) error {
+	return NewSecretsWrapper(config).DeleteSecret(name, recoveryDays)
+}
+
+// RotateSecret rotates secret name with config, see SecretsWrapper.RotateSecret.
+func RotateSecret(config aws.Config, name string) error {
+	return NewSecretsWrapper(config).RotateSecret(name)
+}
+
+// TagSecret tags secret name with config, see SecretsWrapper.TagSecret.
+func TagSecret(config aws.Config, name string, tags map[string]string) error {
+	return NewSecretsWrapper(config).TagSecret(name, tags)
+}
+
+// ListSecretVersions lists versions of secret name with config, see SecretsWrapper.ListSecretVersions.
+func ListSecretVersions(config aws.Config, name string) ([]types.SecretVersionsListEntry, error) {
+	return NewSecretsWrapper(config).ListSecretVersions(name)
+}
+
+// PromoteSecretVersion promotes versionID of secret name with config, see SecretsWrapper.PromoteSecretVersion.
+func PromoteSecretVersion(config aws.Config, name, versionID string) error {
+	return NewSecretsWrapper(config).PromoteSecretVersion(name, versionID)
+}
@@ -0,0 +1,397 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+const (
+	// _publishBatchSize is the max entries of a PublishBatch call.
+	_publishBatchSize = 10
+)
+
+// Subscription protocols of Subscribe.
+const (
+	SnsProtocolSQS    = "sqs"
+	SnsProtocolLambda = "lambda"
+	SnsProtocolEmail  = "email"
+	SnsProtocolHTTPS  = "https"
+)
+
+var ErrPublishBatchFailed = errors.New("failed to publish messages")
+
+type SnsWrapper struct {
+	client *sns.Client
+
+	logger Logger
+}
+
+func NewSnsWrapper(cfg aws.Config, opts ...ClientOptFunc) *SnsWrapper {
+	opt := newClientOpts(opts...)
+
+	return &SnsWrapper{
+		client: sns.NewFromConfig(opt.apply(cfg)),
+		logger: opt.logger,
+	}
+}
+
+func NewSnsWrapperWithDefaultConfig(opts ...ClientOptFunc) (*SnsWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSnsWrapper(cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *SnsWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *SnsWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// CreateTopic creates topic name and returns its arn, it is idempotent,
+// a name ending with ".fifo" creates a FIFO topic, see WithContentBasedDedup.
+func (w *SnsWrapper) CreateTopic(name string, opts ...SnsOptFunc) (string, error) {
+	opt := &SnsOpts{}
+	bindSnsOpts(opt, opts...)
+
+	attrs := map[string]string{}
+
+	if strings.HasSuffix(name, ".fifo") {
+		attrs["FifoTopic"] = "true"
+
+		if opt.contentBasedDedup {
+			attrs["ContentBasedDeduplication"] = "true"
+		}
+	}
+
+	output, err := w.client.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name:       aws.String(name),
+		Attributes: attrs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot create topic %s: %w", name, err)
+	}
+
+	w.log().Debug("topic created", "topic", name)
+
+	return aws.ToString(output.TopicArn), nil
+}
+
+// DeleteTopic deletes topic topicArn and all its subscriptions.
+func (w *SnsWrapper) DeleteTopic(topicArn string) error {
+	_, err := w.client.DeleteTopic(context.TODO(), &sns.DeleteTopicInput{
+		TopicArn: aws.String(topicArn),
+	})
+
+	return err
+}
+
+// ListTopics returns arns of all topics.
+func (w *SnsWrapper) ListTopics() ([]string, error) {
+	var arns []string
+
+	paginator := sns.NewListTopicsPaginator(w.client, &sns.ListTopicsInput{})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return arns, fmt.Errorf("cannot list topics: %w", err)
+		}
+
+		for _, t := range output.Topics {
+			arns = append(arns, aws.ToString(t.TopicArn))
+		}
+	}
+
+	return arns, nil
+}
+
+// Publish publishes message to topic topicArn and returns the message id.
+//
+// Usage:
+//
+//	id, err := w.Publish(arn, body,
+//		WithMessageAttributes(map[string]any{"event": "created"}),
+//		WithMessageGroupID(orderID),
+//	)
+func (w *SnsWrapper) Publish(topicArn, message string, opts ...SnsOptFunc) (string, error) {
+	opt := &SnsOpts{}
+	bindSnsOpts(opt, opts...)
+
+	attrs, err := toSnsAttributes(opt.attributes)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := w.client.Publish(context.TODO(), &sns.PublishInput{
+		TopicArn:               aws.String(topicArn),
+		Message:                aws.String(message),
+		Subject:                optionalString(opt.subject),
+		MessageAttributes:      attrs,
+		MessageGroupId:         optionalString(opt.groupID),
+		MessageDeduplicationId: optionalString(opt.deduplicationID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot publish to %s: %w", topicArn, err)
+	}
+
+	return aws.ToString(output.MessageId), nil
+}
+
+// SnsMessage is a message of PublishBatch, GroupID and DeduplicationID are for FIFO topics.
+type SnsMessage struct {
+	Message    string
+	Subject    string
+	Attributes map[string]any
+
+	GroupID         string
+	DeduplicationID string
+}
+
+// PublishBatch publishes messages to topic topicArn in batches of 10, returns the number of messages accepted,
+// entries rejected by SNS are reported in the returned error.
+func (w *SnsWrapper) PublishBatch(topicArn string, messages []SnsMessage) (int, error) {
+	entries := make([]types.PublishBatchRequestEntry, 0, len(messages))
+
+	for i, m := range messages {
+		attrs, err := toSnsAttributes(m.Attributes)
+		if err != nil {
+			return 0, fmt.Errorf("message %d: %w", i, err)
+		}
+
+		entries = append(entries, types.PublishBatchRequestEntry{
+			Id:                     aws.String(strconv.Itoa(i)),
+			Message:                aws.String(m.Message),
+			Subject:                optionalString(m.Subject),
+			MessageAttributes:      attrs,
+			MessageGroupId:         optionalString(m.GroupID),
+			MessageDeduplicationId: optionalString(m.DeduplicationID),
+		})
+	}
+
+	sent := 0
+
+	var errs []error
+
+	for start := 0; start < len(entries); start += _publishBatchSize {
+		end := min(start+_publishBatchSize, len(entries))
+
+		output, err := w.client.PublishBatch(context.TODO(), &sns.PublishBatchInput{
+			TopicArn:                   aws.String(topicArn),
+			PublishBatchRequestEntries: entries[start:end],
+		})
+		if err != nil {
+			return sent, err
+		}
+
+		sent += len(output.Successful)
+
+		for _, failed := range output.Failed {
+			errs = append(errs, fmt.Errorf("%w: message %s: %s %s",
+				ErrPublishBatchFailed, aws.ToString(failed.Id), aws.ToString(failed.Code), aws.ToString(failed.Message)))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// Subscribe subscribes endpoint to topic topicArn and returns the subscription arn,
+// protocol is one of SnsProtocolSQS / SnsProtocolLambda / SnsProtocolEmail / SnsProtocolHTTPS.
+//
+// An email or https subscription is pending until confirmed,
+// a Lambda function must allow PrincipalSNS to invoke it, see FunctionWrapper.AddPermission,
+// and a queue must allow the topic to send messages, see SubscribeQueue.
+func (w *SnsWrapper) Subscribe(topicArn, protocol, endpoint string, opts ...SnsOptFunc) (string, error) {
+	opt := &SnsOpts{}
+	bindSnsOpts(opt, opts...)
+
+	attrs := map[string]string{}
+
+	if opt.rawDelivery {
+		attrs["RawMessageDelivery"] = "true"
+	}
+
+	if opt.filterPolicy != nil {
+		policy, err := marshalPolicy(opt.filterPolicy)
+		if err != nil {
+			return "", fmt.Errorf("cannot marshal filter policy: %w", err)
+		}
+
+		attrs["FilterPolicy"] = policy
+	}
+
+	output, err := w.client.Subscribe(context.TODO(), &sns.SubscribeInput{
+		TopicArn:              aws.String(topicArn),
+		Protocol:              aws.String(protocol),
+		Endpoint:              aws.String(endpoint),
+		Attributes:            attrs,
+		ReturnSubscriptionArn: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot subscribe %s to %s: %w", endpoint, topicArn, err)
+	}
+
+	return aws.ToString(output.SubscriptionArn), nil
+}
+
+// Unsubscribe deletes subscription subscriptionArn.
+func (w *SnsWrapper) Unsubscribe(subscriptionArn string) error {
+	_, err := w.client.Unsubscribe(context.TODO(), &sns.UnsubscribeInput{
+		SubscriptionArn: aws.String(subscriptionArn),
+	})
+
+	return err
+}
+
+// ListSubscriptions returns all subscriptions of topic topicArn.
+func (w *SnsWrapper) ListSubscriptions(topicArn string) ([]types.Subscription, error) {
+	var subs []types.Subscription
+
+	paginator := sns.NewListSubscriptionsByTopicPaginator(w.client, &sns.ListSubscriptionsByTopicInput{
+		TopicArn: aws.String(topicArn),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return subs, fmt.Errorf("cannot list subscriptions of %s: %w", topicArn, err)
+		}
+
+		subs = append(subs, output.Subscriptions...)
+	}
+
+	return subs, nil
+}
+
+// SubscribeQueue subscribes queue to topic topicArn, the queue policy is extended
+// to allow the topic to send messages, then returns the subscription arn.
+//
+// Usage:
+//
+//	subArn, err := w.SubscribeQueue(topicArn, queue, WithRawDelivery())
+func (w *SnsWrapper) SubscribeQueue(topicArn string, queue *SqsClient, opts ...SnsOptFunc) (string, error) {
+	queueArn, err := queue.GetQueueArn()
+	if err != nil {
+		return "", err
+	}
+
+	if err := allowTopicToQueue(queue, queueArn, topicArn); err != nil {
+		return "", err
+	}
+
+	return w.Subscribe(topicArn, SnsProtocolSQS, queueArn, opts...)
+}
+
+type queuePolicy struct {
+	Version   string           `json:"Version"`
+	Statement []map[string]any `json:"Statement"`
+}
+
+// allowTopicToQueue adds a statement allowing topicArn to send messages to the queue, if not yet added.
+func allowTopicToQueue(queue *SqsClient, queueArn, topicArn string) error {
+	attrs, err := queue.GetQueueAttributes("Policy")
+	if err != nil {
+		return err
+	}
+
+	policy := queuePolicy{Version: "2012-10-17"}
+
+	if raw := attrs["Policy"]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+			return fmt.Errorf("cannot parse policy of queue %s: %w", queue.QueueName, err)
+		}
+	}
+
+	topic, err := ParseARN(topicArn)
+	if err != nil {
+		return err
+	}
+
+	sid := "xaws-sns-" + topic.Resource
+
+	for _, stmt := range policy.Statement {
+		if stmt["Sid"] == sid {
+			return nil
+		}
+	}
+
+	policy.Statement = append(policy.Statement, map[string]any{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueArn,
+		"Condition": map[string]any{
+			"ArnEquals": map[string]string{"aws:SourceArn": topicArn},
+		},
+	})
+
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	return queue.SetQueueAttributes(map[string]string{"Policy": string(raw)})
+}
+
+func toSnsAttributes(m map[string]any) (map[string]types.MessageAttributeValue, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	attrs := make(map[string]types.MessageAttributeValue, len(m))
+
+	for name, value := range m {
+		switch v := value.(type) {
+		case string:
+			attrs[name] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(v)}
+		case int, int32, int64, uint, uint32, uint64, float32, float64:
+			attrs[name] = types.MessageAttributeValue{DataType: aws.String("Number"), StringValue: aws.String(fmt.Sprint(v))}
+		case []string:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+
+			attrs[name] = types.MessageAttributeValue{DataType: aws.String("String.Array"), StringValue: aws.String(string(raw))}
+		case []byte:
+			attrs[name] = types.MessageAttributeValue{DataType: aws.String("Binary"), BinaryValue: v}
+		default:
+			return nil, fmt.Errorf("unsupported type %T of message attribute %s", value, name)
+		}
+	}
+
+	return attrs, nil
+}
+
+// marshalPolicy marshals policy to JSON, string and []byte are returned as is.
+func marshalPolicy(policy any) (string, error) {
+	switch p := policy.(type) {
+	case string:
+		return p, nil
+	case []byte:
+		return string(p), nil
+	default:
+		raw, err := json.Marshal(p)
+		return string(raw), err
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return aws.String(s)
+}
@@ -0,0 +1,112 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// FanoutQueue is a queue subscribed to the topic of Fanout.
+type FanoutQueue struct {
+	Name string
+	// FilterPolicy is optional, see WithFilterPolicy.
+	FilterPolicy any
+	// RawDelivery delivers the published message as is instead of the SNS JSON envelope.
+	RawDelivery bool
+}
+
+// Fanout is a topic with subscribed queues, every message published to the topic
+// is delivered to each queue whose filter policy matches.
+type Fanout struct {
+	Sns      *SnsWrapper
+	TopicArn string
+
+	// Queues are clients of the subscribed queues, by queue name.
+	Queues map[string]*SqsClient
+	// Subscriptions are subscription arns, by queue name.
+	Subscriptions map[string]string
+}
+
+// NewFanout creates topic and queues if not exist, and subscribes each queue to the topic,
+// it is safe to call on every start. FIFO topics are not supported.
+//
+// Usage:
+//
+//	fan, err := NewFanout(cfg, "orders", []FanoutQueue{
+//		{Name: "orders-billing", RawDelivery: true},
+//		{Name: "orders-shipping", RawDelivery: true, FilterPolicy: map[string]any{"event": []string{"paid"}}},
+//	}, 10)
+//
+//	fan.Publish(body, WithMessageAttributes(map[string]any{"event": "paid"}))
+//	msgs, err := fan.Queue("orders-shipping").GetMsgs()
+func NewFanout(cfg aws.Config, topic string, queues []FanoutQueue, batchSize int, opts ...ClientOptFunc) (*Fanout, error) {
+	w := NewSnsWrapper(cfg, opts...)
+
+	topicArn, err := w.CreateTopic(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	fan := &Fanout{
+		Sns:           w,
+		TopicArn:      topicArn,
+		Queues:        make(map[string]*SqsClient, len(queues)),
+		Subscriptions: make(map[string]string, len(queues)),
+	}
+
+	for _, q := range queues {
+		client := NewSqsClient(q.Name, cfg, batchSize, _defaultTimeoutSecs, opts...)
+
+		if _, err := client.CreateQueue(q.Name); err != nil {
+			return fan, fmt.Errorf("cannot create queue %s: %w", q.Name, err)
+		}
+
+		var opts []SnsOptFunc
+
+		if q.RawDelivery {
+			opts = append(opts, WithRawDelivery())
+		}
+
+		if q.FilterPolicy != nil {
+			opts = append(opts, WithFilterPolicy(q.FilterPolicy))
+		}
+
+		subArn, err := w.SubscribeQueue(topicArn, client, opts...)
+		if err != nil {
+			return fan, err
+		}
+
+		fan.Queues[q.Name] = client
+		fan.Subscriptions[q.Name] = subArn
+	}
+
+	return fan, nil
+}
+
+// Queue returns the client of queue name, nil if it is not in the fanout.
+func (f *Fanout) Queue(name string) *SqsClient {
+	return f.Queues[name]
+}
+
+// Publish publishes message to the topic, see SnsWrapper.Publish.
+func (f *Fanout) Publish(message string, opts ...SnsOptFunc) (string, error) {
+	return f.Sns.Publish(f.TopicArn, message, opts...)
+}
+
+// Teardown deletes the topic with its subscriptions, and the queues.
+func (f *Fanout) Teardown() error {
+	var errs []error
+
+	if err := f.Sns.DeleteTopic(f.TopicArn); err != nil {
+		errs = append(errs, fmt.Errorf("cannot delete topic %s: %w", f.TopicArn, err))
+	}
+
+	for name, client := range f.Queues {
+		if err := client.DeleteQueue(name); err != nil {
+			errs = append(errs, fmt.Errorf("cannot delete queue %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
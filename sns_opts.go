@@ -0,0 +1,77 @@
+package xaws
+
+type SnsOpts struct {
+	subject    string
+	attributes map[string]any
+
+	groupID         string
+	deduplicationID string
+
+	contentBasedDedup bool
+
+	rawDelivery  bool
+	filterPolicy any
+}
+
+type SnsOptFunc func(o *SnsOpts)
+
+func bindSnsOpts(opt *SnsOpts, opts ...SnsOptFunc) {
+	for _, f := range opts {
+		f(opt)
+	}
+}
+
+// WithSubject sets the subject of message, used by email subscriptions.
+func WithSubject(s string) SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.subject = s
+	}
+}
+
+// WithMessageAttributes sets message attributes, string values are sent as String,
+// numbers as Number and []string as String.Array, subscriptions filter on them.
+func WithMessageAttributes(m map[string]any) SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.attributes = m
+	}
+}
+
+// WithMessageGroupID sets the message group id, required by FIFO topics.
+func WithMessageGroupID(s string) SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.groupID = s
+	}
+}
+
+// WithDeduplicationID sets the deduplication id of message published to a FIFO topic.
+func WithDeduplicationID(s string) SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.deduplicationID = s
+	}
+}
+
+// WithContentBasedDedup enables content-based deduplication when creating a FIFO topic.
+func WithContentBasedDedup() SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.contentBasedDedup = true
+	}
+}
+
+// WithRawDelivery delivers the raw message instead of the SNS JSON envelope to SQS / HTTPS subscriptions.
+func WithRawDelivery() SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.rawDelivery = true
+	}
+}
+
+// WithFilterPolicy sets the filter policy on message attributes of subscription,
+// policy is marshaled to JSON, string and []byte are sent as is.
+//
+// Usage:
+//
+//	WithFilterPolicy(map[string]any{"event": []string{"created", "updated"}})
+func WithFilterPolicy(policy any) SnsOptFunc {
+	return func(o *SnsOpts) {
+		o.filterPolicy = policy
+	}
+}
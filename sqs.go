@@ -7,18 +7,22 @@ import (
 	"time"
 	"unicode/utf8"
 
-	"github.com/avast/retry-go"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
-	"github.com/coghost/xpretty"
-	"github.com/rs/zerolog/log"
 	"github.com/spf13/cast"
 )
 
 const (
 	MaxBatchSize = 10
+	// MaxDelay is the longest delay of a message supported by SQS.
+	MaxDelay = 15 * time.Minute
+
+	// _maxMessageSize is the largest message accepted by SQS, and the largest payload of a batch.
+	_maxMessageSize = 256 * 1024
+
+	// _defaultEmptyReceives is the number of consecutive empty receives after which ReadMessages stops.
+	_defaultEmptyReceives = 2
 )
 
 var (
@@ -28,11 +32,14 @@ var (
 	ErrQueueNameMismatch = errors.New("queue name does not match the one set during initialization")
 	ErrMessageEmpty      = errors.New("message is empty")
 	ErrSendBatchFailed   = errors.New("failed to send some messages in batch")
+	ErrDelayTooLong      = errors.New("delay exceeds 15 minutes")
 )
 
 type SqsClient struct {
 	Config aws.Config
 	Client *sqs.Client
+	// sdk is the client calls are made with, Client or the SqsSdkClient given to NewSqsClientWithClient.
+	sdk    SqsSdkClient
 	awsCtx context.Context
 	// upload timeout
 	Timeout int
@@ -42,31 +49,72 @@ type SqsClient struct {
 
 	batchSize int
 	SendCache []string
+
+	compressMinSize int
+	envelopeSource  string
+
+	validator MessageValidator
+
+	counters          sqsCounters
+	slowSendThreshold time.Duration
+	emitter           *MetricEmitter
+
+	logger Logger
 }
 
-func NewSqsClient(queue string, cfg aws.Config, batchSize int, timeout int) *SqsClient {
+func NewSqsClient(queue string, cfg aws.Config, batchSize int, timeout int, opts ...ClientOptFunc) *SqsClient {
+	wrapper := NewSqsClientWithClient(queue, sqs.NewFromConfig(applyClientOpts(cfg, opts...)), batchSize, timeout, opts...)
+	wrapper.Config = cfg
+
+	return wrapper
+}
+
+// NewSqsClientWithClient creates wrapper with client, e.g. a mocked SqsSdkClient,
+// Client is only set if client is an *sqs.Client, only the logger of opts is used.
+func NewSqsClientWithClient(queue string, client SqsSdkClient, batchSize int, timeout int, opts ...ClientOptFunc) *SqsClient {
+	concrete, _ := client.(*sqs.Client)
+
 	wrapper := &SqsClient{
-		Config:    cfg,
-		Client:    sqs.NewFromConfig(cfg),
+		Client:    concrete,
+		sdk:       client,
 		awsCtx:    context.TODO(),
 		QueueName: queue,
 		batchSize: batchSize,
 		// timeout
 		Timeout: timeout,
+		logger:  newClientOpts(opts...).logger,
 	}
 	wrapper.SetQueueURL(wrapper.QueueName)
-	xpretty.DummyLog("connected to queue:", wrapper.QueueName)
+	wrapper.log().Debug("connected to queue", "queue", wrapper.QueueName)
 
 	return wrapper
 }
 
-func NewSqsClientWithDefaultConfig(queue string, batchSize int) (*SqsClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *SqsClient) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *SqsClient) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// api returns the client calls are made with, Client if the wrapper is not created by a constructor.
+func (w *SqsClient) api() SqsSdkClient {
+	if w.sdk != nil {
+		return w.sdk
+	}
+
+	return w.Client
+}
+
+func NewSqsClientWithDefaultConfig(queue string, batchSize int, opts ...ClientOptFunc) (*SqsClient, error) {
+	cfg, err := loadDefaultConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewSqsClient(queue, cfg, batchSize, _defaultTimeoutSecs), nil
+	return NewSqsClient(queue, cfg, batchSize, _defaultTimeoutSecs, opts...), nil
 }
 
 func (w *SqsClient) SetQueueURL(name string) {
@@ -78,23 +126,21 @@ func (w *SqsClient) SetQueueURL(name string) {
 	w.QueueURL, _ = w.GetQueueURL(w.QueueName)
 }
 
-func (w *SqsClient) CreateQueue(name string) (string, error) {
-	output, err := w.Client.CreateQueue(w.awsCtx, &sqs.CreateQueueInput{
-		QueueName: &name,
-		Attributes: map[string]string{
-			"DelaySeconds":           "0",
-			"MessageRetentionPeriod": "86400",
-		},
-	})
-
-	w.SetQueueURL(name)
+// CreateQueue creates queue name and points the wrapper to it, returns the queue url.
+// By default it's a standard queue keeping messages for 1 day, it can be changed by
+// WithFifoQueue, WithDeadLetterQueue, WithSqsManagedSSE, WithKmsKey, WithVisibilityTimeout and WithRetentionPeriod.
+func (w *SqsClient) CreateQueue(name string, opts ...SqsOptFunc) (string, error) {
+	info, err := w.CreateQueueWithInfo(name, opts...)
+	if err != nil {
+		return "", err
+	}
 
-	return *output.QueueUrl, err
+	return info.URL, nil
 }
 
 // PurgeQueue removes all messages from the queue
 func (w *SqsClient) PurgeQueue() error {
-	_, err := w.Client.PurgeQueue(w.awsCtx, &sqs.PurgeQueueInput{
+	_, err := w.api().PurgeQueue(w.awsCtx, &sqs.PurgeQueueInput{
 		QueueUrl: &w.QueueURL,
 	})
 
@@ -138,7 +184,7 @@ func (w *SqsClient) DeleteQueue(name string) error {
 		return ErrQueueNameMismatch
 	}
 
-	_, err := w.Client.DeleteQueue(
+	_, err := w.api().DeleteQueue(
 		w.awsCtx,
 		&sqs.DeleteQueueInput{
 			QueueUrl: &url,
@@ -150,7 +196,7 @@ func (w *SqsClient) DeleteQueue(name string) error {
 
 // GetQueues returns a list of queue names
 func (w *SqsClient) GetQueues() (*sqs.ListQueuesOutput, error) {
-	result, err := w.Client.ListQueues(w.awsCtx, nil)
+	result, err := w.api().ListQueues(w.awsCtx, nil)
 	return result, err
 }
 
@@ -164,7 +210,7 @@ func (w *SqsClient) GetQueues() (*sqs.ListQueuesOutput, error) {
 //	If success, the URL of the queue and nil
 //	Otherwise, an empty string and an error from the call to
 func (w *SqsClient) GetQueueURL(name string) (string, error) {
-	res, err := w.Client.GetQueueUrl(w.awsCtx, &sqs.GetQueueUrlInput{QueueName: &name})
+	res, err := w.api().GetQueueUrl(w.awsCtx, &sqs.GetQueueUrlInput{QueueName: &name})
 	if err == nil {
 		return *res.QueueUrl, nil
 	}
@@ -196,6 +242,7 @@ func (w *SqsClient) MustGetQueueURL(name string) string {
 //   - There's a network issue preventing communication with SQS.
 //   - The SQS service returns an error (e.g., invalid queue URL, permissions issues).
 //   - The message body exceeds the maximum size limit for SQS messages (256 KB).
+//   - The message is rejected by the validator set by SetValidator (*ValidationError).
 //
 // Example usage:
 //
@@ -211,30 +258,84 @@ func (w *SqsClient) MustGetQueueURL(name string) string {
 // This method is not thread-safe. If you need to send messages concurrently,
 // consider using separate SqsClient instances or implement your own synchronization.
 func (w *SqsClient) SendMsg(message string) (*sqs.SendMessageOutput, error) {
+	if err := w.checkMessage(message); err != nil {
+		return nil, err
+	}
+
+	return w.sendMsg(context.Background(), message, 0)
+}
+
+// SendMsgContext is SendMsg sending with ctx, in envelope mode (see SetEnvelope) the correlation id
+// and the trace context of ctx are propagated to the envelope.
+func (w *SqsClient) SendMsgContext(ctx context.Context, message string) (*sqs.SendMessageOutput, error) {
+	if err := w.checkMessage(message); err != nil {
+		return nil, err
+	}
+
+	return w.sendMsg(ctx, message, 0)
+}
+
+// SendMsgWithDelay sends message which becomes visible after delay, rounded up to seconds,
+// it is checked like SendMsg, delay longer than 15 minutes fails with ErrDelayTooLong, see MessageScheduler.
+func (w *SqsClient) SendMsgWithDelay(message string, delay time.Duration) (*sqs.SendMessageOutput, error) {
+	if delay > MaxDelay {
+		return nil, fmt.Errorf("%w: %s", ErrDelayTooLong, delay)
+	}
+
+	if err := w.checkMessage(message); err != nil {
+		return nil, err
+	}
+
+	return w.sendMsg(context.Background(), message, int32((max(delay, 0)+time.Second-1)/time.Second))
+}
+
+// checkMessage returns the error of an invalid message, which SQS would reject, or rejected by the validator,
+// the size is checked by encodeBody since it depends on compression.
+func (w *SqsClient) checkMessage(message string) error {
 	if message == "" {
-		return nil, ErrEmptyMessageBody
+		return ErrEmptyMessageBody
 	}
 
 	if !utf8.ValidString(message) {
-		return nil, ErrInvalidUTF8
+		return ErrInvalidUTF8
+	}
+
+	return w.validate(message)
+}
+
+func (w *SqsClient) sendMsg(ctx context.Context, message string, delaySeconds int32, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	message, err := w.wrapEnvelope(ctx, message)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(message) > 256*1024 {
-		return nil, ErrMessageTooLong
+	body, attrs, err := w.encodeBody(message)
+	if err != nil {
+		return nil, err
 	}
 
-	ctx, cancelFn := context.WithTimeout(context.Background(), time.Duration(w.Timeout)*time.Second)
+	ctx, cancelFn := context.WithTimeout(ctx, time.Duration(w.Timeout)*time.Second)
 	if cancelFn != nil {
 		defer cancelFn()
 	}
 
-	res, err := w.Client.SendMessage(
+	start := time.Now()
+
+	res, err := w.api().SendMessage(
 		ctx,
 		&sqs.SendMessageInput{
-			MessageBody: aws.String(message),
-			QueueUrl:    &w.QueueURL,
+			MessageBody:       aws.String(body),
+			MessageAttributes: attrs,
+			QueueUrl:          &w.QueueURL,
+			DelaySeconds:      delaySeconds,
 		},
+		optFns...,
 	)
+	if err != nil {
+		w.recordSend(time.Since(start), 0, 1, false)
+	} else {
+		w.recordSend(time.Since(start), 1, 0, false)
+	}
 
 	return res, err
 }
@@ -250,27 +351,37 @@ func (w *SqsClient) MustSendMsg(message string) *sqs.SendMessageOutput {
 //
 // Parameters:
 //   - message: The message to be sent.
-//   - retries: The number of retry attempts.
+//   - retries: The number of attempts, every error but validation errors is retried with
+//     DefaultRetryPolicy's backoff instead of the sdk retryer of the client.
 //
 // Returns:
 //   - *sqs.SendMessageOutput: The response from SQS if successful.
 //   - error: An error if all retry attempts fail, nil otherwise.
 func (w *SqsClient) SendMsgWithRetry(message string, retries uint) (*sqs.SendMessageOutput, error) {
-	var (
-		output *sqs.SendMessageOutput
-		err    error
-	)
+	if err := w.checkMessage(message); err != nil {
+		return nil, err
+	}
 
-	retryErr := retry.Do(
-		func() error {
-			output, err = w.SendMsg(message)
-			return err
-		},
-		retry.Attempts(retries),
-	)
+	policy := DefaultRetryPolicy
+	policy.MaxAttempts = max(int(retries), 1)
+	policy.Retryable = func(error) bool { return true }
+
+	var output *sqs.SendMessageOutput
+
+	err := policy.Do(context.Background(), func(attempt int) error {
+		if attempt > 1 {
+			w.counters.retries.Add(1)
+		}
+
+		var err error
+		output, err = w.sendMsg(context.Background(), message, 0, func(o *sqs.Options) {
+			o.Retryer = aws.NopRetryer{}
+		})
 
-	if retryErr != nil {
-		return nil, fmt.Errorf("failed to send message after %d attempts: %w", retries, retryErr)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send message after %d attempts: %w", retries, err)
 	}
 
 	return output, nil
@@ -331,14 +442,14 @@ func (w *SqsClient) SendManyMessages(messages []string) (int, error) {
 //
 // The method will return an error if:
 //   - The messages slice is empty (ErrMessageEmpty).
+//   - Any message is empty, invalid UTF-8, too long or rejected by the validator set by SetValidator (*ValidationError).
 //   - There's a network issue preventing communication with SQS.
 //   - The SQS service returns an error (e.g., invalid queue URL, permissions issues).
 //
 // Note:
-//   - The maximum number of messages in a batch is 10. If more than 10 messages are provided,
-//     only the first 10 will be sent.
-//   - Each message in the batch can be up to 256 KB in size.
-//   - The total size of all messages in the batch cannot exceed the SQS maximum batch size (256 KB).
+//   - Every message is checked like SendMsg, an invalid message fails the call with its index and nothing is sent.
+//   - SQS accepts at most 10 messages and 256 KB in a request, larger batches are split into several requests
+//     whose results are merged, entry ids are the 1-based positions in messages.
 //   - If some messages in the batch fail to send, the method will not return an error. Check the
 //     Failed field of the output to identify any messages that were not sent successfully.
 //
@@ -362,24 +473,112 @@ func (w *SqsClient) SendMsgBatch(messages []string) (*sqs.SendMessageBatchOutput
 		return nil, ErrMessageEmpty
 	}
 
-	var entries []types.SendMessageBatchRequestEntry
+	for i, message := range messages {
+		if message == "" {
+			return nil, fmt.Errorf("%w: message #%d", ErrEmptyMessageBody, i)
+		}
+
+		if !utf8.ValidString(message) {
+			return nil, fmt.Errorf("%w: message #%d", ErrInvalidUTF8, i)
+		}
+	}
+
+	if err := w.validate(messages...); err != nil {
+		return nil, err
+	}
+
+	entries := make([]types.SendMessageBatchRequestEntry, 0, len(messages))
 
 	for i, message := range messages {
+		message, err := w.wrapEnvelope(context.Background(), message)
+		if err != nil {
+			return nil, fmt.Errorf("%w: message #%d", err, i)
+		}
+
+		body, attrs, err := w.encodeBody(message)
+		if err != nil {
+			return nil, fmt.Errorf("%w: message #%d", err, i)
+		}
+
 		et := types.SendMessageBatchRequestEntry{
-			Id:          aws.String(fmt.Sprintf("%d", i+1)),
-			MessageBody: aws.String(message),
+			Id:                aws.String(fmt.Sprintf("%d", i+1)),
+			MessageBody:       aws.String(body),
+			MessageAttributes: attrs,
 		}
 		entries = append(entries, et)
 	}
 
-	res, err := w.Client.SendMessageBatch(
-		w.awsCtx,
-		&sqs.SendMessageBatchInput{
-			Entries:  entries,
-			QueueUrl: &w.QueueURL,
-		})
+	merged := &sqs.SendMessageBatchOutput{}
 
-	return res, err
+	for _, chunk := range splitBatch(entries) {
+		start := time.Now()
+
+		res, err := w.api().SendMessageBatch(
+			w.awsCtx,
+			&sqs.SendMessageBatchInput{
+				Entries:  chunk,
+				QueueUrl: &w.QueueURL,
+			})
+		if err != nil {
+			w.recordSend(time.Since(start), 0, len(chunk), true)
+
+			if len(merged.Successful) == 0 {
+				return nil, err
+			}
+
+			// messages of previous requests are sent, report those of this one and the rest as failed.
+			for _, e := range entries[len(merged.Successful)+len(merged.Failed):] {
+				merged.Failed = append(merged.Failed, types.BatchResultErrorEntry{Id: e.Id, Message: aws.String(err.Error())})
+			}
+
+			return merged, nil
+		}
+
+		w.recordSend(time.Since(start), len(res.Successful), len(res.Failed), true)
+
+		merged.Successful = append(merged.Successful, res.Successful...)
+		merged.Failed = append(merged.Failed, res.Failed...)
+		merged.ResultMetadata = res.ResultMetadata
+	}
+
+	return merged, nil
+}
+
+// splitBatch splits entries into requests of at most MaxBatchSize entries and _maxMessageSize bytes.
+func splitBatch(entries []types.SendMessageBatchRequestEntry) [][]types.SendMessageBatchRequestEntry {
+	var (
+		chunks [][]types.SendMessageBatchRequestEntry
+		chunk  []types.SendMessageBatchRequestEntry
+		size   int
+	)
+
+	for _, e := range entries {
+		n := batchEntrySize(e)
+		if len(chunk) == MaxBatchSize || (len(chunk) > 0 && size+n > _maxMessageSize) {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+
+		chunk = append(chunk, e)
+		size += n
+	}
+
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// batchEntrySize returns the size SQS counts for e, the body and its message attributes.
+func batchEntrySize(e types.SendMessageBatchRequestEntry) int {
+	n := len(aws.ToString(e.MessageBody))
+
+	for name, attr := range e.MessageAttributes {
+		n += len(name) + len(aws.ToString(attr.DataType)) + len(aws.ToString(attr.StringValue)) + len(attr.BinaryValue)
+	}
+
+	return n
 }
 
 // GetMsgs retrieves multiple messages from the SQS queue.
@@ -424,13 +623,26 @@ func (w *SqsClient) GetMsgs(opts ...SqsOptFunc) (*sqs.ReceiveMessageOutput, erro
 	opt := SqsOpts{waitTimeSeconds: _waitTimeSeconds, batchSize: w.batchSize}
 	bindSqsOpts(&opt, opts...)
 
-	return w.Client.ReceiveMessage(
+	out, err := w.api().ReceiveMessage(
 		w.awsCtx,
 		&sqs.ReceiveMessageInput{
-			QueueUrl:            &w.QueueURL,
-			MaxNumberOfMessages: int32(opt.batchSize),
-			WaitTimeSeconds:     int32(opt.waitTimeSeconds),
+			QueueUrl:              &w.QueueURL,
+			MaxNumberOfMessages:   int32(opt.batchSize),
+			WaitTimeSeconds:       int32(opt.waitTimeSeconds),
+			VisibilityTimeout:     int32(opt.peek / time.Second),
+			MessageAttributeNames: []string{_sqsEncodingAttr},
 		})
+	if err != nil {
+		return out, err
+	}
+
+	for i := range out.Messages {
+		if err := decodeMessage(&out.Messages[i]); err != nil {
+			w.log().Error("cannot decode message, body is kept as is", "id", aws.ToString(out.Messages[i].MessageId), "error", err)
+		}
+	}
+
+	return out, nil
 }
 
 // GetMsg retrieves a single message from the SQS queue.
@@ -518,6 +730,37 @@ func (s SqsReadStatus) String() string {
 type SqsResp struct {
 	Status SqsReadStatus
 	Msg    *string
+
+	// claim is the idempotency claim of Msg with WithIdempotency.
+	claim *idempotencyClaim
+}
+
+// idempotencyClaim is a key claimed in store for a message being processed.
+type idempotencyClaim struct {
+	store   *IdempotencyStore
+	key     string
+	payload []byte
+}
+
+// Done ends the processing of Msg read with WithIdempotency, it does nothing without WithIdempotency.
+// A nil err completes the idempotency key, so the message is deleted when redelivered,
+// otherwise the key is released so the message is processed again when redelivered.
+// Without Done, the claim expires after the lease of the store.
+func (r *SqsResp) Done(err error) error {
+	return r.claim.done(err)
+}
+
+// done completes the claim if err is nil, or releases it, a nil claim does nothing.
+func (c *idempotencyClaim) done(err error) error {
+	if c == nil {
+		return nil
+	}
+
+	if err != nil {
+		return c.store.Delete(c.key)
+	}
+
+	return c.store.Complete(c.key, c.payload)
 }
 
 func NewSqsResp(msg *string, status SqsReadStatus) *SqsResp {
@@ -528,45 +771,97 @@ func (w *SqsClient) GoReadMessages(ch chan *SqsResp, opts ...SqsOptFunc) {
 	go w.ReadMessages(ch, opts...)
 }
 
+// ReadMessages sends the messages of the queue to chanResp, with SqsReadSuccess, until:
+//   - WithMax(n) messages are sent, then SqsReadMaximumReached is sent.
+//   - WithEmptyReceives(n) consecutive long polling receives got no message (2 by default), then SqsReadAllConsumed is sent.
+//   - a receive fails, then SqsReadError is sent.
+//
+// Receives wait WaitTimeSeconds (10 by default) and get BatchSize messages like GetMsgs.
+// The approximate number of messages is not used, it lags and counts in-flight messages.
 func (w *SqsClient) ReadMessages(chanResp chan *SqsResp, opts ...SqsOptFunc) {
-	opt := SqsOpts{max: 0}
+	opt := SqsOpts{max: 0, emptyReceives: _defaultEmptyReceives}
 	bindSqsOpts(&opt, opts...)
 
-	failed := NewSqsResp(nil, SqsReadError)
+	got, empty := 0, 0
 
-	got := 0
-OUTER:
 	for {
-		remained, err := w.GetRemainedItems()
-		log.Debug().Err(err).Int64("remain", remained).Msg("remained messages")
-
+		msgs, err := w.GetMsgs(opts...)
 		if err != nil {
-			chanResp <- failed
-		}
+			w.log().Error("cannot receive messages", "queue", w.QueueName, "error", err)
+			chanResp <- NewSqsResp(nil, SqsReadError)
 
-		if remained == 0 {
-			chanResp <- NewSqsResp(nil, SqsReadAllConsumed)
-			break
+			return
 		}
 
-		msgs, err := w.GetMsgs()
-		if err != nil {
-			chanResp <- failed
+		if len(msgs.Messages) == 0 {
+			if empty++; empty >= opt.emptyReceives {
+				chanResp <- NewSqsResp(nil, SqsReadAllConsumed)
+				return
+			}
+
+			w.log().Debug("empty receive", "queue", w.QueueName, "empty", empty)
+
+			continue
 		}
 
+		empty = 0
+
 		for _, msg := range msgs.Messages {
-			chanResp <- NewSqsResp(msg.Body, SqsReadSuccess)
+			resp := NewSqsResp(msg.Body, SqsReadSuccess)
+
+			if opt.idempotency != nil {
+				if resp.claim = w.claimMessage(msg, &opt); resp.claim == nil {
+					continue
+				}
+			}
+
+			chanResp <- resp
 			got += 1
 			if opt.max != 0 && got >= opt.max {
 				chanResp <- NewSqsResp(nil, SqsReadMaximumReached)
-				break OUTER
+				return
 			}
 		}
 	}
 }
 
+// claimMessage claims msg in the idempotency store, a processed msg is deleted from the queue,
+// a msg being processed is left to be redelivered. Returns nil if msg should not be processed.
+func (w *SqsClient) claimMessage(msg types.Message, opt *SqsOpts) *idempotencyClaim {
+	claim := &idempotencyClaim{
+		store:   opt.idempotency,
+		key:     opt.idempotencyKey(msg),
+		payload: []byte(aws.ToString(msg.Body)),
+	}
+
+	key := claim.key
+
+	err := claim.store.Claim(key, claim.payload)
+	if err == nil {
+		return claim
+	}
+
+	if errors.Is(err, ErrInProgress) {
+		w.log().Info("skip message being processed", "key", key)
+		return nil
+	}
+
+	if !errors.Is(err, ErrAlreadyProcessed) {
+		w.log().Error("cannot record message", "key", key, "error", err)
+		return nil
+	}
+
+	w.log().Info("skip processed message", "key", key)
+
+	if _, err := w.DeleteMsg(msg.ReceiptHandle); err != nil {
+		w.log().Warn("cannot delete processed message", "key", key, "error", err)
+	}
+
+	return nil
+}
+
 func (w *SqsClient) DeleteMsg(handle *string) (*sqs.DeleteMessageOutput, error) {
-	return w.Client.DeleteMessage(
+	return w.api().DeleteMessage(
 		w.awsCtx,
 		&sqs.DeleteMessageInput{
 			QueueUrl:      &w.QueueURL,
@@ -593,7 +888,7 @@ func (w *SqsClient) GetRemainedItems(opts ...SqsOptFunc) (int64, error) {
 	}
 
 	attr := types.QueueAttributeNameApproximateNumberOfMessages
-	res, err := w.Client.GetQueueAttributes(w.awsCtx,
+	res, err := w.api().GetQueueAttributes(w.awsCtx,
 		&sqs.GetQueueAttributesInput{
 			QueueUrl:       &qurl,
 			AttributeNames: []types.QueueAttributeName{attr},
@@ -604,6 +899,46 @@ func (w *SqsClient) GetRemainedItems(opts ...SqsOptFunc) (int64, error) {
 	return n, err
 }
 
+// GetQueueAttributes returns attributes names of the queue, all attributes if names is empty.
+func (w *SqsClient) GetQueueAttributes(names ...types.QueueAttributeName) (map[string]string, error) {
+	if len(names) == 0 {
+		names = []types.QueueAttributeName{types.QueueAttributeNameAll}
+	}
+
+	res, err := w.api().GetQueueAttributes(w.awsCtx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       &w.QueueURL,
+		AttributeNames: names,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get attributes of queue %s: %w", w.QueueName, err)
+	}
+
+	return res.Attributes, nil
+}
+
+// SetQueueAttributes sets attributes of the queue, e.g. "Policy" or "VisibilityTimeout".
+func (w *SqsClient) SetQueueAttributes(attrs map[string]string) error {
+	_, err := w.api().SetQueueAttributes(w.awsCtx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   &w.QueueURL,
+		Attributes: attrs,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set attributes of queue %s: %w", w.QueueName, err)
+	}
+
+	return nil
+}
+
+// GetQueueArn returns the arn of the queue.
+func (w *SqsClient) GetQueueArn() (string, error) {
+	attrs, err := w.GetQueueAttributes(types.QueueAttributeNameQueueArn)
+	if err != nil {
+		return "", err
+	}
+
+	return attrs[string(types.QueueAttributeNameQueueArn)], nil
+}
+
 func ChunkSlice(slice []string, chunkSize int) [][]string {
 	var chunks [][]string
 
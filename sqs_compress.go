@@ -0,0 +1,99 @@
+package xaws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// _sqsEncodingAttr is the message attribute marking an encoded body.
+	_sqsEncodingAttr   = "xaws-encoding"
+	_sqsEncodingGzip   = "gzip+base64"
+	_sqsAttrTypeString = "String"
+)
+
+// SetCompression gzips and base64 encodes bodies of at least minSize bytes before they are sent,
+// when it makes them smaller, 0 disables compression.
+// Encoded messages are marked with the "xaws-encoding" attribute, and decoded by GetMsgs
+// and the readers built on it, whether compression is enabled or not.
+//
+// Usage:
+//
+//	w.SetCompression(8 * 1024)
+//	_, err := w.SendMsg(largeJSON)
+func (w *SqsClient) SetCompression(minSize int) {
+	w.compressMinSize = minSize
+}
+
+// encodeBody returns the body and attributes to send message with, ErrMessageTooLong if body exceeds 256KB.
+func (w *SqsClient) encodeBody(message string) (string, map[string]types.MessageAttributeValue, error) {
+	if w.compressMinSize <= 0 || len(message) < w.compressMinSize {
+		return encodeRaw(message)
+	}
+
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write([]byte(message)); err != nil {
+		return "", nil, err
+	}
+
+	if err := gw.Close(); err != nil {
+		return "", nil, err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+	if len(encoded) >= len(message) {
+		return encodeRaw(message)
+	}
+
+	if len(encoded) > _maxMessageSize {
+		return "", nil, ErrMessageTooLong
+	}
+
+	return encoded, map[string]types.MessageAttributeValue{
+		_sqsEncodingAttr: {DataType: aws.String(_sqsAttrTypeString), StringValue: aws.String(_sqsEncodingGzip)},
+	}, nil
+}
+
+func encodeRaw(message string) (string, map[string]types.MessageAttributeValue, error) {
+	if len(message) > _maxMessageSize {
+		return "", nil, ErrMessageTooLong
+	}
+
+	return message, nil, nil
+}
+
+// decodeMessage replaces the body of msg with its decoded content if it is marked by _sqsEncodingAttr.
+func decodeMessage(msg *types.Message) error {
+	attr, ok := msg.MessageAttributes[_sqsEncodingAttr]
+	if !ok || aws.ToString(attr.StringValue) != _sqsEncodingGzip {
+		return nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(aws.ToString(msg.Body))
+	if err != nil {
+		return err
+	}
+
+	gr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	body, err := io.ReadAll(gr)
+	if err != nil {
+		return err
+	}
+
+	msg.Body = aws.String(string(body))
+	delete(msg.MessageAttributes, _sqsEncodingAttr)
+
+	return nil
+}
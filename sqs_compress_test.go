@@ -0,0 +1,87 @@
+package xaws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// loopbackSqsSdk receives the messages it was sent, with their attributes.
+type loopbackSqsSdk struct {
+	SqsSdkClient
+
+	sent []types.Message
+}
+
+func (c *loopbackSqsSdk) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	c.sent = append(c.sent, types.Message{Body: in.MessageBody, MessageAttributes: in.MessageAttributes, MessageId: aws.String("id")})
+	return &sqs.SendMessageOutput{MessageId: aws.String("id")}, nil
+}
+
+func (c *loopbackSqsSdk) ReceiveMessage(_ context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	out := &sqs.ReceiveMessageOutput{Messages: c.sent}
+	c.sent = nil
+
+	return out, nil
+}
+
+type CompressSuite struct {
+	suite.Suite
+}
+
+func TestCompress(t *testing.T) {
+	suite.Run(t, new(CompressSuite))
+}
+
+func (s *CompressSuite) Test_01_roundTrip() {
+	sdk := &loopbackSqsSdk{}
+	w := &SqsClient{sdk: sdk, QueueName: "q", Timeout: 5}
+	w.SetCompression(1024)
+
+	large := `{"items":[` + strings.Repeat(`{"name":"item","price":1},`, 20000) + `{}]}`
+	s.Greater(len(large), _maxMessageSize)
+
+	_, err := w.SendMsg(large)
+	s.Nil(err)
+	_, err = w.SendMsg(`{"small":true}`)
+	s.Nil(err)
+
+	s.Less(len(aws.ToString(sdk.sent[0].Body)), _maxMessageSize)
+	s.Contains(sdk.sent[0].MessageAttributes, _sqsEncodingAttr)
+	s.NotContains(sdk.sent[1].MessageAttributes, _sqsEncodingAttr)
+
+	out, err := w.GetMsgs()
+	s.Nil(err)
+	s.Len(out.Messages, 2)
+	s.Equal(large, aws.ToString(out.Messages[0].Body))
+	s.Equal(`{"small":true}`, aws.ToString(out.Messages[1].Body))
+}
+
+func (s *CompressSuite) Test_02_tooLong() {
+	w := &SqsClient{sdk: &loopbackSqsSdk{}, QueueName: "q", Timeout: 5}
+
+	_, err := w.SendMsg(strings.Repeat("a", _maxMessageSize+1))
+	s.ErrorIs(err, ErrMessageTooLong)
+
+	// incompressible bodies are sent raw, and still limited.
+	w.SetCompression(1)
+	_, err = w.SendMsg(randSeq(_maxMessageSize + 1))
+	s.ErrorIs(err, ErrMessageTooLong)
+}
+
+func (s *CompressSuite) Test_03_decodeInvalid() {
+	msg := types.Message{
+		Body: aws.String("not base64!"),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			_sqsEncodingAttr: {DataType: aws.String(_sqsAttrTypeString), StringValue: aws.String(_sqsEncodingGzip)},
+		},
+	}
+
+	s.NotNil(decodeMessage(&msg))
+	s.Equal("not base64!", aws.ToString(msg.Body))
+}
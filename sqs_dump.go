@@ -0,0 +1,231 @@
+package xaws
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	// _dumpPartSize is the number of messages of each object written by DumpQueueToS3.
+	_dumpPartSize = 1000
+	// _dumpDrainVisibility hides the messages received by a draining DumpQueueToS3 until they're deleted.
+	_dumpDrainVisibility = 5 * time.Minute
+	_dotJSONL            = ".jsonl"
+)
+
+// dumpedMessage is a line of the objects written by DumpQueueToS3.
+type dumpedMessage struct {
+	ID   string `json:"id"`
+	Body string `json:"body"`
+}
+
+// DumpQueueToS3 writes the messages of the queue to dst as JSONL objects "<prefix>/part-00000.jsonl", ...
+// of up to 1000 messages, and returns the number of dumped messages.
+//
+// By default the queue is drained, received messages are hidden for 5 minutes and deleted once the object
+// holding them is written, a part is written early when its oldest message is hidden for half of it.
+// With WithPeek(d) messages are kept and hidden for d, they're visible again after it.
+// A message received again is dumped once, the dump stops after WithEmptyReceives consecutive receives
+// (2 by default) of WaitTimeSeconds each without new message.
+//
+// Usage:
+//
+//	n, err := w.DumpQueueToS3(s3c, "snapshots/orders-20240601", WithPeek(time.Hour))
+//	// later, to the same or another queue
+//	n, err = w.LoadQueueFromS3(s3c, "snapshots/orders-20240601")
+func (w *SqsClient) DumpQueueToS3(dst S3API, prefix string, opts ...SqsOptFunc) (int, error) {
+	opt := SqsOpts{emptyReceives: _defaultEmptyReceives}
+	bindSqsOpts(&opt, opts...)
+
+	drain := opt.peek == 0
+	if drain {
+		opts = append(opts, func(o *SqsOpts) { o.peek = _dumpDrainVisibility })
+	}
+
+	var (
+		pending []types.Message
+		// seen holds the dumped ids, pendingAt the index in pending of the ones not written yet.
+		seen      = map[string]bool{}
+		pendingAt = map[string]int{}
+		oldest    time.Time
+		part      = 0
+		dumped    = 0
+		empty     = 0
+	)
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+
+		key := fmt.Sprintf("%s/part-%05d%s", strings.TrimSuffix(prefix, "/"), part, _dotJSONL)
+		if err := dst.PutObject(key, encodeDump(pending)); err != nil {
+			return fmt.Errorf("cannot write %s: %w", key, err)
+		}
+
+		if drain {
+			w.deleteDumped(pending)
+		}
+
+		part++
+		dumped += len(pending)
+		pending = pending[:0]
+		clear(pendingAt)
+
+		return nil
+	}
+
+	for empty < opt.emptyReceives {
+		out, err := w.GetMsgs(append(opts, BatchSize(MaxBatchSize))...)
+		if err != nil {
+			return dumped, err
+		}
+
+		var fresh, redelivered []types.Message
+
+		for _, msg := range out.Messages {
+			id := aws.ToString(msg.MessageId)
+
+			switch i, ok := pendingAt[id]; {
+			case ok:
+				// received again before its part is written, only the newest receipt handle is valid.
+				pending[i].ReceiptHandle = msg.ReceiptHandle
+			case seen[id]:
+				redelivered = append(redelivered, msg)
+			default:
+				seen[id] = true
+				fresh = append(fresh, msg)
+			}
+		}
+
+		// already written, its delete failed or came too late.
+		if drain && len(redelivered) > 0 {
+			w.deleteDumped(redelivered)
+		}
+
+		if len(fresh) == 0 {
+			empty++
+			continue
+		}
+
+		empty = 0
+
+		if len(pending) == 0 {
+			oldest = time.Now()
+		}
+
+		for _, msg := range fresh {
+			pendingAt[aws.ToString(msg.MessageId)] = len(pending)
+			pending = append(pending, msg)
+		}
+
+		if len(pending) >= _dumpPartSize || (drain && time.Since(oldest) >= _dumpDrainVisibility/2) {
+			if err := flush(); err != nil {
+				return dumped, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return dumped, err
+	}
+
+	return dumped, nil
+}
+
+// deleteDumped deletes messages in batches, failures are logged, the messages are received and deleted again.
+func (w *SqsClient) deleteDumped(messages []types.Message) {
+	for i := 0; i < len(messages); i += MaxBatchSize {
+		batch := messages[i:min(i+MaxBatchSize, len(messages))]
+
+		entries := make([]types.DeleteMessageBatchRequestEntry, len(batch))
+		for j, msg := range batch {
+			entries[j] = types.DeleteMessageBatchRequestEntry{Id: aws.String(strconv.Itoa(j)), ReceiptHandle: msg.ReceiptHandle}
+		}
+
+		out, err := w.api().DeleteMessageBatch(w.awsCtx, &sqs.DeleteMessageBatchInput{
+			QueueUrl: &w.QueueURL,
+			Entries:  entries,
+		})
+		if err != nil {
+			w.log().Warn("cannot delete dumped messages", "count", len(batch), "error", err)
+			continue
+		}
+
+		for _, f := range out.Failed {
+			w.log().Warn("cannot delete dumped message", "code", aws.ToString(f.Code), "error", aws.ToString(f.Message))
+		}
+	}
+}
+
+func encodeDump(messages []types.Message) []byte {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	for _, msg := range messages {
+		// cannot fail, both fields are strings.
+		_ = enc.Encode(dumpedMessage{ID: aws.ToString(msg.MessageId), Body: aws.ToString(msg.Body)})
+	}
+
+	return buf.Bytes()
+}
+
+// LoadQueueFromS3 sends the messages of the JSONL objects under prefix written by DumpQueueToS3 to the queue,
+// in the dumped order, and returns the number of sent messages.
+// Messages get new ids, and FIFO queues are not supported since no message group is set.
+func (w *SqsClient) LoadQueueFromS3(src S3API, prefix string) (int, error) {
+	keys, err := src.ListObjects(strings.TrimSuffix(prefix, "/") + "/")
+	if err != nil {
+		return 0, err
+	}
+
+	loaded := 0
+
+	for _, key := range keys {
+		if !strings.HasSuffix(key, _dotJSONL) {
+			continue
+		}
+
+		raw, err := src.GetObject(key)
+		if err != nil {
+			return loaded, fmt.Errorf("cannot read %s: %w", key, err)
+		}
+
+		var bodies []string
+
+		scanner := bufio.NewScanner(bytes.NewReader(raw))
+		// escaping may make a line much longer than its body.
+		scanner.Buffer(nil, 8*_maxMessageSize)
+
+		for scanner.Scan() {
+			var m dumpedMessage
+			if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+				return loaded, fmt.Errorf("invalid line in %s: %w", key, err)
+			}
+
+			bodies = append(bodies, m.Body)
+		}
+
+		if err := scanner.Err(); err != nil {
+			return loaded, fmt.Errorf("cannot read %s: %w", key, err)
+		}
+
+		n, err := w.SendManyMessages(bodies)
+		loaded += n
+
+		if err != nil {
+			return loaded, fmt.Errorf("cannot load %s: %w", key, err)
+		}
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,166 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const _envelopeIDLen = 20
+
+var ErrInvalidEnvelope = errors.New("invalid envelope")
+
+// Envelope is the structure messages are wrapped in by envelope mode, see SetEnvelope.
+type Envelope struct {
+	ID string `json:"id"`
+	// CorrelationID is the id of the message starting a chain of messages, the ID of that message by default.
+	CorrelationID string          `json:"correlation_id"`
+	Timestamp     time.Time       `json:"timestamp"`
+	Source        string          `json:"source"`
+	Payload       json.RawMessage `json:"payload"`
+	// TraceParent and TraceState are the W3C trace context of the sender.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+
+	// Message is the received message, e.g. to DeleteMsg(env.Message.ReceiptHandle), nil for parsed bodies.
+	Message *types.Message `json:"-"`
+}
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying the correlation id of messages sent with it.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation id of ctx, empty if there is none.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// SetEnvelope enables envelope mode, every sent message is wrapped as
+// {id, correlation_id, timestamp, source, payload}, source names the sender, empty disables it.
+//
+// A JSON message is the payload as is, others are a JSON string. The validator checks the message,
+// not the envelope. With SendMsgContext, the correlation id and the trace context of ctx
+// (e.g. of a span started with the tracer of WithTelemetry) are propagated, so traces flow across the queue.
+//
+// Usage:
+//
+//	producer.SetEnvelope("billing")
+//	_, err := producer.SendMsgContext(ctx, `{"invoice":42}`)
+//
+//	envs, err := consumer.GetEnvelopes()
+//	for _, env := range envs {
+//		ctx := env.Context(ctx) // carries the correlation id and the producer span
+//		var inv Invoice
+//		err = env.Decode(&inv)
+//	}
+func (w *SqsClient) SetEnvelope(source string) {
+	w.envelopeSource = source
+}
+
+// wrapEnvelope returns message wrapped in an envelope if envelope mode is enabled, message otherwise.
+func (w *SqsClient) wrapEnvelope(ctx context.Context, message string) (string, error) {
+	if w.envelopeSource == "" {
+		return message, nil
+	}
+
+	env := Envelope{
+		ID:            randSeq(_envelopeIDLen),
+		CorrelationID: CorrelationIDFromContext(ctx),
+		Timestamp:     time.Now().UTC(),
+		Source:        w.envelopeSource,
+		Payload:       json.RawMessage(message),
+	}
+
+	if env.CorrelationID == "" {
+		env.CorrelationID = env.ID
+	}
+
+	if !json.Valid([]byte(message)) {
+		payload, err := json.Marshal(message)
+		if err != nil {
+			return "", err
+		}
+
+		env.Payload = payload
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	env.TraceParent = carrier.Get("traceparent")
+	env.TraceState = carrier.Get("tracestate")
+
+	raw, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+// ParseEnvelope parses a message body sent in envelope mode.
+func ParseEnvelope(body string) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal([]byte(body), &env); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+
+	if env.ID == "" || len(env.Payload) == 0 {
+		return nil, fmt.Errorf("%w: missing id or payload", ErrInvalidEnvelope)
+	}
+
+	return &env, nil
+}
+
+// Decode unmarshals the payload of e into v.
+func (e *Envelope) Decode(v any) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Context returns a copy of ctx carrying the correlation id and the remote trace context of e,
+// spans started with it are children of the span which sent e.
+func (e *Envelope) Context(ctx context.Context) context.Context {
+	ctx = ContextWithCorrelationID(ctx, e.CorrelationID)
+
+	carrier := propagation.MapCarrier{"traceparent": e.TraceParent, "tracestate": e.TraceState}
+
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
+
+// GetEnvelopes receives messages like GetMsgs and parses their envelopes,
+// messages which are not envelopes are left in the queue and reported in the joined error.
+func (w *SqsClient) GetEnvelopes(opts ...SqsOptFunc) ([]*Envelope, error) {
+	out, err := w.GetMsgs(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		envs []*Envelope
+		errs []error
+	)
+
+	for i := range out.Messages {
+		msg := &out.Messages[i]
+
+		env, err := ParseEnvelope(aws.ToString(msg.Body))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("message %s: %w", aws.ToString(msg.MessageId), err))
+			continue
+		}
+
+		env.Message = msg
+		envs = append(envs, env)
+	}
+
+	return envs, errors.Join(errs...)
+}
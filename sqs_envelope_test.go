@@ -0,0 +1,73 @@
+package xaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type EnvelopeSuite struct {
+	suite.Suite
+}
+
+func TestEnvelope(t *testing.T) {
+	suite.Run(t, new(EnvelopeSuite))
+}
+
+func (s *EnvelopeSuite) Test_01_roundTrip() {
+	sdk := &loopbackSqsSdk{}
+	w := &SqsClient{sdk: sdk, QueueName: "q", Timeout: 5}
+	w.SetEnvelope("billing")
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(ContextWithCorrelationID(context.Background(), "order-1"), sc)
+
+	_, err := w.SendMsgContext(ctx, `{"invoice":42}`)
+	s.Nil(err)
+	_, err = w.SendMsg("plain text")
+	s.Nil(err)
+
+	envs, err := w.GetEnvelopes()
+	s.Nil(err)
+	s.Require().Len(envs, 2)
+
+	var inv struct{ Invoice int }
+	s.Nil(envs[0].Decode(&inv))
+	s.Equal(42, inv.Invoice)
+	s.Equal("billing", envs[0].Source)
+	s.Equal("order-1", envs[0].CorrelationID)
+	s.NotNil(envs[0].Message)
+
+	got := envs[0].Context(context.Background())
+	s.Equal("order-1", CorrelationIDFromContext(got))
+	s.Equal(sc.TraceID(), trace.SpanContextFromContext(got).TraceID())
+	s.True(trace.SpanContextFromContext(got).IsRemote())
+
+	var text string
+	s.Nil(envs[1].Decode(&text))
+	s.Equal("plain text", text)
+	s.Equal(envs[1].ID, envs[1].CorrelationID)
+	s.Empty(envs[1].TraceParent)
+}
+
+func (s *EnvelopeSuite) Test_02_invalid() {
+	_, err := ParseEnvelope(`{"payload":1}`)
+	s.ErrorIs(err, ErrInvalidEnvelope)
+
+	_, err = ParseEnvelope("not json")
+	s.ErrorIs(err, ErrInvalidEnvelope)
+
+	sdk := &loopbackSqsSdk{}
+	w := &SqsClient{sdk: sdk, QueueName: "q", Timeout: 5}
+
+	_, _ = w.SendMsg("raw")
+	envs, err := w.GetEnvelopes()
+	s.Empty(envs)
+	s.ErrorIs(err, ErrInvalidEnvelope)
+}
@@ -0,0 +1,162 @@
+package xaws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const _multiQueueIdleWait = time.Second
+
+// QueueMessage is a message received by MultiQueueConsumer, tagged with its source queue.
+type QueueMessage struct {
+	Queue   string
+	Message types.Message
+}
+
+// MultiQueueHandler handles a message, the message is deleted when it returns nil,
+// otherwise it's received again after the visibility timeout.
+type MultiQueueHandler func(ctx context.Context, msg QueueMessage) error
+
+type weightedQueue struct {
+	client *SqsClient
+	weight int
+}
+
+// MultiQueueConsumer polls several queues and delivers their messages to one handler,
+// a queue of weight n is polled n times for each poll of a queue of weight 1.
+//
+// Usage:
+//
+//	c := NewMultiQueueConsumer(handle, BatchSize(10)).
+//		AddQueue(high, 3).
+//		AddQueue(low, 1)
+//	err := c.Run(ctx)
+type MultiQueueConsumer struct {
+	queues  []weightedQueue
+	handler MultiQueueHandler
+	opt     SqsOpts
+
+	logger Logger
+}
+
+// NewMultiQueueConsumer creates a consumer of handler, BatchSize and WithIdempotency are supported,
+// with WithIdempotency processed messages are deleted without calling handler.
+func NewMultiQueueConsumer(handler MultiQueueHandler, opts ...SqsOptFunc) *MultiQueueConsumer {
+	opt := SqsOpts{batchSize: MaxBatchSize}
+	bindSqsOpts(&opt, opts...)
+
+	return &MultiQueueConsumer{handler: handler, opt: opt}
+}
+
+// AddQueue adds the queue of client polled with weight, weight < 1 is 1.
+func (c *MultiQueueConsumer) AddQueue(client *SqsClient, weight int) *MultiQueueConsumer {
+	c.queues = append(c.queues, weightedQueue{client: client, weight: max(weight, 1)})
+	return c
+}
+
+// SetLogger sets the logger of consumer, nil falls back to the default logger.
+func (c *MultiQueueConsumer) SetLogger(l Logger) {
+	c.logger = l
+}
+
+func (c *MultiQueueConsumer) log() Logger {
+	return orDefaultLogger(c.logger)
+}
+
+// Run polls the queues until ctx is done and returns its error,
+// it waits 1s after a round in which all queues were empty.
+func (c *MultiQueueConsumer) Run(ctx context.Context) error {
+	schedule := c.schedule()
+
+	for {
+		got := 0
+
+		for _, q := range schedule {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			got += c.poll(ctx, q)
+		}
+
+		if got > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(_multiQueueIdleWait):
+		}
+	}
+}
+
+// schedule returns the queues of a polling round, interleaved by smooth weighted round-robin,
+// e.g. weights 3 and 1 give [a a b a].
+func (c *MultiQueueConsumer) schedule() []*SqsClient {
+	total := 0
+	for _, q := range c.queues {
+		total += q.weight
+	}
+
+	current := make([]int, len(c.queues))
+	schedule := make([]*SqsClient, 0, total)
+
+	for range total {
+		best := 0
+
+		for i, q := range c.queues {
+			current[i] += q.weight
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+
+		current[best] -= total
+		schedule = append(schedule, c.queues[best].client)
+	}
+
+	return schedule
+}
+
+// poll receives a batch of q without waiting and handles it, returns the number of received messages.
+func (c *MultiQueueConsumer) poll(ctx context.Context, q *SqsClient) int {
+	out, err := q.GetMsgs(WaitTimeSeconds(0), BatchSize(c.opt.batchSize))
+	if err != nil {
+		c.log().Error("cannot receive messages", "queue", q.QueueName, "error", err)
+		return 0
+	}
+
+	for _, msg := range out.Messages {
+		var claim *idempotencyClaim
+
+		if c.opt.idempotency != nil {
+			if claim = q.claimMessage(msg, &c.opt); claim == nil {
+				continue
+			}
+		}
+
+		err := c.handler(ctx, QueueMessage{Queue: q.QueueName, Message: msg})
+		if err != nil {
+			c.log().Warn("cannot handle message", "queue", q.QueueName, "id", aws.ToString(msg.MessageId), "error", err)
+		}
+
+		// a failed message is released, so it's handled again when redelivered.
+		if err := claim.done(err); err != nil {
+			c.log().Warn("cannot record message", "queue", q.QueueName, "id", aws.ToString(msg.MessageId), "error", err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if _, err := q.DeleteMsg(msg.ReceiptHandle); err != nil {
+			c.log().Warn("cannot delete message", "queue", q.QueueName, "id", aws.ToString(msg.MessageId), "error", err)
+		}
+	}
+
+	return len(out.Messages)
+}
@@ -0,0 +1,134 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// memQueueSdk is a queue of bodies, received messages are gone until deleted or released by fail.
+type memQueueSdk struct {
+	SqsSdkClient
+
+	mu       sync.Mutex
+	bodies   []string
+	inflight map[string]string
+	deleted  []string
+}
+
+func newMemQueueSdk(prefix string, n int) *memQueueSdk {
+	q := &memQueueSdk{inflight: map[string]string{}}
+	for i := range n {
+		q.bodies = append(q.bodies, fmt.Sprintf("%s-%d", prefix, i))
+	}
+
+	return q
+}
+
+func (q *memQueueSdk) ReceiveMessage(_ context.Context, in *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	out := &sqs.ReceiveMessageOutput{}
+
+	for len(q.bodies) > 0 && len(out.Messages) < int(in.MaxNumberOfMessages) {
+		body := q.bodies[0]
+		q.bodies = q.bodies[1:]
+		q.inflight[body] = body
+		out.Messages = append(out.Messages, types.Message{Body: aws.String(body), MessageId: aws.String(body), ReceiptHandle: aws.String(body)})
+	}
+
+	return out, nil
+}
+
+func (q *memQueueSdk) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.inflight, aws.ToString(in.ReceiptHandle))
+	q.deleted = append(q.deleted, aws.ToString(in.ReceiptHandle))
+
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (q *memQueueSdk) DeleteMessageBatch(_ context.Context, in *sqs.DeleteMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	out := &sqs.DeleteMessageBatchOutput{}
+
+	for _, e := range in.Entries {
+		_, _ = q.DeleteMessage(context.Background(), &sqs.DeleteMessageInput{ReceiptHandle: e.ReceiptHandle})
+		out.Successful = append(out.Successful, types.DeleteMessageBatchResultEntry{Id: e.Id})
+	}
+
+	return out, nil
+}
+
+type MultiQueueSuite struct {
+	suite.Suite
+}
+
+func TestMultiQueue(t *testing.T) {
+	suite.Run(t, new(MultiQueueSuite))
+}
+
+func (s *MultiQueueSuite) Test_01_schedule() {
+	a, b := &SqsClient{QueueName: "a"}, &SqsClient{QueueName: "b"}
+	c := NewMultiQueueConsumer(nil).AddQueue(a, 3).AddQueue(b, 1)
+
+	var names []string
+	for _, q := range c.schedule() {
+		names = append(names, q.QueueName)
+	}
+
+	s.Equal([]string{"a", "a", "b", "a"}, names)
+}
+
+func (s *MultiQueueSuite) Test_02_run() {
+	high, low := newMemQueueSdk("high", 6), newMemQueueSdk("low", 2)
+
+	var (
+		mu  sync.Mutex
+		got []QueueMessage
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := NewMultiQueueConsumer(func(_ context.Context, msg QueueMessage) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if aws.ToString(msg.Message.Body) == "low-1" {
+			return errors.New("boom")
+		}
+
+		got = append(got, msg)
+		if len(got) == 7 {
+			cancel()
+		}
+
+		return nil
+	}, BatchSize(1))
+
+	c.AddQueue(&SqsClient{sdk: high, QueueName: "high"}, 3).
+		AddQueue(&SqsClient{sdk: low, QueueName: "low"}, 1)
+
+	err := c.Run(ctx)
+	s.ErrorIs(err, context.Canceled)
+
+	s.Len(got, 7)
+	s.Equal("high", got[0].Queue)
+	s.Equal("high", got[1].Queue)
+	s.Equal("low", got[2].Queue)
+	s.Equal("low-0", aws.ToString(got[2].Message.Body))
+
+	// failed message is not deleted.
+	s.Contains(low.inflight, "low-1")
+	s.Len(high.deleted, 6)
+}
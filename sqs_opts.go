@@ -1,5 +1,12 @@
 package xaws
 
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
 type SqsOpts struct {
 	batchSize int
 	max       int
@@ -7,6 +14,22 @@ type SqsOpts struct {
 	queueName string
 
 	waitTimeSeconds int
+	emptyReceives   int
+	// peek is the visibility timeout of received messages, set by WithPeek.
+	peek time.Duration
+
+	idempotency    *IdempotencyStore
+	idempotencyKey func(msg types.Message) string
+
+	// used by CreateQueue only.
+	fifo              bool
+	contentBasedDedup bool
+	dlqArn            string
+	maxReceiveCount   int
+	sqsManagedSSE     bool
+	kmsKeyID          string
+	visibilityTimeout time.Duration
+	retentionPeriod   time.Duration
 }
 
 type SqsOptFunc func(o *SqsOpts)
@@ -29,6 +52,22 @@ func WithMax(i int) SqsOptFunc {
 	}
 }
 
+// WithEmptyReceives makes ReadMessages stop after n consecutive receives without message, n < 1 is 1.
+func WithEmptyReceives(n int) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.emptyReceives = max(n, 1)
+	}
+}
+
+// WithPeek makes DumpQueueToS3 keep the messages in the queue instead of draining it,
+// received messages are hidden for d, so each one is dumped once, then they're visible again.
+// d must be longer than the dump, at most 12 hours.
+func WithPeek(d time.Duration) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.peek = d
+	}
+}
+
 func BatchSize(i int) SqsOptFunc {
 	return func(o *SqsOpts) {
 		o.batchSize = i
@@ -40,3 +79,67 @@ func WithQueueName(s string) SqsOptFunc {
 		o.queueName = s
 	}
 }
+
+// WithIdempotency makes ReadMessages claim each message in store before it is sent to the channel,
+// call SqsResp.Done after processing it. A processed message is deleted from the queue instead of
+// being sent again, a message being processed is skipped until it's done or its claim expires.
+// keyFn returns the idempotency key of message, nil uses the MessageId.
+func WithIdempotency(store *IdempotencyStore, keyFn func(msg types.Message) string) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.idempotency = store
+		o.idempotencyKey = keyFn
+
+		if keyFn == nil {
+			o.idempotencyKey = func(msg types.Message) string {
+				return aws.ToString(msg.MessageId)
+			}
+		}
+	}
+}
+
+// WithFifoQueue makes CreateQueue create a FIFO queue, ".fifo" is appended to the name if missing.
+// With contentBasedDedup, the sha256 of the body is used as deduplication id when none is given.
+func WithFifoQueue(contentBasedDedup bool) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.fifo = true
+		o.contentBasedDedup = contentBasedDedup
+	}
+}
+
+// WithDeadLetterQueue makes CreateQueue attach a redrive policy, messages received
+// more than maxReceiveCount times are moved to the queue dlqArn.
+func WithDeadLetterQueue(dlqArn string, maxReceiveCount int) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.dlqArn = dlqArn
+		o.maxReceiveCount = maxReceiveCount
+	}
+}
+
+// WithSqsManagedSSE makes CreateQueue enable encryption at rest with SQS owned keys (SSE-SQS).
+func WithSqsManagedSSE() SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.sqsManagedSSE = true
+	}
+}
+
+// WithKmsKey makes CreateQueue enable encryption at rest with the KMS key id, arn or alias (SSE-KMS),
+// e.g. "alias/aws/sqs".
+func WithKmsKey(keyID string) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.kmsKeyID = keyID
+	}
+}
+
+// WithVisibilityTimeout sets the visibility timeout of the queue created by CreateQueue, 30s by default.
+func WithVisibilityTimeout(d time.Duration) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.visibilityTimeout = d
+	}
+}
+
+// WithRetentionPeriod sets how long the queue created by CreateQueue keeps messages, 1 day by default.
+func WithRetentionPeriod(d time.Duration) SqsOptFunc {
+	return func(o *SqsOpts) {
+		o.retentionPeriod = d
+	}
+}
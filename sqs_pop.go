@@ -0,0 +1,60 @@
+package xaws
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var ErrNoMessage = errors.New("no message in queue")
+
+// PopMsg receives a message and deletes it before returning its body, ErrNoMessage if the queue is empty.
+//
+// It's at-most-once delivery: a message is gone once it's popped, if the caller crashes while processing it,
+// it's lost. Use it for fire-and-forget workloads, where processing a message twice is worse than losing it,
+// otherwise receive with GetMsgs or ReadMessages and DeleteMsg after processing (at-least-once).
+func (w *SqsClient) PopMsg() (string, error) {
+	bodies, err := w.PopMsgs(1)
+	if err != nil {
+		return "", err
+	}
+
+	if len(bodies) == 0 {
+		return "", ErrNoMessage
+	}
+
+	return bodies[0], nil
+}
+
+// PopMsgs receives up to n messages and deletes them before returning their bodies, see PopMsg for the tradeoff.
+// It stops early on an empty receive, so fewer than n (or no) bodies may be returned.
+// A message which cannot be deleted is not returned, it's received again after its visibility timeout.
+//
+// Usage:
+//
+//	bodies, err := w.PopMsgs(25)
+func (w *SqsClient) PopMsgs(n int) ([]string, error) {
+	var bodies []string
+
+	for len(bodies) < n {
+		out, err := w.GetMsgs(BatchSize(min(n-len(bodies), MaxBatchSize)))
+		if err != nil {
+			return bodies, err
+		}
+
+		if len(out.Messages) == 0 {
+			break
+		}
+
+		for _, msg := range out.Messages {
+			if _, err := w.DeleteMsg(msg.ReceiptHandle); err != nil {
+				w.log().Warn("cannot delete popped message, it's not returned", "id", aws.ToString(msg.MessageId), "error", err)
+				continue
+			}
+
+			bodies = append(bodies, aws.ToString(msg.Body))
+		}
+	}
+
+	return bodies, nil
+}
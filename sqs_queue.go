@@ -0,0 +1,200 @@
+package xaws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/spf13/cast"
+)
+
+const (
+	_fifoSuffix              = ".fifo"
+	_defaultRetentionSeconds = 86400
+)
+
+// QueueInfo is the configuration and state of a queue.
+type QueueInfo struct {
+	Name string
+	URL  string
+	Arn  string
+
+	FIFO              bool
+	ContentBasedDedup bool
+
+	VisibilityTimeout time.Duration
+	RetentionPeriod   time.Duration
+	Delay             time.Duration
+
+	// DeadLetterTargetArn is empty if the queue has no redrive policy.
+	DeadLetterTargetArn string
+	MaxReceiveCount     int
+
+	SqsManagedSSE  bool
+	KmsMasterKeyID string
+
+	ApproximateMessages int64
+}
+
+// redrivePolicy is the RedrivePolicy attribute of a queue, maxReceiveCount is a string or a number.
+type redrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     any    `json:"maxReceiveCount"`
+}
+
+// CreateQueueWithInfo creates queue name and points the wrapper to it, see CreateQueue for options.
+//
+// Usage:
+//
+//	dlq, err := w.CreateQueueWithInfo("jobs-dlq.fifo", WithFifoQueue(true))
+//	info, err := w.CreateQueueWithInfo("jobs.fifo",
+//		WithFifoQueue(true),
+//		WithDeadLetterQueue(dlq.Arn, 5),
+//		WithSqsManagedSSE(),
+//		WithVisibilityTimeout(5*time.Minute),
+//	)
+func (w *SqsClient) CreateQueueWithInfo(name string, opts ...SqsOptFunc) (*QueueInfo, error) {
+	opt := &SqsOpts{}
+	bindSqsOpts(opt, opts...)
+
+	attrs := map[string]string{
+		string(types.QueueAttributeNameDelaySeconds):           "0",
+		string(types.QueueAttributeNameMessageRetentionPeriod): strconv.Itoa(_defaultRetentionSeconds),
+	}
+
+	if opt.fifo {
+		if !strings.HasSuffix(name, _fifoSuffix) {
+			name += _fifoSuffix
+		}
+
+		attrs[string(types.QueueAttributeNameFifoQueue)] = "true"
+		attrs[string(types.QueueAttributeNameContentBasedDeduplication)] = strconv.FormatBool(opt.contentBasedDedup)
+	}
+
+	if opt.dlqArn != "" {
+		policy, err := json.Marshal(redrivePolicy{DeadLetterTargetArn: opt.dlqArn, MaxReceiveCount: opt.maxReceiveCount})
+		if err != nil {
+			return nil, err
+		}
+
+		attrs[string(types.QueueAttributeNameRedrivePolicy)] = string(policy)
+	}
+
+	if opt.kmsKeyID != "" {
+		attrs[string(types.QueueAttributeNameKmsMasterKeyId)] = opt.kmsKeyID
+	} else if opt.sqsManagedSSE {
+		attrs[string(types.QueueAttributeNameSqsManagedSseEnabled)] = "true"
+	}
+
+	if opt.visibilityTimeout > 0 {
+		attrs[string(types.QueueAttributeNameVisibilityTimeout)] = strconv.Itoa(int(opt.visibilityTimeout.Seconds()))
+	}
+
+	if opt.retentionPeriod > 0 {
+		attrs[string(types.QueueAttributeNameMessageRetentionPeriod)] = strconv.Itoa(int(opt.retentionPeriod.Seconds()))
+	}
+
+	output, err := w.api().CreateQueue(w.awsCtx, &sqs.CreateQueueInput{
+		QueueName:  &name,
+		Attributes: attrs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create queue %s: %w", name, err)
+	}
+
+	w.QueueName = name
+	w.QueueURL = aws.ToString(output.QueueUrl)
+
+	return w.DescribeQueue()
+}
+
+// DescribeQueue returns the configuration and approximate size of the queue.
+func (w *SqsClient) DescribeQueue() (*QueueInfo, error) {
+	attrs, err := w.GetQueueAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	return newQueueInfo(w.QueueName, w.QueueURL, attrs), nil
+}
+
+func newQueueInfo(name, url string, attrs map[string]string) *QueueInfo {
+	attr := func(n types.QueueAttributeName) string {
+		return attrs[string(n)]
+	}
+
+	info := &QueueInfo{
+		Name:                name,
+		URL:                 url,
+		Arn:                 attr(types.QueueAttributeNameQueueArn),
+		FIFO:                cast.ToBool(attr(types.QueueAttributeNameFifoQueue)),
+		ContentBasedDedup:   cast.ToBool(attr(types.QueueAttributeNameContentBasedDeduplication)),
+		VisibilityTimeout:   time.Duration(cast.ToInt64(attr(types.QueueAttributeNameVisibilityTimeout))) * time.Second,
+		RetentionPeriod:     time.Duration(cast.ToInt64(attr(types.QueueAttributeNameMessageRetentionPeriod))) * time.Second,
+		Delay:               time.Duration(cast.ToInt64(attr(types.QueueAttributeNameDelaySeconds))) * time.Second,
+		SqsManagedSSE:       cast.ToBool(attr(types.QueueAttributeNameSqsManagedSseEnabled)),
+		KmsMasterKeyID:      attr(types.QueueAttributeNameKmsMasterKeyId),
+		ApproximateMessages: cast.ToInt64(attr(types.QueueAttributeNameApproximateNumberOfMessages)),
+	}
+
+	var policy redrivePolicy
+	if raw := attr(types.QueueAttributeNameRedrivePolicy); raw != "" && json.Unmarshal([]byte(raw), &policy) == nil {
+		info.DeadLetterTargetArn = policy.DeadLetterTargetArn
+		info.MaxReceiveCount = cast.ToInt(policy.MaxReceiveCount)
+	}
+
+	return info
+}
+
+// WaitUntilQueueEmpty polls the queue every pollInterval until no message is visible, in flight or delayed,
+// it returns the error of ctx if the queue is still not empty when ctx is done.
+// The counts are approximate, a message sent right after may not be seen.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+//	defer cancel()
+//	err := w.WaitUntilQueueEmpty(ctx, 10*time.Second)
+func (w *SqsClient) WaitUntilQueueEmpty(ctx context.Context, pollInterval time.Duration) error {
+	names := []types.QueueAttributeName{
+		types.QueueAttributeNameApproximateNumberOfMessages,
+		types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+		types.QueueAttributeNameApproximateNumberOfMessagesDelayed,
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		res, err := w.api().GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       &w.QueueURL,
+			AttributeNames: names,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot get attributes of queue %s: %w", w.QueueName, err)
+		}
+
+		var total int64
+		for _, n := range names {
+			total += cast.ToInt64(res.Attributes[string(n)])
+		}
+
+		if total == 0 {
+			return nil
+		}
+
+		w.log().Debug("waiting for queue to be empty", "queue", w.QueueName, "messages", total)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("queue %s still has %d messages: %w", w.QueueName, total, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// redeliverSdk returns every message not deleted on each receive with a new receipt handle,
+// like a queue whose visibility timeout expires between receives, only the newest handle deletes.
+type redeliverSdk struct {
+	SqsSdkClient
+
+	ids      []string
+	handles  map[string]string
+	receives int
+	stale    int
+}
+
+func (q *redeliverSdk) ReceiveMessage(_ context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	q.receives++
+
+	out := &sqs.ReceiveMessageOutput{}
+
+	for _, id := range q.ids {
+		if _, ok := q.handles[id]; !ok {
+			continue
+		}
+
+		q.handles[id] = fmt.Sprintf("%s#%d", id, q.receives)
+		out.Messages = append(out.Messages, types.Message{MessageId: aws.String(id), Body: aws.String(id), ReceiptHandle: aws.String(q.handles[id])})
+	}
+
+	return out, nil
+}
+
+func (q *redeliverSdk) DeleteMessageBatch(_ context.Context, in *sqs.DeleteMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	out := &sqs.DeleteMessageBatchOutput{}
+
+	for _, e := range in.Entries {
+		id, _, _ := strings.Cut(aws.ToString(e.ReceiptHandle), "#")
+		if q.handles[id] != aws.ToString(e.ReceiptHandle) {
+			q.stale++
+			continue
+		}
+
+		delete(q.handles, id)
+		out.Successful = append(out.Successful, types.DeleteMessageBatchResultEntry{Id: e.Id})
+	}
+
+	return out, nil
+}
+
+type ReadSuite struct {
+	suite.Suite
+}
+
+func TestRead(t *testing.T) {
+	suite.Run(t, new(ReadSuite))
+}
+
+func (s *ReadSuite) read(w *SqsClient, opts ...SqsOptFunc) ([]string, SqsReadStatus) {
+	ch := make(chan *SqsResp, 16)
+	go w.ReadMessages(ch, opts...)
+
+	var bodies []string
+
+	for resp := range ch {
+		if resp.Status != SqsReadSuccess {
+			return bodies, resp.Status
+		}
+
+		bodies = append(bodies, *resp.Msg)
+	}
+
+	return bodies, SqsReadError
+}
+
+func (s *ReadSuite) Test_01_allConsumed() {
+	// received messages stay in flight, ReadMessages stops on empty receives only.
+	w := &SqsClient{sdk: newMemQueueSdk("m", 5), QueueName: "q", batchSize: 2}
+
+	bodies, status := s.read(w, WithEmptyReceives(3))
+	s.Equal(SqsReadAllConsumed, status)
+	s.Equal([]string{"m-0", "m-1", "m-2", "m-3", "m-4"}, bodies)
+}
+
+func (s *ReadSuite) Test_02_max() {
+	w := &SqsClient{sdk: newMemQueueSdk("m", 5), QueueName: "q"}
+
+	bodies, status := s.read(w, WithMax(3), BatchSize(2))
+	s.Equal(SqsReadMaximumReached, status)
+	s.Equal([]string{"m-0", "m-1", "m-2"}, bodies)
+}
+
+func (s *ReadSuite) Test_03_pop() {
+	sdk := newMemQueueSdk("m", 13)
+	w := &SqsClient{sdk: sdk, QueueName: "q"}
+
+	body, err := w.PopMsg()
+	s.Nil(err)
+	s.Equal("m-0", body)
+
+	bodies, err := w.PopMsgs(20)
+	s.Nil(err)
+	s.Len(bodies, 12)
+	s.Len(sdk.deleted, 13)
+	s.Empty(sdk.inflight)
+
+	_, err = w.PopMsg()
+	s.ErrorIs(err, ErrNoMessage)
+}
+
+func (s *ReadSuite) Test_04_dumpAndLoad() {
+	store := NewFakeS3("backup")
+
+	sdk := newMemQueueSdk("m", 1500)
+	w := &SqsClient{sdk: sdk, QueueName: "q"}
+
+	n, err := w.DumpQueueToS3(store, "snap/")
+	s.Nil(err)
+	s.Equal(1500, n)
+	s.Len(sdk.deleted, 1500)
+
+	keys, _ := store.ListObjects("snap/")
+	s.Equal([]string{"snap/part-00000.jsonl", "snap/part-00001.jsonl"}, keys)
+
+	// peeked messages are not deleted.
+	peeked := newMemQueueSdk("p", 3)
+	n, err = (&SqsClient{sdk: peeked, QueueName: "p"}).DumpQueueToS3(store, "peek", WithPeek(time.Minute))
+	s.Nil(err)
+	s.Equal(3, n)
+	s.Empty(peeked.deleted)
+
+	recorder := &batchRecorderSdk{}
+	n, err = (&SqsClient{sdk: recorder, QueueName: "r"}).LoadQueueFromS3(store, "snap")
+	s.Nil(err)
+	s.Equal(1500, n)
+	s.Equal("m-0", *recorder.requests[0][0].MessageBody)
+	s.Equal("m-1499", *recorder.requests[149][9].MessageBody)
+}
+
+func (s *ReadSuite) Test_05_dumpRedelivered() {
+	store := NewFakeS3("backup")
+
+	sdk := &redeliverSdk{ids: []string{"a", "b", "c"}, handles: map[string]string{"a": "", "b": "", "c": ""}}
+	w := &SqsClient{sdk: sdk, QueueName: "q"}
+
+	// receives returning only dumped messages count as empty, so the dump ends.
+	n, err := w.DumpQueueToS3(store, "redeliver")
+	s.Nil(err)
+	s.Equal(3, n)
+	s.Equal(3, sdk.receives)
+	s.Empty(sdk.handles)
+	s.Zero(sdk.stale)
+
+	keys, _ := store.ListObjects("redeliver/")
+	s.Equal([]string{"redeliver/part-00000.jsonl"}, keys)
+
+	peeked := &redeliverSdk{ids: []string{"a", "b"}, handles: map[string]string{"a": "", "b": ""}}
+	n, err = (&SqsClient{sdk: peeked, QueueName: "p"}).DumpQueueToS3(store, "peek-redeliver", WithPeek(time.Second))
+	s.Nil(err)
+	s.Equal(2, n)
+	s.Len(peeked.handles, 2)
+}
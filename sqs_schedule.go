@@ -0,0 +1,102 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	_scheduledMessagePrefix    = "xaws-msg-"
+	_scheduledMessageRandomLen = 16
+)
+
+// ScheduledMessage is the result of MessageScheduler.ScheduleMessage,
+// either MessageID (native delay) or ScheduleName (EventBridge Scheduler) is set.
+type ScheduledMessage struct {
+	MessageID    string
+	ScheduleName string
+	At           time.Time
+}
+
+// MessageScheduler sends messages to a queue at a given time, beyond the 15 minutes limit of DelaySeconds.
+//
+// Messages due within 15 minutes are sent with a native delay, later ones are sent by a one-time
+// EventBridge Scheduler schedule, which is deleted once it has run. RoleArn must allow
+// scheduler.amazonaws.com to sqs:SendMessage to the queue.
+//
+// Usage:
+//
+//	ms := NewMessageScheduler(queue, sched, "arn:aws:iam::123456789012:role/scheduler-sqs")
+//	sm, err := ms.ScheduleMessage(`{"job":"report"}`, time.Now().Add(6*time.Hour))
+type MessageScheduler struct {
+	Queue     *SqsClient
+	Scheduler *SchedulerWrapper
+	RoleArn   string
+	// MessageGroupID is used for FIFO queues, "default" if empty.
+	MessageGroupID string
+}
+
+func NewMessageScheduler(queue *SqsClient, sched *SchedulerWrapper, roleArn string) *MessageScheduler {
+	return &MessageScheduler{Queue: queue, Scheduler: sched, RoleArn: roleArn}
+}
+
+// ScheduleMessage sends msg to the queue at at, msg is checked like SendMsg before it's scheduled.
+// A time in the past sends msg immediately, schedules have a precision of one second.
+func (s *MessageScheduler) ScheduleMessage(msg string, at time.Time) (*ScheduledMessage, error) {
+	delay := time.Until(at)
+	if delay <= MaxDelay {
+		out, err := s.Queue.SendMsgWithDelay(msg, delay)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ScheduledMessage{MessageID: aws.ToString(out.MessageId), At: at}, nil
+	}
+
+	if err := s.Queue.checkMessage(msg); err != nil {
+		return nil, err
+	}
+
+	body, err := s.Queue.wrapEnvelope(context.Background(), msg)
+	if err != nil {
+		return nil, err
+	}
+
+	queueArn, err := s.Queue.GetQueueArn()
+	if err != nil {
+		return nil, err
+	}
+
+	var groupID string
+
+	if strings.HasSuffix(s.Queue.QueueName, _fifoSuffix) {
+		groupID = s.MessageGroupID
+		if groupID == "" {
+			groupID = "default"
+		}
+	}
+
+	name := _scheduledMessagePrefix + randSeq(_scheduledMessageRandomLen)
+	spec := ScheduleAt(at).DeleteAfterCompletion()
+
+	if err := s.Scheduler.CreateWithTarget(name, spec, NewSqsTarget(queueArn, s.RoleArn, body, groupID)); err != nil {
+		return nil, fmt.Errorf("cannot schedule message to %s at %s: %w", s.Queue.QueueName, at, err)
+	}
+
+	return &ScheduledMessage{ScheduleName: name, At: at}, nil
+}
+
+// Cancel deletes the schedule of sm, a message sent with native delay cannot be cancelled.
+func (s *MessageScheduler) Cancel(sm *ScheduledMessage) error {
+	if sm.ScheduleName == "" {
+		return fmt.Errorf("message %s is already in queue %s", sm.MessageID, s.Queue.QueueName)
+	}
+
+	_, err := s.Scheduler.DeleteSchedule(sm.ScheduleName)
+
+	return err
+}
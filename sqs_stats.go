@@ -0,0 +1,102 @@
+package xaws
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// SqsStats are the producer counters of a SqsClient since it was created.
+type SqsStats struct {
+	// MessagesSent counts messages accepted by SQS, by SendMsg and batches.
+	MessagesSent int64
+	// Batches counts SendMessageBatch calls.
+	Batches int64
+	// Failures counts failed calls and failed entries of batches.
+	Failures int64
+	// Retries counts attempts after the first one of SendMsgWithRetry.
+	Retries int64
+	// SlowSends counts calls slower than the threshold of SetSlowSendThreshold.
+	SlowSends int64
+	// Calls counts SendMessage and SendMessageBatch calls, successful or not.
+	Calls int64
+	// AverageLatency is the average duration of Calls.
+	AverageLatency time.Duration
+}
+
+type sqsCounters struct {
+	sent, batches, failures, retries, slow, calls, latencyNanos atomic.Int64
+}
+
+// Stats returns a snapshot of the producer counters.
+func (w *SqsClient) Stats() SqsStats {
+	c := &w.counters
+
+	stats := SqsStats{
+		MessagesSent: c.sent.Load(),
+		Batches:      c.batches.Load(),
+		Failures:     c.failures.Load(),
+		Retries:      c.retries.Load(),
+		SlowSends:    c.slow.Load(),
+		Calls:        c.calls.Load(),
+	}
+
+	if stats.Calls > 0 {
+		stats.AverageLatency = time.Duration(c.latencyNanos.Load() / stats.Calls)
+	}
+
+	return stats
+}
+
+// SetSlowSendThreshold logs a warning and counts SlowSends when a send call takes longer than d,
+// 0 disables the detection.
+func (w *SqsClient) SetSlowSendThreshold(d time.Duration) {
+	w.slowSendThreshold = d
+}
+
+// SetMetricEmitter exports the counters of every send call to CloudWatch through e, nil disables it.
+// Metrics are MessagesSent, SendFailures (Count) and SendLatency (Milliseconds), with a QueueName dimension.
+//
+// Usage:
+//
+//	emitter := cw.NewEmitter("producers", time.Minute)
+//	defer emitter.Close()
+//	w.SetMetricEmitter(emitter)
+func (w *SqsClient) SetMetricEmitter(e *MetricEmitter) {
+	w.emitter = e
+}
+
+// recordSend updates the counters of a send call which took elapsed, sent and failed are the messages of the call.
+func (w *SqsClient) recordSend(elapsed time.Duration, sent, failed int, batch bool) {
+	c := &w.counters
+
+	c.calls.Add(1)
+	c.latencyNanos.Add(int64(elapsed))
+	c.sent.Add(int64(sent))
+	c.failures.Add(int64(failed))
+
+	if batch {
+		c.batches.Add(1)
+	}
+
+	if w.slowSendThreshold > 0 && elapsed > w.slowSendThreshold {
+		c.slow.Add(1)
+		w.log().Warn("slow sqs send", "queue", w.QueueName, "elapsed", elapsed, "messages", sent+failed)
+	}
+
+	if w.emitter == nil {
+		return
+	}
+
+	dims := map[string]string{"QueueName": w.QueueName}
+
+	w.emitter.AddMetric(Metric{Name: "MessagesSent", Value: float64(sent), Unit: types.StandardUnitCount, Dimensions: dims})
+	w.emitter.AddMetric(Metric{Name: "SendFailures", Value: float64(failed), Unit: types.StandardUnitCount, Dimensions: dims})
+	w.emitter.AddMetric(Metric{
+		Name:       "SendLatency",
+		Value:      float64(elapsed) / float64(time.Millisecond),
+		Unit:       types.StandardUnitMilliseconds,
+		Dimensions: dims,
+	})
+}
@@ -0,0 +1,106 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// stubSqsSdk fails every n-th SendMessage and the last entry of each batch,
+// other methods of SqsSdkClient are not implemented.
+type stubSqsSdk struct {
+	SqsSdkClient
+
+	calls   int
+	failNth int
+	delay   time.Duration
+}
+
+func (c *stubSqsSdk) SendMessage(_ context.Context, _ *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	time.Sleep(c.delay)
+
+	if c.calls++; c.failNth > 0 && c.calls%c.failNth == 0 {
+		return nil, errors.New("boom")
+	}
+
+	return &sqs.SendMessageOutput{MessageId: aws.String("id")}, nil
+}
+
+func (c *stubSqsSdk) SendMessageBatch(_ context.Context, in *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	out := &sqs.SendMessageBatchOutput{}
+
+	for i, e := range in.Entries {
+		if i == len(in.Entries)-1 {
+			out.Failed = append(out.Failed, sqstypes.BatchResultErrorEntry{Id: e.Id})
+			continue
+		}
+
+		out.Successful = append(out.Successful, sqstypes.SendMessageBatchResultEntry{Id: e.Id})
+	}
+
+	return out, nil
+}
+
+type SqsStatsSuite struct {
+	suite.Suite
+}
+
+func TestSqsStats(t *testing.T) {
+	suite.Run(t, new(SqsStatsSuite))
+}
+
+func (s *SqsStatsSuite) Test_01_counters() {
+	w := &SqsClient{sdk: &stubSqsSdk{failNth: 2}, QueueName: "q", Timeout: 5}
+
+	_, err := w.SendMsgWithRetry("a", 3)
+	s.Nil(err)
+
+	_, err = w.SendMsgBatch([]string{"a", "b", "c"})
+	s.Nil(err)
+
+	stats := w.Stats()
+	s.Equal(int64(1+2), stats.MessagesSent)
+	s.Equal(int64(1), stats.Batches)
+	s.Equal(int64(0), stats.Retries)
+	s.Equal(int64(1), stats.Failures)
+	s.Equal(int64(2), stats.Calls)
+
+	// the 2nd SendMessage fails, the 3rd succeeds.
+	_, err = w.SendMsgWithRetry("b", 3)
+	s.Nil(err)
+
+	stats = w.Stats()
+	s.Equal(int64(1), stats.Retries)
+	s.Equal(int64(2), stats.Failures)
+	s.Equal(int64(4), stats.MessagesSent)
+}
+
+func (s *SqsStatsSuite) Test_02_slowAndEmitter() {
+	var got []types.MetricDatum
+
+	emitter := newMetricEmitter("producers", time.Hour, func(_ string, data []types.MetricDatum) error {
+		got = append(got, data...)
+		return nil
+	})
+
+	w := &SqsClient{sdk: &stubSqsSdk{delay: 20 * time.Millisecond}, QueueName: "q", Timeout: 5}
+	w.SetSlowSendThreshold(10 * time.Millisecond)
+	w.SetMetricEmitter(emitter)
+
+	_, err := w.SendMsg("a")
+	s.Nil(err)
+
+	stats := w.Stats()
+	s.Equal(int64(1), stats.SlowSends)
+	s.GreaterOrEqual(stats.AverageLatency, 20*time.Millisecond)
+
+	s.Nil(emitter.Close())
+	s.Len(got, 3)
+}
@@ -0,0 +1,237 @@
+package xaws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidMessage = errors.New("invalid message")
+
+// MessageValidator checks an outgoing message body, it returns the failures, nil if message is valid.
+type MessageValidator func(message string) []string
+
+// ValidationError is returned when a message is rejected by the validator of SqsClient,
+// errors.Is(err, ErrInvalidMessage) is true.
+type ValidationError struct {
+	// Index is the position of the message in a batch, 0 for SendMsg.
+	Index    int
+	Failures []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s #%d: %s", ErrInvalidMessage, e.Index, strings.Join(e.Failures, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidMessage
+}
+
+// SetValidator sets the validator every message is checked against before it is sent,
+// nil disables validation.
+//
+// Usage:
+//
+//	v, err := JSONSchemaValidator([]byte(`{"type":"object","required":["id"]}`))
+//	w.SetValidator(v)
+//	_, err = w.SendMsg(`{"name":"x"}`) // *ValidationError: $: missing required property "id"
+func (w *SqsClient) SetValidator(fn MessageValidator) {
+	w.validator = fn
+}
+
+// validate returns a *ValidationError if messages[i] is rejected by the validator, errors of all messages are joined.
+func (w *SqsClient) validate(messages ...string) error {
+	if w.validator == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for i, message := range messages {
+		if failures := w.validator(message); len(failures) > 0 {
+			errs = append(errs, &ValidationError{Index: i, Failures: failures})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// JSONSchemaValidator returns a validator checking messages are JSON documents matching schema.
+//
+// Only a subset of JSON Schema is supported: type, enum, const, required, properties,
+// additionalProperties (boolean), items, minLength, maxLength, pattern, minimum, maximum,
+// minItems and maxItems, other keywords are ignored.
+func JSONSchemaValidator(schema []byte) (MessageValidator, error) {
+	var root map[string]any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return func(message string) []string {
+		var doc any
+		if err := json.Unmarshal([]byte(message), &doc); err != nil {
+			return []string{"not a json document: " + err.Error()}
+		}
+
+		return validateSchema(root, doc, "$")
+	}, nil
+}
+
+func validateSchema(schema map[string]any, value any, path string) []string {
+	var failures []string
+
+	fail := func(format string, args ...any) {
+		failures = append(failures, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if t, ok := schema["type"]; ok && !matchSchemaType(t, value) {
+		fail("expected type %v, got %s", t, jsonType(value))
+		return failures
+	}
+
+	if enum, ok := schema["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, value) }) {
+		fail("value not in enum %v", enum)
+	}
+
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		fail("value must be %v", c)
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := float64(utf8.RuneCountInString(v))
+		if limit, ok := schema["minLength"].(float64); ok && n < limit {
+			fail("length %v is less than %v", n, limit)
+		}
+
+		if limit, ok := schema["maxLength"].(float64); ok && n > limit {
+			fail("length %v is greater than %v", n, limit)
+		}
+
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				fail("invalid pattern %q: %v", pattern, err)
+			} else if !re.MatchString(v) {
+				fail("%q does not match pattern %q", v, pattern)
+			}
+		}
+	case float64:
+		if limit, ok := schema["minimum"].(float64); ok && v < limit {
+			fail("%v is less than minimum %v", v, limit)
+		}
+
+		if limit, ok := schema["maximum"].(float64); ok && v > limit {
+			fail("%v is greater than maximum %v", v, limit)
+		}
+	case []any:
+		if limit, ok := schema["minItems"].(float64); ok && float64(len(v)) < limit {
+			fail("%d items, less than %v", len(v), limit)
+		}
+
+		if limit, ok := schema["maxItems"].(float64); ok && float64(len(v)) > limit {
+			fail("%d items, more than %v", len(v), limit)
+		}
+
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				failures = append(failures, validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]any:
+		failures = append(failures, validateObject(schema, v, path)...)
+	}
+
+	return failures
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string) []string {
+	var failures []string
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				failures = append(failures, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"].(bool)
+
+	// sorted to report failures in a stable order.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		sub, ok := props[k].(map[string]any)
+		if !ok {
+			if hasAdditional && !additional {
+				failures = append(failures, fmt.Sprintf("%s: unexpected property %q", path, k))
+			}
+
+			continue
+		}
+
+		failures = append(failures, validateSchema(sub, obj[k], path+"."+k)...)
+	}
+
+	return failures
+}
+
+// matchSchemaType reports whether value is of type t, a type name or a list of them.
+func matchSchemaType(t any, value any) bool {
+	switch t := t.(type) {
+	case string:
+		actual := jsonType(value)
+		if t == "number" && actual == "integer" {
+			return true
+		}
+
+		return t == actual
+	case []any:
+		return slices.ContainsFunc(t, func(e any) bool { return matchSchemaType(e, value) })
+	default:
+		return true
+	}
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func jsonEqual(a, b any) bool {
+	ra, errA := json.Marshal(a)
+	rb, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && string(ra) == string(rb)
+}
@@ -0,0 +1,123 @@
+package xaws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/suite"
+)
+
+// batchRecorderSdk records the entries of each SendMessageBatch request, all entries succeed.
+type batchRecorderSdk struct {
+	SqsSdkClient
+
+	requests [][]sqstypes.SendMessageBatchRequestEntry
+}
+
+func (c *batchRecorderSdk) SendMessageBatch(_ context.Context, in *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	c.requests = append(c.requests, in.Entries)
+
+	out := &sqs.SendMessageBatchOutput{}
+	for _, e := range in.Entries {
+		out.Successful = append(out.Successful, sqstypes.SendMessageBatchResultEntry{Id: e.Id})
+	}
+
+	return out, nil
+}
+
+type ValidateSuite struct {
+	suite.Suite
+}
+
+func TestValidate(t *testing.T) {
+	suite.Run(t, new(ValidateSuite))
+}
+
+const testMessageSchema = `{
+	"type": "object",
+	"required": ["id", "kind"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"kind": {"enum": ["job", "event"]},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z]+$"}}
+	}
+}`
+
+func (s *ValidateSuite) Test_01_schema() {
+	v, err := JSONSchemaValidator([]byte(testMessageSchema))
+	s.Require().Nil(err)
+
+	s.Empty(v(`{"id": 1, "kind": "job", "tags": ["a"]}`))
+
+	s.Equal([]string{
+		`$: missing required property "kind"`,
+		`$.id: 0 is less than minimum 1`,
+		`$.tags: 3 items, more than 2`,
+		`$.tags[1]: "B" does not match pattern "^[a-z]+$"`,
+		`$: unexpected property "x"`,
+	}, v(`{"id": 0, "tags": ["a", "B", "c"], "x": 1}`))
+
+	s.Equal([]string{`$.id: expected type integer, got number`}, v(`{"id": 1.5, "kind": "job"}`))
+	s.Len(v(`not json`), 1)
+
+	_, err = JSONSchemaValidator([]byte(`{`))
+	s.Error(err)
+}
+
+func (s *ValidateSuite) Test_02_send() {
+	v, err := JSONSchemaValidator([]byte(testMessageSchema))
+	s.Require().Nil(err)
+
+	// client is never called, invalid messages are rejected before sending.
+	w := &SqsClient{}
+	w.SetValidator(v)
+
+	_, err = w.SendMsg(`{"id": 1}`)
+	s.ErrorIs(err, ErrInvalidMessage)
+
+	_, err = w.SendMsgBatch([]string{`{"id": 1, "kind": "job"}`, `{"kind": "job"}`})
+
+	var verr *ValidationError
+	s.True(errors.As(err, &verr))
+	s.Equal(1, verr.Index)
+	s.Equal([]string{`$: missing required property "id"`}, verr.Failures)
+}
+
+func (s *ValidateSuite) Test_03_batch() {
+	sdk := &batchRecorderSdk{}
+	w := &SqsClient{sdk: sdk, QueueName: "q"}
+
+	_, err := w.SendMsgBatch([]string{"a", ""})
+	s.ErrorIs(err, ErrEmptyMessageBody)
+
+	_, err = w.SendMsgBatch([]string{"a", "\xff"})
+	s.ErrorIs(err, ErrInvalidUTF8)
+
+	_, err = w.SendMsgBatch([]string{"a", strings.Repeat("a", _maxMessageSize+1)})
+	s.ErrorIs(err, ErrMessageTooLong)
+	s.Empty(sdk.requests)
+
+	// 3 messages of 100KB don't fit in one request, 12 small ones exceed 10 entries.
+	large := strings.Repeat("a", 100*1024)
+	messages := []string{large, large, large}
+	for range 12 {
+		messages = append(messages, "small")
+	}
+
+	out, err := w.SendMsgBatch(messages)
+	s.Nil(err)
+	s.Len(out.Successful, len(messages))
+
+	var sizes []int
+	for _, req := range sdk.requests {
+		sizes = append(sizes, len(req))
+	}
+
+	s.Equal([]int{2, 10, 3}, sizes)
+	s.Equal("15", *out.Successful[14].Id)
+}
@@ -0,0 +1,81 @@
+package xaws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type StsWrapper struct {
+	client *sts.Client
+	cfg    aws.Config
+
+	logger Logger
+}
+
+func NewStsWrapper(cfg aws.Config, opts ...ClientOptFunc) *StsWrapper {
+	opt := newClientOpts(opts...)
+	cfg = opt.apply(cfg)
+
+	return &StsWrapper{
+		client: sts.NewFromConfig(cfg),
+		cfg:    cfg,
+		logger: opt.logger,
+	}
+}
+
+func NewStsWrapperWithDefaultConfig(opts ...ClientOptFunc) (*StsWrapper, error) {
+	cfg, err := loadDefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStsWrapper(cfg, opts...), nil
+}
+
+// SetLogger sets the logger of wrapper, nil falls back to the default logger.
+func (w *StsWrapper) SetLogger(l Logger) {
+	w.logger = l
+}
+
+func (w *StsWrapper) log() Logger {
+	return orDefaultLogger(w.logger)
+}
+
+// CallerIdentity is the identity whose credentials call aws.
+type CallerIdentity struct {
+	Account string
+	Arn     string
+	UserID  string
+}
+
+// WhoAmI returns the identity of the credentials of wrapper, it needs no permission.
+//
+// Usage:
+//
+//	id, err := w.WhoAmI()
+//	logger.Info("running as", "account", id.Account, "arn", id.Arn)
+func (w *StsWrapper) WhoAmI() (*CallerIdentity, error) {
+	output, err := w.client.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot get caller identity: %w", err)
+	}
+
+	return &CallerIdentity{
+		Account: aws.ToString(output.Account),
+		Arn:     aws.ToString(output.Arn),
+		UserID:  aws.ToString(output.UserId),
+	}, nil
+}
+
+// AssumeRole returns a config with the credentials of roleArn, assumed with the config of wrapper,
+// see NewAwsConfigWithAssumeRole for opts, e.g. WithSessionPolicy.
+func (w *StsWrapper) AssumeRole(roleArn, sessionName string, opts ...AssumeRoleOptFunc) (aws.Config, error) {
+	opts = append([]AssumeRoleOptFunc{WithBaseConfig(w.cfg)}, opts...)
+
+	w.log().Debug("assuming role", "role", roleArn, "session", sessionName)
+
+	return NewAwsConfigWithAssumeRole(roleArn, sessionName, "", opts...)
+}
@@ -0,0 +1,144 @@
+package xaws
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	_instrumentationName = "github.com/coghost/xaws"
+)
+
+// resourceFields maps the input field naming the resource of a call to its span attribute.
+var resourceFields = map[string]attribute.Key{
+	"Bucket":       "aws.s3.bucket",
+	"QueueUrl":     "aws.sqs.queue_url",
+	"TableName":    "aws.dynamodb.table_name",
+	"FunctionName": "aws.lambda.function_name",
+	"SecretId":     "aws.secretsmanager.secret_id",
+}
+
+// telemetry traces and measures aws calls, see WithTelemetry.
+type telemetry struct {
+	tracer   trace.Tracer
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) *telemetry {
+	if mp == nil {
+		mp = noop.NewMeterProvider()
+	}
+
+	t := &telemetry{}
+
+	if tp != nil {
+		t.tracer = tp.Tracer(_instrumentationName)
+	}
+
+	meter := mp.Meter(_instrumentationName)
+
+	calls, err := meter.Int64Counter("xaws.aws.calls", metric.WithDescription("number of aws api calls"))
+	if err != nil {
+		DefaultLogger().Warn("cannot create calls counter", "error", err)
+
+		calls, _ = noop.NewMeterProvider().Meter(_instrumentationName).Int64Counter("xaws.aws.calls")
+	}
+
+	duration, err := meter.Float64Histogram("xaws.aws.duration",
+		metric.WithDescription("latency of aws api calls"), metric.WithUnit("ms"))
+	if err != nil {
+		DefaultLogger().Warn("cannot create duration histogram", "error", err)
+
+		duration, _ = noop.NewMeterProvider().Meter(_instrumentationName).Float64Histogram("xaws.aws.duration")
+	}
+
+	t.calls = calls
+	t.duration = duration
+
+	return t
+}
+
+// apply returns a copy of cfg whose clients call handle around every aws call.
+func (t *telemetry) apply(cfg aws.Config) aws.Config {
+	cfg = cfg.Copy()
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// After: the service metadata is set by an earlier initialize middleware.
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("xaws.telemetry", t.handle), middleware.After)
+	})
+
+	return cfg
+}
+
+func (t *telemetry) handle(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	service := awsmiddleware.GetServiceID(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "aws-api"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", operation),
+	}
+
+	var span trace.Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.Start(ctx, service+"."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(resourceAttributes(in.Parameters)...),
+		)
+		defer span.End()
+	}
+
+	start := time.Now()
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	if span != nil && err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	attrs = append(attrs, attribute.Bool("error", err != nil))
+	t.calls.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+
+	return out, metadata, err
+}
+
+// resourceAttributes extracts the resource names in resourceFields from input.
+func resourceAttributes(input any) []attribute.KeyValue {
+	v := reflect.ValueOf(input)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+
+	for field, key := range resourceFields {
+		f := v.FieldByName(field)
+		if !f.IsValid() || f.Kind() != reflect.Pointer || f.IsNil() || f.Elem().Kind() != reflect.String {
+			continue
+		}
+
+		attrs = append(attrs, key.String(f.Elem().String()))
+	}
+
+	return attrs
+}
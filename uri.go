@@ -0,0 +1,151 @@
+package xaws
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+var ErrInvalidURI = errors.New("invalid uri")
+
+// S3URI is the location of an object, Key is empty for a bucket.
+// Bucket is the access point arn for an access point, which the sdk accepts as bucket.
+type S3URI struct {
+	Bucket string
+	Key    string
+}
+
+func (u S3URI) String() string {
+	return "s3://" + u.Bucket + "/" + u.Key
+}
+
+// ParseS3URI parses "s3://bucket/key", an s3 arn "arn:aws:s3:::bucket/key"
+// or an access point arn "arn:aws:s3:region:account:accesspoint/name/object/key",
+// the key is kept as is, even if it starts with the bucket name.
+// Other arns with a region or account, e.g. of outposts, are rejected.
+func ParseS3URI(uri string) (S3URI, error) {
+	var rest string
+
+	switch {
+	case strings.HasPrefix(uri, "s3://"):
+		rest = strings.TrimPrefix(uri, "s3://")
+	case arn.IsARN(uri):
+		a, err := ParseARN(uri)
+		if err != nil {
+			return S3URI{}, err
+		}
+
+		if a.Service != "s3" {
+			return S3URI{}, fmt.Errorf("%w: %s is not an s3 arn", ErrInvalidURI, uri)
+		}
+
+		if a.Region != "" || a.AccountID != "" {
+			return parseAccessPointURI(uri, a)
+		}
+
+		rest = a.Resource
+	default:
+		return S3URI{}, fmt.Errorf("%w: %s", ErrInvalidURI, uri)
+	}
+
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return S3URI{}, fmt.Errorf("%w: %s has no bucket", ErrInvalidURI, uri)
+	}
+
+	return S3URI{Bucket: bucket, Key: key}, nil
+}
+
+// parseAccessPointURI parses the access point arn a, the key is after "/object/".
+func parseAccessPointURI(uri string, a ARN) (S3URI, error) {
+	name, rest, _ := strings.Cut(a.ResourceName(), "/")
+	if a.ResourceType() != "accesspoint" || name == "" || a.Region == "" || a.AccountID == "" {
+		return S3URI{}, fmt.Errorf("%w: %s is not an s3 access point arn", ErrInvalidURI, uri)
+	}
+
+	key, ok := strings.CutPrefix(rest, "object/")
+	if !ok && rest != "" {
+		return S3URI{}, fmt.Errorf("%w: %s has no object in access point", ErrInvalidURI, uri)
+	}
+
+	a.Resource = "accesspoint/" + name
+
+	return S3URI{Bucket: a.String(), Key: key}, nil
+}
+
+// ARN is a parsed arn, e.g. "arn:aws:sqs:us-east-1:123456789012:my-queue".
+type ARN struct {
+	Partition string
+	Service   string
+	Region    string
+	AccountID string
+	// Resource is everything after the account id, e.g. "function:my-func:1" or "role/deployer".
+	Resource string
+}
+
+func (a ARN) String() string {
+	return arn.ARN{
+		Partition: a.Partition,
+		Service:   a.Service,
+		Region:    a.Region,
+		AccountID: a.AccountID,
+		Resource:  a.Resource,
+	}.String()
+}
+
+// ResourceType returns the part of Resource before the first "/" or ":",
+// empty if Resource has neither, e.g. "function" of "function:my-func".
+func (a ARN) ResourceType() string {
+	i := strings.IndexAny(a.Resource, "/:")
+	if i < 0 {
+		return ""
+	}
+
+	return a.Resource[:i]
+}
+
+// ResourceName returns the part of Resource after the resource type,
+// e.g. "my-func:1" of "function:my-func:1" and "my-queue" of "my-queue".
+func (a ARN) ResourceName() string {
+	i := strings.IndexAny(a.Resource, "/:")
+	if i < 0 {
+		return a.Resource
+	}
+
+	return a.Resource[i+1:]
+}
+
+// ParseARN parses s into its sections.
+func ParseARN(s string) (ARN, error) {
+	a, err := arn.Parse(s)
+	if err != nil {
+		return ARN{}, fmt.Errorf("%w: %w", ErrInvalidURI, err)
+	}
+
+	return ARN{
+		Partition: a.Partition,
+		Service:   a.Service,
+		Region:    a.Region,
+		AccountID: a.AccountID,
+		Resource:  a.Resource,
+	}, nil
+}
+
+// targetArnMatches reports whether targetArn is arn, or a version or alias of lambda function arn,
+// e.g. "arn:aws:lambda:us-east-1:123456789012:function:report:live" matches the function arn.
+func targetArnMatches(targetArn, arn string) bool {
+	if targetArn == arn {
+		return true
+	}
+
+	a, err := ParseARN(arn)
+	if err != nil || a.Service != "lambda" || a.ResourceType() != "function" {
+		return false
+	}
+
+	qualifier, ok := strings.CutPrefix(targetArn, arn+":")
+
+	return ok && qualifier != "" && !strings.Contains(qualifier, ":")
+}
@@ -0,0 +1,83 @@
+package xaws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type URISuite struct {
+	suite.Suite
+}
+
+func TestURI(t *testing.T) {
+	suite.Run(t, new(URISuite))
+}
+
+func (s *URISuite) Test_01_s3URI() {
+	tests := []struct {
+		uri    string
+		bucket string
+		key    string
+	}{
+		{"s3://data/a/b.json", "data", "a/b.json"},
+		{"s3://data/data/b.json", "data", "data/b.json"},
+		{"s3://data", "data", ""},
+		{"arn:aws:s3:::data/a.txt", "data", "a.txt"},
+		{
+			"arn:aws:s3:us-east-1:123456789012:accesspoint/reports/object/2024/a.json",
+			"arn:aws:s3:us-east-1:123456789012:accesspoint/reports", "2024/a.json",
+		},
+		{"arn:aws:s3:us-east-1:123456789012:accesspoint/reports", "arn:aws:s3:us-east-1:123456789012:accesspoint/reports", ""},
+	}
+
+	for _, tt := range tests {
+		uri, err := ParseS3URI(tt.uri)
+		s.Nil(err, tt.uri)
+		s.Equal(tt.bucket, uri.Bucket, tt.uri)
+		s.Equal(tt.key, uri.Key, tt.uri)
+	}
+
+	for _, bad := range []string{
+		"data/a.txt", "s3:///a.txt", "arn:aws:sqs:us-east-1:123456789012:q",
+		"arn:aws:s3:us-east-1:123456789012:accesspoint/reports/2024/a.json",
+		"arn:aws:s3-outposts:us-east-1:123456789012:outpost/op-1/accesspoint/ap/object/a.json",
+		"arn:aws:s3:us-east-1:123456789012:job/1",
+	} {
+		_, err := ParseS3URI(bad)
+		s.ErrorIs(err, ErrInvalidURI, bad)
+	}
+}
+
+func (s *URISuite) Test_02_arn() {
+	a, err := ParseARN("arn:aws:lambda:us-east-1:123456789012:function:my-func:1")
+	s.Nil(err)
+	s.Equal("lambda", a.Service)
+	s.Equal("us-east-1", a.Region)
+	s.Equal("123456789012", a.AccountID)
+	s.Equal("function", a.ResourceType())
+	s.Equal("my-func:1", a.ResourceName())
+	s.Equal("arn:aws:lambda:us-east-1:123456789012:function:my-func:1", a.String())
+
+	a, err = ParseARN("arn:aws:sqs:us-east-1:123456789012:my-queue")
+	s.Nil(err)
+	s.Equal("", a.ResourceType())
+	s.Equal("my-queue", a.ResourceName())
+
+	_, err = ParseARN("not-an-arn")
+	s.ErrorIs(err, ErrInvalidURI)
+}
+
+func (s *URISuite) Test_03_targetArnMatches() {
+	fn := "arn:aws:lambda:us-east-1:123456789012:function:report"
+
+	s.True(targetArnMatches(fn, fn))
+	s.True(targetArnMatches(fn+":live", fn))
+	s.True(targetArnMatches(fn+":3", fn))
+	s.False(targetArnMatches(fn+"-v2", fn))
+	s.False(targetArnMatches(fn, fn+":live"))
+
+	queue := "arn:aws:sqs:us-east-1:123456789012:jobs"
+	s.True(targetArnMatches(queue, queue))
+	s.False(targetArnMatches(queue+":x", queue))
+}